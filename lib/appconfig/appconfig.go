@@ -0,0 +1,219 @@
+// Package appconfig loads the configuration of all binaries from layered sources.
+// The keys are the env-tags of the config structs, the values are taken in this order,
+// a later source overrides an earlier one:
+//
+//  1. the envDefault-tags
+//  2. the config file given with --config or CONFIG_FILE_PATH (YAML, JSON or TOML)
+//  3. the environment
+//  4. the command line flags like --postgres-host for POSTGRES_HOST
+package appconfig
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FileEnv is the environment variable with the path of the config file, the --config flag takes precedence
+const FileEnv = "CONFIG_FILE_PATH"
+
+const (
+	sourceDefault = "default"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+)
+
+// Loader loads the configuration of one binary. NewLoader creates one for the current process.
+type Loader struct {
+	// Name of the binary in the usage of the flags
+	Name string
+	// DefaultFile is read, if no file was given. It may be missing.
+	DefaultFile string
+	Args        []string
+	Environ     []string
+	// Output receives the usage and the configuration printed with --print-config
+	Output io.Writer
+	// Exit is called after the usage or the configuration were printed
+	Exit func(code int)
+
+	file string
+}
+
+func NewLoader(name string) *Loader {
+	return &Loader{
+		Name:    name,
+		Args:    os.Args[1:],
+		Environ: os.Environ(),
+		Output:  os.Stdout,
+		Exit:    os.Exit,
+	}
+}
+
+// Load fills the config, which must be a pointer to a struct, for the current process.
+// With --print-config the configuration is printed with redacted secrets and the process exits.
+func Load(name string, config any) error {
+	return NewLoader(name).Load(config)
+}
+
+// entry is the merged value of a key and where it came from
+type entry struct {
+	value  string
+	source string
+	tree   any
+}
+
+func (loader *Loader) Load(config any) error {
+	value := reflect.ValueOf(config)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return errors.New("appconfig: config must be a pointer to a struct")
+	}
+
+	var fields []field
+	var sections []section
+	collectFields(value.Elem(), "", &fields, &sections)
+
+	flagValues := map[string]string{}
+	flags := flag.NewFlagSet(loader.Name, flag.ContinueOnError)
+	flags.SetOutput(loader.Output)
+	configFile := flags.String("config", "", "path of the YAML-, JSON- or TOML-config file (env "+FileEnv+")")
+	printConfig := flags.Bool("print-config", false, "print the configuration with redacted secrets and exit")
+	for _, f := range fields {
+		usage := "env " + f.key
+		if f.hasDefault {
+			usage += fmt.Sprintf(" (default %q)", f.defaultValue)
+		}
+		flags.Var(&flagValue{f.key, f.isBool(), flagValues}, f.flagName(), usage)
+	}
+	if err := flags.Parse(loader.Args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			loader.Exit(0)
+			return nil
+		}
+		return err
+	}
+
+	environ := map[string]string{}
+	for _, variable := range loader.Environ {
+		if key, value, ok := strings.Cut(variable, "="); ok {
+			environ[key] = value
+		}
+	}
+
+	entries := map[string]entry{}
+	for _, f := range fields {
+		if f.hasDefault {
+			entries[f.key] = entry{f.defaultValue, sourceDefault, nil}
+		}
+	}
+
+	var problems []string
+	fileValues, err := loader.readFile(*configFile, environ)
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, f := range fields {
+		known[f.key] = true
+	}
+	for key, fileValue := range fileValues {
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("unknown key %q in the config file", fileValue.path))
+			continue
+		}
+		entries[key] = entry{fileValue.value, sourceFile, fileValue.tree}
+	}
+	sort.Strings(problems)
+
+	for _, f := range fields {
+		if value, ok := environ[f.key]; ok {
+			entries[f.key] = entry{value, sourceEnv, nil}
+		}
+		if value, ok := flagValues[f.key]; ok {
+			entries[f.key] = entry{value, sourceFlag, nil}
+		}
+	}
+
+	// the other variables are kept for options of the env-package like expand
+	environment := environ
+	for key, entry := range entries {
+		environment[key] = entry.value
+	}
+
+	problems = append(problems, parse(config, fields, sections, environment)...)
+
+	if *printConfig {
+		if err := printEntries(loader.Output, fields, entries); err != nil {
+			return err
+		}
+		loader.Exit(0)
+		return nil
+	}
+
+	if len(problems) > 0 {
+		return &Error{problems}
+	}
+	return nil
+}
+
+// File returns the path of the config file read by the last Load or "" if there was none,
+// e.g. to watch it for changes
+func (loader *Loader) File() string {
+	return loader.file
+}
+
+// readFile reads the config file of the flag, the environment or the default file in this order
+func (loader *Loader) readFile(path string, environ map[string]string) (map[string]fileValue, error) {
+	loader.file = ""
+	if path == "" {
+		path = environ[FileEnv]
+	}
+	if path == "" {
+		if loader.DefaultFile == "" {
+			return nil, nil
+		}
+		values, err := readFile(loader.DefaultFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err == nil {
+			loader.file = loader.DefaultFile
+		}
+		return values, err
+	}
+
+	values, err := readFile(path)
+	if err == nil {
+		loader.file = path
+	}
+	return values, err
+}
+
+// flagValue stores the value of a flag as string, it is parsed with the other sources
+type flagValue struct {
+	key    string
+	isBool bool
+	values map[string]string
+}
+
+func (value *flagValue) String() string {
+	if value == nil || value.values == nil {
+		return ""
+	}
+	return value.values[value.key]
+}
+
+func (value *flagValue) Set(raw string) error {
+	value.values[value.key] = raw
+	return nil
+}
+
+// IsBoolFlag allows --grpc-communication without a value for bool fields
+func (value *flagValue) IsBoolFlag() bool {
+	return value.isBool
+}
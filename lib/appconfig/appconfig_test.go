@@ -0,0 +1,272 @@
+package appconfig
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type databaseConfig struct {
+	Host     string `env:"HOST,notEmpty"`
+	Port     int    `env:"PORT" envDefault:"5432" validate:"min=1,max=65535"`
+	Password string `env:"PASSWORD" secret:"true"`
+}
+
+type keyConfig struct {
+	Key     string `env:"KEY" secret:"true"`
+	KeyPath string `env:"KEY_PATH"`
+}
+
+func (config keyConfig) Validate() error {
+	if config.Key == "" && config.KeyPath == "" {
+		return errors.New("either KEY or KEY_PATH must be set")
+	}
+	return nil
+}
+
+type testConfig struct {
+	Database  databaseConfig `envPrefix:"POSTGRES_"`
+	Jwt       keyConfig      `envPrefix:"JWT_"`
+	Port      uint16         `env:"PORT" envDefault:"8080"`
+	Active    bool           `env:"ACTIVE" envDefault:"false"`
+	Timeout   time.Duration  `env:"TIMEOUT" envDefault:"30s"`
+	Endpoint  url.URL        `env:"ENDPOINT"`
+	Origins   []string       `env:"ORIGINS" envSeparator:","`
+	Shutdown  time.Duration  `env:"SHUTDOWN_DELAY" envDefault:"0s"`
+	unchecked string
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestLoader(args []string, environ ...string) (*Loader, *bytes.Buffer, *int) {
+	output := &bytes.Buffer{}
+	exitCode := -1
+	return &Loader{
+		Name:    "test",
+		Args:    args,
+		Environ: environ,
+		Output:  output,
+		Exit:    func(code int) { exitCode = code },
+	}, output, &exitCode
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("should use the defaults and the environment", func(t *testing.T) {
+		// given
+		loader, _, _ := newTestLoader(nil, "POSTGRES_HOST=db", "JWT_KEY_PATH=key.pem", "ORIGINS=a,b", "ENDPOINT=http://user-service:8080")
+		config := testConfig{}
+
+		// when
+		err := loader.Load(&config)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "db", config.Database.Host)
+		assert.Equal(t, 5432, config.Database.Port)
+		assert.Equal(t, uint16(8080), config.Port)
+		assert.Equal(t, 30*time.Second, config.Timeout)
+		assert.Equal(t, "user-service:8080", config.Endpoint.Host)
+		assert.Equal(t, []string{"a", "b"}, config.Origins)
+	})
+
+	t.Run("should override the file with the environment and the environment with the flags", func(t *testing.T) {
+		// given
+		file := writeFile(t, "config.yaml", `
+port: 3000
+active: true
+origins: [a, b]
+postgres:
+  host: file-db
+  port: 5433
+jwt:
+  key_path: file.pem
+`)
+		loader, _, _ := newTestLoader([]string{"--config", file, "--postgres-port=5434"}, "POSTGRES_HOST=env-db", "POSTGRES_PORT=6000")
+		config := testConfig{}
+
+		// when
+		err := loader.Load(&config)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(3000), config.Port)
+		assert.True(t, config.Active)
+		assert.Equal(t, []string{"a", "b"}, config.Origins)
+		assert.Equal(t, "env-db", config.Database.Host)
+		assert.Equal(t, 5434, config.Database.Port)
+		assert.Equal(t, "file.pem", config.Jwt.KeyPath)
+	})
+
+	t.Run("should read the file of the environment or the default file", func(t *testing.T) {
+		// given
+		file := writeFile(t, "config.toml", `
+port = 3000
+
+[postgres]
+host = "toml-db"
+
+[jwt]
+key = "secret"
+`)
+		loader, _, _ := newTestLoader(nil, FileEnv+"="+file)
+		config := testConfig{}
+
+		defaultLoader, _, _ := newTestLoader(nil)
+		defaultLoader.DefaultFile = file
+		defaultConfig := testConfig{}
+
+		// when
+		err := loader.Load(&config)
+		defaultErr := defaultLoader.Load(&defaultConfig)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "toml-db", config.Database.Host)
+		assert.Equal(t, uint16(3000), config.Port)
+		assert.Equal(t, file, loader.File())
+		assert.NoError(t, defaultErr)
+		assert.Equal(t, config, defaultConfig)
+		assert.Equal(t, file, defaultLoader.File())
+	})
+
+	t.Run("should ignore a missing default file but not a missing file", func(t *testing.T) {
+		// given
+		defaultLoader, _, _ := newTestLoader(nil, "POSTGRES_HOST=db", "JWT_KEY=key")
+		defaultLoader.DefaultFile = filepath.Join(t.TempDir(), "config.yaml")
+
+		loader, _, _ := newTestLoader([]string{"--config", defaultLoader.DefaultFile}, "POSTGRES_HOST=db", "JWT_KEY=key")
+
+		// when
+		defaultErr := defaultLoader.Load(&testConfig{})
+		err := loader.Load(&testConfig{})
+
+		// then
+		assert.NoError(t, defaultErr)
+		assert.Equal(t, "", defaultLoader.File())
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("should accept bool flags without a value", func(t *testing.T) {
+		// given
+		loader, _, _ := newTestLoader([]string{"--active"}, "POSTGRES_HOST=db", "JWT_KEY=key")
+		config := testConfig{}
+
+		// when
+		err := loader.Load(&config)
+
+		// then
+		assert.NoError(t, err)
+		assert.True(t, config.Active)
+	})
+
+	t.Run("should report all problems at once", func(t *testing.T) {
+		// given
+		file := writeFile(t, "config.yaml", "postgres:\n  hots: db\n")
+		loader, _, _ := newTestLoader([]string{"--config", file}, "PORT=abc", "TIMEOUT=1h")
+		config := testConfig{}
+
+		// when
+		err := loader.Load(&config)
+
+		// then
+		var configErr *Error
+		assert.ErrorAs(t, err, &configErr)
+		assert.Equal(t, []string{
+			`unknown key "postgres.hots" in the config file`,
+			`PORT: invalid value "abc": strconv.ParseUint: parsing "abc": invalid syntax`,
+			"POSTGRES_HOST is required",
+		}, configErr.Problems)
+		assert.Contains(t, err.Error(), "invalid configuration:\n\t")
+	})
+
+	t.Run("should check the rules of the values and the validators", func(t *testing.T) {
+		// given
+		loader, _, _ := newTestLoader(nil, "POSTGRES_HOST=db", "POSTGRES_PORT=0")
+		config := testConfig{}
+
+		// when
+		err := loader.Load(&config)
+
+		// then
+		var configErr *Error
+		assert.ErrorAs(t, err, &configErr)
+		assert.Equal(t, []string{
+			"POSTGRES_PORT must be at least 1",
+			"JWT_*: either KEY or KEY_PATH must be set",
+		}, configErr.Problems)
+	})
+
+	t.Run("should print the configuration with redacted secrets", func(t *testing.T) {
+		// given
+		file := writeFile(t, "config.yaml", "postgres:\n  host: db\n")
+		loader, output, exitCode := newTestLoader([]string{"--print-config", "--port", "3000"},
+			FileEnv+"="+file, "POSTGRES_PASSWORD=password", "JWT_KEY=private-key")
+
+		// when
+		err := loader.Load(&testConfig{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 0, *exitCode)
+		assert.Equal(t, `postgres:
+  host: db # file
+  port: 5432 # default
+  password: <redacted> # env
+jwt:
+  key: <redacted> # env
+port: 3000 # flag
+active: false # default
+timeout: 30s # default
+shutdown_delay: 0s # default
+`, output.String())
+		assert.NotContains(t, output.String(), "private-key")
+	})
+
+	t.Run("should print the usage of the flags", func(t *testing.T) {
+		// given
+		loader, output, exitCode := newTestLoader([]string{"-h"})
+
+		// when
+		err := loader.Load(&testConfig{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 0, *exitCode)
+		assert.Contains(t, output.String(), "-postgres-host")
+		assert.Contains(t, output.String(), "env POSTGRES_PORT (default \"5432\")")
+	})
+
+	t.Run("should reject unknown flags", func(t *testing.T) {
+		// given
+		loader, _, _ := newTestLoader([]string{"--unknown"})
+
+		// when
+		err := loader.Load(&testConfig{})
+
+		// then
+		assert.ErrorContains(t, err, "flag provided but not defined: -unknown")
+	})
+
+	t.Run("should reject a config, which isn't a pointer to a struct", func(t *testing.T) {
+		// given
+		loader, _, _ := newTestLoader(nil)
+
+		// when
+		err := loader.Load(testConfig{})
+
+		// then
+		assert.Error(t, err)
+	})
+}
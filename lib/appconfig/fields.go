@@ -0,0 +1,80 @@
+package appconfig
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field is a value of the configuration, which is set by one key in all sources
+type field struct {
+	// key is the full name of the environment variable like POSTGRES_HOST
+	key string
+	// section is the env prefix in lower case like postgres, it nests the value in a printed config file
+	section string
+	// name is the own key in lower case like host
+	name         string
+	defaultValue string
+	hasDefault   bool
+	secret       bool
+	rules        string
+	structField  reflect.StructField
+	value        reflect.Value
+}
+
+// flagName is the name of the command line flag like postgres-host
+func (f field) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(f.key), "_", "-")
+}
+
+func (f field) isBool() bool {
+	return f.structField.Type.Kind() == reflect.Bool
+}
+
+// section is a nested struct of the configuration, which may validate itself
+type section struct {
+	prefix string
+	value  reflect.Value
+}
+
+// collectFields walks the struct like the env-package does: fields with an env-tag are values,
+// other structs are walked with the envPrefix-tag added to the prefix.
+func collectFields(value reflect.Value, prefix string, fields *[]field, sections *[]section) {
+	*sections = append(*sections, section{prefix, value})
+
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if tag, ok := structField.Tag.Lookup("env"); ok {
+			ownKey, _, _ := strings.Cut(tag, ",")
+			if ownKey == "" {
+				continue
+			}
+			defaultValue, hasDefault := structField.Tag.Lookup("envDefault")
+			*fields = append(*fields, field{
+				key:          prefix + ownKey,
+				section:      strings.ToLower(strings.TrimSuffix(prefix, "_")),
+				name:         strings.ToLower(ownKey),
+				defaultValue: defaultValue,
+				hasDefault:   hasDefault,
+				secret:       structField.Tag.Get("secret") == "true",
+				rules:        structField.Tag.Get("validate"),
+				structField:  structField,
+				value:        fieldValue,
+			})
+			continue
+		}
+
+		// like the env-package nil pointers aren't walked
+		if fieldValue.Kind() == reflect.Pointer && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Struct {
+			collectFields(fieldValue, prefix+structField.Tag.Get("envPrefix"), fields, sections)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileValue is a value of the config file flattened to the key of its environment variable
+type fileValue struct {
+	value string
+	// path is the nested key in the file like postgres.host, for error messages
+	path string
+	// tree is the decoded value, if it was a list or map, to print it as it was written
+	tree any
+}
+
+// readFile decodes a YAML-, JSON- or TOML-file depending on its extension and flattens it, so
+//
+//	postgres:
+//	  host: localhost
+//
+// becomes POSTGRES_HOST=localhost. Lists of scalars are joined with commas like in the environment,
+// other lists are encoded as JSON, which is parsed by the fields implementing encoding.TextUnmarshaler.
+func readFile(path string) (map[string]fileValue, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		err = yaml.Unmarshal(content, &tree)
+	case ".toml":
+		err = toml.Unmarshal(content, &tree)
+	default:
+		return nil, fmt.Errorf("%s: unsupported file type, use .yaml, .yml, .json or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := map[string]fileValue{}
+	if err := flatten(tree, "", "", values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(tree map[string]any, keyPrefix string, pathPrefix string, values map[string]fileValue) error {
+	// sorted, so the errors are deterministic
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := keyPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+		path := pathPrefix + name
+
+		switch value := tree[name].(type) {
+		case nil:
+		case map[string]any:
+			if err := flatten(value, key+"_", path+".", values); err != nil {
+				return err
+			}
+		case []any:
+			joined, ok := joinScalars(value)
+			if !ok {
+				encoded, err := json.Marshal(value)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				joined = string(encoded)
+			}
+			values[key] = fileValue{joined, path, value}
+		default:
+			values[key] = fileValue{fmt.Sprint(value), path, nil}
+		}
+	}
+	return nil
+}
+
+func joinScalars(list []any) (string, bool) {
+	items := make([]string, len(list))
+	for i, item := range list {
+		switch item.(type) {
+		case map[string]any, []any:
+			return "", false
+		}
+		items[i] = fmt.Sprint(item)
+	}
+	return strings.Join(items, ","), true
+}
@@ -0,0 +1,60 @@
+package appconfig
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "<redacted>"
+
+// printEntries writes the merged configuration as YAML, which can be used as config file.
+// Every value is commented with its source and secrets are redacted.
+func printEntries(w io.Writer, fields []field, entries map[string]entry) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{}
+
+	for _, f := range fields {
+		entry, ok := entries[f.key]
+		if !ok {
+			continue
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: f.name}
+		var value yaml.Node
+		switch {
+		case f.secret && entry.value != "":
+			value = yaml.Node{Kind: yaml.ScalarNode, Value: redacted}
+		case entry.tree != nil:
+			if err := value.Encode(entry.tree); err != nil {
+				return err
+			}
+		default:
+			value = yaml.Node{Kind: yaml.ScalarNode, Value: entry.value}
+		}
+		// yaml moves line comments of lists and maps to the following key
+		if value.Kind == yaml.ScalarNode {
+			value.LineComment = entry.source
+		} else {
+			key.HeadComment = entry.source
+		}
+
+		parent := root
+		if f.section != "" {
+			parent = sections[f.section]
+			if parent == nil {
+				parent = &yaml.Node{Kind: yaml.MappingNode}
+				sections[f.section] = parent
+				root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.section}, parent)
+			}
+		}
+		parent.Content = append(parent.Content, key, &value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
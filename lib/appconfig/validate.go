@@ -0,0 +1,122 @@
+package appconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/validation"
+	"github.com/caarlos0/env/v10"
+)
+
+// Validator is implemented by configs, which have rules spanning multiple values,
+// e.g. that either a key or the path to the key has to be set.
+type Validator interface {
+	Validate() error
+}
+
+// Error lists every problem of the configuration, so all of them can be fixed at once
+type Error struct {
+	Problems []string
+}
+
+func (err *Error) Error() string {
+	return "invalid configuration:\n\t" + strings.Join(err.Problems, "\n\t")
+}
+
+// parse fills the config from the merged sources and collects the problems of all values.
+// Every value is parsed on its own first, so a parse error can be reported with its key.
+func parse(config any, fields []field, sections []section, environment map[string]string) []string {
+	var problems []string
+	for _, f := range fields {
+		raw, ok := environment[f.key]
+		if !ok {
+			continue
+		}
+		if err := parseValue(f, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", f.key, err.Error()))
+		}
+	}
+
+	err := env.ParseWithOptions(config, env.Options{Environment: environment})
+	var aggregateErr env.AggregateError
+	if errors.As(err, &aggregateErr) {
+		for _, err := range aggregateErr.Errors {
+			var notSetErr env.EnvVarIsNotSetError
+			var emptyErr env.EmptyEnvVarError
+			var parseErr env.ParseError
+			switch {
+			case errors.As(err, &notSetErr):
+				problems = append(problems, notSetErr.Key+" is required")
+			case errors.As(err, &emptyErr):
+				if _, ok := environment[emptyErr.Key]; ok {
+					problems = append(problems, emptyErr.Key+" must not be empty")
+				} else {
+					problems = append(problems, emptyErr.Key+" is required")
+				}
+			case errors.As(err, &parseErr):
+				// already reported with the key by parseValue
+			default:
+				problems = append(problems, err.Error())
+			}
+		}
+	} else if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	// the rules only make sense for values, which could be parsed
+	if len(problems) > 0 {
+		return problems
+	}
+
+	for _, f := range fields {
+		if f.rules == "" {
+			continue
+		}
+		if message := validation.ValidateValue(f.value.Interface(), f.rules); message != "" {
+			problems = append(problems, f.key+" "+message)
+		}
+	}
+
+	for _, s := range sections {
+		validator, ok := s.value.Addr().Interface().(Validator)
+		if !ok {
+			continue
+		}
+		if err := validator.Validate(); err != nil {
+			if s.prefix == "" {
+				problems = append(problems, err.Error())
+			} else {
+				problems = append(problems, fmt.Sprintf("%s*: %s", s.prefix, err.Error()))
+			}
+		}
+	}
+
+	return problems
+}
+
+// parseValue parses the raw value into a struct with only this field, so the parsers of the env-package are used
+func parseValue(f field, raw string) error {
+	tag := `env:"VALUE"`
+	for _, name := range []string{"envSeparator", "envKeyValSeparator"} {
+		if option, ok := f.structField.Tag.Lookup(name); ok {
+			tag += fmt.Sprintf(" %s:%q", name, option)
+		}
+	}
+
+	single := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: f.structField.Type, Tag: reflect.StructTag(tag)},
+	}))
+
+	err := env.ParseWithOptions(single.Interface(), env.Options{Environment: map[string]string{"VALUE": raw}})
+	var aggregateErr env.AggregateError
+	if !errors.As(err, &aggregateErr) {
+		return err
+	}
+	var parseErr env.ParseError
+	if len(aggregateErr.Errors) == 1 && errors.As(aggregateErr.Errors[0], &parseErr) {
+		return fmt.Errorf("invalid value %q: %w", raw, parseErr.Err)
+	}
+	return err
+}
@@ -9,6 +9,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -38,16 +39,16 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // VerifyToken mocks base method.
-func (m *MockRepository) VerifyToken(token string) (uint64, error) {
+func (m *MockRepository) VerifyToken(ctx context.Context, token string) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyToken", token)
+	ret := m.ctrl.Call(m, "VerifyToken", ctx, token)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // VerifyToken indicates an expected call of VerifyToken.
-func (mr *MockRepositoryMockRecorder) VerifyToken(token any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) VerifyToken(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockRepository)(nil).VerifyToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockRepository)(nil).VerifyToken), ctx, token)
 }
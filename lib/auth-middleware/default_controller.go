@@ -5,7 +5,10 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 )
 
 type contextKey string
@@ -29,6 +32,7 @@ func NewDefaultController(
 func (ctrl *DefaultController) AuthenticationMiddleware(w http.ResponseWriter, r *http.Request, next router.Next) {
 	if !ctrl.authIsActive {
 		ctx := context.WithValue(r.Context(), AuthenticatedUserId, uint64(1))
+		logger.SetUserID(ctx, 1)
 		next(r.WithContext(ctx))
 		return
 	}
@@ -36,16 +40,17 @@ func (ctrl *DefaultController) AuthenticationMiddleware(w http.ResponseWriter, r
 	bearerToken := r.Header.Get("Authorization")
 	token, found := strings.CutPrefix(bearerToken, "Bearer ")
 	if !found {
-		http.Error(w, "There was no Token provided", http.StatusUnauthorized)
+		problem.Write(w, r, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_MISSING", "there was no token provided"))
 		return
 	}
 
-	userId, err := ctrl.authRepository.VerifyToken(token)
+	userId, err := ctrl.authRepository.VerifyToken(r.Context(), token)
 	if err != nil {
-		http.Error(w, "There was an Error while verifying you token", http.StatusUnauthorized)
+		problem.Write(w, r, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_INVALID", "there was an error while verifying your token"))
 		return
 	}
 
 	ctx := context.WithValue(r.Context(), AuthenticatedUserId, userId)
+	logger.SetUserID(ctx, userId)
 	next(r.WithContext(ctx))
 }
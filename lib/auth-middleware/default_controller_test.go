@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
 	"github.com/stretchr/testify/assert"
 
 	"go.uber.org/mock/gomock"
@@ -69,7 +70,7 @@ func TestDefaultController(t *testing.T) {
 
 				repository.
 					EXPECT().
-					VerifyToken("invalid-token").
+					VerifyToken(gomock.Any(), "invalid-token").
 					Return(uint64(0), errors.New("invalid token"))
 
 				// when
@@ -89,10 +90,11 @@ func TestDefaultController(t *testing.T) {
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest("GET", "/api/v1/books", nil)
 				r.Header.Set("Authorization", "Bearer invalid-token")
+				r = r.WithContext(logger.WithRequest(r.Context(), "my-id", ""))
 
 				repository.
 					EXPECT().
-					VerifyToken("invalid-token").
+					VerifyToken(gomock.Any(), "invalid-token").
 					Return(userId, nil)
 
 				// when
@@ -105,6 +107,8 @@ func TestDefaultController(t *testing.T) {
 				// then
 				assert.True(t, called)
 				assert.Equal(t, userId, r.Context().Value(AuthenticatedUserId))
+				loggedUserId, _ := logger.UserID(r.Context())
+				assert.Equal(t, userId, loggedUserId)
 				assert.Equal(t, http.StatusOK, w.Code)
 			})
 		})
@@ -17,12 +17,12 @@ func NewGRPCRepository(client proto.UserServiceClient) *GRPCRepository {
 	}
 }
 
-func (repo *GRPCRepository) VerifyToken(token string) (uint64, error) {
+func (repo *GRPCRepository) VerifyToken(ctx context.Context, token string) (uint64, error) {
 	req := &proto.ValidateTokenRequest{
 		Token: token,
 	}
 
-	res, err := repo.client.ValidateToken(context.Background(), req)
+	res, err := repo.client.ValidateToken(ctx, req)
 	if err != nil {
 		return 0, err
 	}
@@ -76,7 +76,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 			token := "invalid_token"
 
 			// when
-			userId, err := repository.VerifyToken(token)
+			userId, err := repository.VerifyToken(context.Background(), token)
 
 			// then
 			assert.Equal(t, uint64(0), userId)
@@ -85,7 +85,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 		t.Run("should return userId if token is valid", func(t *testing.T) {
 			// when
-			userId, err := repository.VerifyToken(valid_token)
+			userId, err := repository.VerifyToken(context.Background(), valid_token)
 
 			// then
 			assert.Equal(t, uint64(2), userId)
@@ -2,6 +2,7 @@ package auth_middleware
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -28,7 +29,7 @@ func NewHTTPRepository(authServiceURL *url.URL, client client.Client) *HTTPRepos
 	return &HTTPRepository{authServiceURL, client}
 }
 
-func (repo *HTTPRepository) VerifyToken(token string) (uint64, error) {
+func (repo *HTTPRepository) VerifyToken(ctx context.Context, token string) (uint64, error) {
 	host := repo.authServiceURL.String()
 	tokenBody := &VerifyTokenRequest{token}
 
@@ -37,7 +38,7 @@ func (repo *HTTPRepository) VerifyToken(token string) (uint64, error) {
 		return 0, err
 	}
 
-	req, err := http.NewRequest("POST", host, bytes.NewBuffer(reqBody))
+	req, err := client.NewRequest(ctx, "POST", host, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return 0, err
 	}
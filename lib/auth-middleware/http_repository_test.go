@@ -2,6 +2,7 @@ package auth_middleware
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -38,7 +39,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(nil, errors.New("error with request"))
 
 			// when
-			userId, err := repo.VerifyToken(token)
+			userId, err := repo.VerifyToken(context.Background(), token)
 
 			// then
 			assert.Error(t, err)
@@ -66,7 +67,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			userId, err := repo.VerifyToken(token)
+			userId, err := repo.VerifyToken(context.Background(), token)
 
 			// then
 			assert.Error(t, err)
@@ -94,7 +95,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			userId, err := repo.VerifyToken(token)
+			userId, err := repo.VerifyToken(context.Background(), token)
 
 			// then
 			assert.Error(t, err)
@@ -124,7 +125,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			userId, err := repo.VerifyToken(token)
+			userId, err := repo.VerifyToken(context.Background(), token)
 
 			// then
 			assert.Error(t, err)
@@ -154,7 +155,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			userId, err := repo.VerifyToken(token)
+			userId, err := repo.VerifyToken(context.Background(), token)
 
 			// then
 			assert.Error(t, err)
@@ -184,7 +185,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			userId, err := repo.VerifyToken(token)
+			userId, err := repo.VerifyToken(context.Background(), token)
 
 			// then
 			assert.NoError(t, err)
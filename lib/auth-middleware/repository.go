@@ -1,5 +1,7 @@
 package auth_middleware
 
+import "context"
+
 type Repository interface {
-	VerifyToken(token string) (uint64, error)
+	VerifyToken(ctx context.Context, token string) (uint64, error)
 }
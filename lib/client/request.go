@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TimeoutHeader carries the remaining time of the caller's deadline in milliseconds,
+// so the called service can cancel its work when the caller stops waiting.
+const TimeoutHeader = "X-Request-Timeout"
+
+// NewRequest creates a request bound to the context and sets the TimeoutHeader, if the context has a deadline.
+// The trace context and the request id of ctx are sent in the headers, so the called service continues the trace and request.
+func NewRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline).Milliseconds()
+		if remaining < 1 {
+			remaining = 1
+		}
+		req.Header.Set(TimeoutHeader, strconv.FormatInt(remaining, 10))
+	}
+
+	if id := logger.RequestID(ctx); id != "" {
+		req.Header.Set(logger.RequestIDHeader, id)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req, nil
+}
+
+// ParseTimeout reads the TimeoutHeader of the request.
+func ParseTimeout(r *http.Request) (time.Duration, bool) {
+	value := r.Header.Get(TimeoutHeader)
+	if value == "" {
+		return 0, false
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
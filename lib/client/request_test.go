@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewRequest(t *testing.T) {
+	t.Run("should not set the timeout header without a deadline", func(t *testing.T) {
+		// when
+		req, err := NewRequest(context.Background(), "GET", "http://localhost", nil)
+
+		// then
+		assert.NoError(t, err)
+		assert.Empty(t, req.Header.Get(TimeoutHeader))
+	})
+
+	t.Run("should set the remaining time of the deadline", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		// when
+		req, err := NewRequest(ctx, "GET", "http://localhost", nil)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, ctx, req.Context())
+		timeout, ok := ParseTimeout(req)
+		assert.True(t, ok)
+		assert.InDelta(t, time.Minute, timeout, float64(time.Second))
+	})
+
+	t.Run("should inject the trace context", func(t *testing.T) {
+		// given
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}))
+
+		// when
+		req, err := NewRequest(ctx, "GET", "http://localhost", nil)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", req.Header.Get("traceparent"))
+	})
+
+	t.Run("should forward the request id", func(t *testing.T) {
+		// given
+		ctx := logger.WithRequest(context.Background(), "my-id", "")
+
+		// when
+		req, err := NewRequest(ctx, "GET", "http://localhost", nil)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "my-id", req.Header.Get(logger.RequestIDHeader))
+	})
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		timeout time.Duration
+		ok      bool
+	}{
+		{"", 0, false},
+		{"abc", 0, false},
+		{"-5", 0, false},
+		{"0", 0, false},
+		{"250", 250 * time.Millisecond, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			// given
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(TimeoutHeader, test.value)
+
+			// when
+			timeout, ok := ParseTimeout(r)
+
+			// then
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.timeout, timeout)
+		})
+	}
+}
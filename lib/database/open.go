@@ -0,0 +1,32 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// dbSystems maps the names of the drivers to the values of the db.system attribute
+var dbSystems = map[string]attribute.KeyValue{
+	"postgres": semconv.DBSystemPostgreSQL,
+}
+
+// Open opens a connection pool like sql.Open, which creates a span for every statement,
+// if the context of the statement belongs to a trace. The driver still has to be imported.
+func Open(driverName string, config Config) (*sql.DB, error) {
+	system, ok := dbSystems[driverName]
+	if !ok {
+		system = semconv.DBSystemKey.String(driverName)
+	}
+
+	return otelsql.Open(driverName, config.Dsn(),
+		otelsql.WithAttributes(system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
+}
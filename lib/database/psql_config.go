@@ -6,9 +6,9 @@ import (
 
 type PsqlConfig struct {
 	Host     string `env:"HOST,notEmpty"`
-	Port     int    `env:"PORT,notEmpty"`
+	Port     int    `env:"PORT,notEmpty" validate:"min=1,max=65535"`
 	Username string `env:"USER,notEmpty"`
-	Password string `env:"PASSWORD,notEmpty"`
+	Password string `env:"PASSWORD,notEmpty" secret:"true"`
 	Database string `env:"DB,notEmpty"`
 }
 
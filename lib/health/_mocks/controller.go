@@ -49,3 +49,27 @@ func (mr *MockControllerMockRecorder) ProvideHealth(arg0, arg1 any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvideHealth", reflect.TypeOf((*MockController)(nil).ProvideHealth), arg0, arg1)
 }
+
+// ProvideLiveness mocks base method.
+func (m *MockController) ProvideLiveness(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProvideLiveness", arg0, arg1)
+}
+
+// ProvideLiveness indicates an expected call of ProvideLiveness.
+func (mr *MockControllerMockRecorder) ProvideLiveness(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvideLiveness", reflect.TypeOf((*MockController)(nil).ProvideLiveness), arg0, arg1)
+}
+
+// ProvideReadiness mocks base method.
+func (m *MockController) ProvideReadiness(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProvideReadiness", arg0, arg1)
+}
+
+// ProvideReadiness indicates an expected call of ProvideReadiness.
+func (mr *MockControllerMockRecorder) ProvideReadiness(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvideReadiness", reflect.TypeOf((*MockController)(nil).ProvideReadiness), arg0, arg1)
+}
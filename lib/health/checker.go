@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config is read with the prefix HEALTH_. The timeout is HEALTH_CHECK_TIMEOUT, because HEALTH_TIMEOUT
+// is the timeout in milliseconds of the load-balancer, which passes its environment to the services.
+type Config struct {
+	Timeout  time.Duration `env:"CHECK_TIMEOUT" envDefault:"2s"`
+	CacheTTL time.Duration `env:"CACHE_TTL" envDefault:"5s"`
+}
+
+// CheckFunc returns an error if a dependency of the service isn't usable.
+type CheckFunc func(ctx context.Context) error
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type CheckResult struct {
+	Status    Status    `json:"status"`
+	Optional  bool      `json:"optional,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	optional bool
+	lock     sync.Mutex
+	result   *CheckResult
+}
+
+// Checker is the registry of the checks a service needs to be ready.
+// Every check runs with its own timeout and its result is cached, so probes can't overload a dependency.
+type Checker struct {
+	config   Config
+	checks   []*check
+	shutdown chan struct{}
+	isDown   atomic.Bool
+}
+
+func NewChecker(config Config) *Checker {
+	return &Checker{config: config, shutdown: make(chan struct{})}
+}
+
+// Register adds a named check. It must be called before the checker is used.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.checks = append(c.checks, &check{name: name, fn: fn})
+}
+
+// RegisterOptional adds a named check, which is reported but doesn't make the service unready.
+// Use it for services which need each other, otherwise none of them gets ready after they are started together.
+func (c *Checker) RegisterOptional(name string, fn CheckFunc) {
+	c.checks = append(c.checks, &check{name: name, fn: fn, optional: true})
+}
+
+// Shutdown reports the service as not ready from now on, so it doesn't get new traffic while it shuts down.
+func (c *Checker) Shutdown() {
+	if c.isDown.CompareAndSwap(false, true) {
+		close(c.shutdown)
+	}
+}
+
+// Done is closed when the checker was shut down.
+func (c *Checker) Done() <-chan struct{} {
+	return c.shutdown
+}
+
+// Live reports if the process itself is running. It doesn't depend on other services,
+// so an outage of a dependency doesn't restart every service.
+func (c *Checker) Live(ctx context.Context) Report {
+	return Report{Status: StatusUp}
+}
+
+// Ready runs all registered checks concurrently and reports if the service can handle requests.
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.isDown.Load() {
+		return Report{Status: StatusDown}
+	}
+
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, ch := range c.checks {
+		wg.Add(1)
+		go func(i int, ch *check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, ch)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(c.checks))}
+	for i, ch := range c.checks {
+		report.Checks[ch.name] = results[i]
+		if results[i].Status != StatusUp && !ch.optional {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, ch *check) CheckResult {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	if ch.result != nil && time.Since(ch.result.CheckedAt) < c.config.CacheTTL {
+		return *ch.result
+	}
+
+	// the result is shared with other probes, so it isn't cancelled with the request
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ch.fn(ctx)
+	result := CheckResult{Status: StatusUp, Optional: ch.optional, Duration: time.Since(start).String(), CheckedAt: start}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	ch.result = &result
+	return result
+}
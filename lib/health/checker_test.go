@@ -0,0 +1,187 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("should load the environment of the load-balanced deployment", func(t *testing.T) {
+		// given
+		bytes, err := os.ReadFile("../../docker-compose-loadbalance.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var compose struct {
+			Services map[string]struct {
+				Environment map[string]any `yaml:"environment"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(bytes, &compose); err != nil {
+			t.Fatal(err)
+		}
+
+		for name, service := range compose.Services {
+			// the load-balancer passes its environment to the services it starts
+			var environ []string
+			for key, value := range service.Environment {
+				environ = append(environ, fmt.Sprintf("%s=%v", key, value))
+			}
+			var config struct {
+				Health Config `envPrefix:"HEALTH_"`
+			}
+			loader := &appconfig.Loader{Name: name, Environ: environ, Output: io.Discard, Exit: func(int) {}}
+
+			// when
+			err := loader.Load(&config)
+
+			// then
+			assert.NoError(t, err, name)
+			assert.Equal(t, 2*time.Second, config.Health.Timeout, name)
+		}
+	})
+}
+
+func TestChecker(t *testing.T) {
+	config := Config{Timeout: 50 * time.Millisecond, CacheTTL: time.Minute}
+
+	t.Run("Live", func(t *testing.T) {
+		t.Run("should be up even if a check fails", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			checker.Register("database", func(ctx context.Context) error { return errors.New("down") })
+
+			// when
+			report := checker.Live(context.Background())
+
+			// then
+			assert.Equal(t, StatusUp, report.Status)
+		})
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		t.Run("should be up without checks", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+
+			// when
+			report := checker.Ready(context.Background())
+
+			// then
+			assert.Equal(t, StatusUp, report.Status)
+			assert.Empty(t, report.Checks)
+		})
+
+		t.Run("should be down if a check fails", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			checker.Register("database", func(ctx context.Context) error { return nil })
+			checker.Register("user-service", func(ctx context.Context) error { return errors.New("connection refused") })
+
+			// when
+			report := checker.Ready(context.Background())
+
+			// then
+			assert.Equal(t, StatusDown, report.Status)
+			assert.Equal(t, StatusUp, report.Checks["database"].Status)
+			assert.Equal(t, StatusDown, report.Checks["user-service"].Status)
+			assert.Equal(t, "connection refused", report.Checks["user-service"].Error)
+		})
+
+		t.Run("should be up if an optional check fails", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			checker.Register("database", func(ctx context.Context) error { return nil })
+			checker.RegisterOptional("book-service", func(ctx context.Context) error { return errors.New("connection refused") })
+
+			// when
+			report := checker.Ready(context.Background())
+
+			// then
+			assert.Equal(t, StatusUp, report.Status)
+			assert.Equal(t, StatusDown, report.Checks["book-service"].Status)
+			assert.True(t, report.Checks["book-service"].Optional)
+		})
+
+		t.Run("should cancel a check after the timeout", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			checker.Register("slow", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+			// when
+			start := time.Now()
+			report := checker.Ready(context.Background())
+
+			// then
+			assert.Less(t, time.Since(start), time.Second)
+			assert.Equal(t, StatusDown, report.Checks["slow"].Status)
+			assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+		})
+
+		t.Run("should cache the results", func(t *testing.T) {
+			// given
+			calls := 0
+			checker := NewChecker(config)
+			checker.Register("database", func(ctx context.Context) error {
+				calls++
+				return nil
+			})
+
+			// when
+			checker.Ready(context.Background())
+			checker.Ready(context.Background())
+
+			// then
+			assert.Equal(t, 1, calls)
+		})
+
+		t.Run("should run the check again after the cache expired", func(t *testing.T) {
+			// given
+			calls := 0
+			checker := NewChecker(Config{Timeout: time.Second, CacheTTL: 0})
+			checker.Register("database", func(ctx context.Context) error {
+				calls++
+				return nil
+			})
+
+			// when
+			checker.Ready(context.Background())
+			checker.Ready(context.Background())
+
+			// then
+			assert.Equal(t, 2, calls)
+		})
+
+		t.Run("should be down after shutdown", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			checker.Register("database", func(ctx context.Context) error { return nil })
+
+			// when
+			checker.Shutdown()
+			checker.Shutdown()
+			report := checker.Ready(context.Background())
+
+			// then
+			assert.Equal(t, StatusDown, report.Status)
+			assert.Equal(t, StatusUp, checker.Live(context.Background()).Status)
+			select {
+			case <-checker.Done():
+			default:
+				t.Error("done channel isn't closed")
+			}
+		})
+	})
+}
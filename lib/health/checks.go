@@ -0,0 +1,56 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// GRPCConn checks the connectivity state of a gRPC client connection.
+// An idle connection is connected first.
+func GRPCConn(conn *grpc.ClientConn) CheckFunc {
+	return func(ctx context.Context) error {
+		for {
+			state := conn.GetState()
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Shutdown:
+				return errors.New("connection is shut down")
+			case connectivity.Idle:
+				conn.Connect()
+			}
+
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connection is %s", state)
+			}
+		}
+	}
+}
+
+// HTTP checks that a GET request to the url of a downstream service is answered with 200 OK.
+// Use the liveness endpoint of other services, otherwise services which need each other are never ready.
+func HTTP(c client.Client, url string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := client.NewRequest(ctx, "GET", url, nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %s", res.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client/_mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestHTTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockClient(ctrl)
+	check := HTTP(client, "http://user-service:8080/health/live")
+
+	t.Run("should return error if the request failed", func(t *testing.T) {
+		// given
+		client.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		// when
+		err := check(context.Background())
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error if the status isn't 200 OK", func(t *testing.T) {
+		// given
+		client.EXPECT().Do(gomock.Any()).Return(&http.Response{Status: "503 Service Unavailable", StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil)
+
+		// when
+		err := check(context.Background())
+
+		// then
+		assert.ErrorContains(t, err, "503 Service Unavailable")
+	})
+
+	t.Run("should return nil if the status is 200 OK", func(t *testing.T) {
+		// given
+		client.EXPECT().Do(gomock.Any()).
+			Do(func(req *http.Request) {
+				assert.Equal(t, "GET", req.Method)
+				assert.Equal(t, "http://user-service:8080/health/live", req.URL.String())
+			}).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil)
+
+		// when
+		err := check(context.Background())
+
+		// then
+		assert.NoError(t, err)
+	})
+}
+
+func TestGRPCConn(t *testing.T) {
+	t.Run("should return nil if the connection is ready", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := grpc.NewServer()
+		go srv.Serve(listener)
+		defer srv.Stop()
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// when
+		err = GRPCConn(conn)(ctx)
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return error if the peer isn't reachable", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := listener.Addr().String()
+		listener.Close()
+
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		// when
+		err = GRPCConn(conn)(ctx)
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error if the connection is closed", func(t *testing.T) {
+		// given
+		conn, err := grpc.Dial("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		// when
+		err = GRPCConn(conn)(context.Background())
+
+		// then
+		assert.ErrorContains(t, err, "shut down")
+	})
+}
@@ -4,4 +4,6 @@ import "net/http"
 
 type Controller interface {
 	ProvideHealth(http.ResponseWriter, *http.Request)
+	ProvideLiveness(http.ResponseWriter, *http.Request)
+	ProvideReadiness(http.ResponseWriter, *http.Request)
 }
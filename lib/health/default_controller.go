@@ -1,13 +1,38 @@
 package health
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+)
 
-type DefaultController struct{}
+type DefaultController struct {
+	checker *Checker
+}
 
-func NewDefaultController() *DefaultController {
-	return &DefaultController{}
+func NewDefaultController(checker *Checker) *DefaultController {
+	return &DefaultController{checker}
 }
 
+// ProvideHealth answers like ProvideReadiness for the load-balancer and existing probes.
 func (ctrl *DefaultController) ProvideHealth(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+	ctrl.ProvideReadiness(w, r)
+}
+
+func (ctrl *DefaultController) ProvideLiveness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, ctrl.checker.Live(r.Context()))
+}
+
+func (ctrl *DefaultController) ProvideReadiness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, ctrl.checker.Ready(r.Context()))
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == StatusUp {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
 }
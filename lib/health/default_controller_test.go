@@ -1,14 +1,27 @@
 package health
 
 import (
-	"github.com/stretchr/testify/assert"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDefaultController(t *testing.T) {
-	controller := NewDefaultController()
+	checker := NewChecker(Config{Timeout: time.Second})
+	failing := false
+	checker.Register("database", func(ctx context.Context) error {
+		if failing {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	controller := NewDefaultController(checker)
 
 	t.Run("ProvideHealth", func(t *testing.T) {
 		// given
@@ -21,4 +34,54 @@ func TestDefaultController(t *testing.T) {
 		// then
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("ProvideReadiness", func(t *testing.T) {
+		t.Run("should return 200 OK with the checks", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/health/ready", nil)
+
+			// when
+			controller.ProvideReadiness(w, r)
+
+			// then
+			var report Report
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, StatusUp, report.Status)
+			assert.Equal(t, StatusUp, report.Checks["database"].Status)
+		})
+
+		t.Run("should return 503 SERVICE UNAVAILABLE if a check fails", func(t *testing.T) {
+			// given
+			failing = true
+			defer func() { failing = false }()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/health/ready", nil)
+
+			// when
+			controller.ProvideReadiness(w, r)
+
+			// then
+			var report Report
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Equal(t, "connection refused", report.Checks["database"].Error)
+		})
+	})
+
+	t.Run("ProvideLiveness", func(t *testing.T) {
+		// given
+		failing = true
+		defer func() { failing = false }()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/health/live", nil)
+
+		// when
+		controller.ProvideLiveness(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
 }
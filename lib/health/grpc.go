@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const watchInterval = 5 * time.Second
+
+type grpcServer struct {
+	healthpb.UnimplementedHealthServer
+	checker  *Checker
+	services map[string]bool
+}
+
+// RegisterGRPC implements the standard grpc.health.v1 service on the server with the readiness of the checker.
+// It must be called after all other services were registered, they and the empty name can be checked.
+func RegisterGRPC(srv *grpc.Server, checker *Checker) {
+	services := map[string]bool{"": true}
+	for name := range srv.GetServiceInfo() {
+		services[name] = true
+	}
+	healthpb.RegisterHealthServer(srv, &grpcServer{checker: checker, services: services})
+}
+
+func (s *grpcServer) servingStatus(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if s.checker.Ready(ctx).Status == StatusUp {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+func (s *grpcServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !s.services[req.Service] {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: s.servingStatus(ctx)}, nil
+}
+
+// Watch sends the status whenever it changes. The stream ends when the checker is shut down,
+// otherwise the watching clients would block the graceful stop of the server.
+func (s *grpcServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if !s.services[req.Service] {
+		return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN})
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		current := s.servingStatus(stream.Context())
+		if current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-s.checker.Done():
+			if last != healthpb.HealthCheckResponse_NOT_SERVING {
+				return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING})
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
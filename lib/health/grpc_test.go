@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func startGRPCServer(t *testing.T, checker *Checker) healthpb.HealthClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := grpc.NewServer()
+	RegisterGRPC(srv, checker)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestGRPCServer(t *testing.T) {
+	config := Config{Timeout: time.Second}
+
+	t.Run("Check", func(t *testing.T) {
+		t.Run("should return SERVING if the checks succeed", func(t *testing.T) {
+			// given
+			client := startGRPCServer(t, NewChecker(config))
+
+			// when
+			res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+		})
+
+		t.Run("should return NOT_SERVING if a check fails", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			checker.Register("database", func(ctx context.Context) error { return errors.New("down") })
+			client := startGRPCServer(t, checker)
+
+			// when
+			res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+		})
+
+		t.Run("should return NOT_FOUND for unknown services", func(t *testing.T) {
+			// given
+			client := startGRPCServer(t, NewChecker(config))
+
+			// when
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+
+			// then
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		t.Run("should send NOT_SERVING and end the stream on shutdown", func(t *testing.T) {
+			// given
+			checker := NewChecker(config)
+			client := startGRPCServer(t, checker)
+			stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+			assert.NoError(t, err)
+
+			first, err := stream.Recv()
+			assert.NoError(t, err)
+
+			// when
+			checker.Shutdown()
+
+			// then
+			second, err := stream.Recv()
+			assert.NoError(t, err)
+			_, end := stream.Recv()
+
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, first.Status)
+			assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, second.Status)
+			assert.Error(t, end)
+		})
+	})
+}
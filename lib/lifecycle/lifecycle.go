@@ -0,0 +1,181 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type Config struct {
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	ShutdownDelay   time.Duration `env:"SHUTDOWN_DELAY" envDefault:"0s"`
+}
+
+type server struct {
+	name     string
+	addr     string
+	serve    func() error
+	shutdown func(ctx context.Context) error
+}
+
+type closer struct {
+	name   string
+	closer io.Closer
+}
+
+// Runner starts the servers of a service and shuts them down gracefully on SIGINT or SIGTERM.
+type Runner struct {
+	config     Config
+	servers    []server
+	onShutdown []func()
+	closers    []closer
+}
+
+func NewRunner(config Config) *Runner {
+	return &Runner{config: config}
+}
+
+// AddHTTPServer serves the handler on addr.
+// On shutdown the server stops accepting connections and waits for the in-flight requests.
+func (r *Runner) AddHTTPServer(name string, addr string, handler http.Handler) {
+	r.AddServer(name, &http.Server{Addr: addr, Handler: handler})
+}
+
+// AddServer serves the configured server, e.g. with timeouts, on its address. It serves HTTPS,
+// if the server has a TLSConfig with the certificates. It is shut down like the servers of AddHTTPServer.
+func (r *Runner) AddServer(name string, srv *http.Server) {
+	r.servers = append(r.servers, server{
+		name: name,
+		addr: srv.Addr,
+		serve: func() error {
+			var err error
+			if srv.TLSConfig != nil {
+				err = srv.ListenAndServeTLS("", "")
+			} else {
+				err = srv.ListenAndServe()
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		shutdown: func(ctx context.Context) error {
+			if err := srv.Shutdown(ctx); err != nil {
+				srv.Close()
+				return err
+			}
+			return nil
+		},
+	})
+}
+
+// AddGRPCServer serves the gRPC-server on addr.
+// On shutdown the server stops accepting connections and waits for the pending RPCs and streams.
+func (r *Runner) AddGRPCServer(name string, addr string, srv *grpc.Server) {
+	r.servers = append(r.servers, server{
+		name: name,
+		addr: addr,
+		serve: func() error {
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			return srv.Serve(listener)
+		},
+		shutdown: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				srv.Stop()
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// OnShutdown registers a function, which is called before the servers are shut down,
+// e.g. to report the service as not ready.
+func (r *Runner) OnShutdown(f func()) {
+	r.onShutdown = append(r.onShutdown, f)
+}
+
+// AddCloser registers a resource like a database pool or a client connection,
+// which is closed after all servers are shut down. The closers are called in reverse order.
+func (r *Runner) AddCloser(name string, c io.Closer) {
+	r.closers = append(r.closers, closer{name, c})
+}
+
+// Run starts all servers and blocks until the context is cancelled, a signal is received or a server fails.
+// Afterwards everything is shut down and the errors of the failed server and the shutdown are returned.
+func (r *Runner) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErrors := make(chan error, len(r.servers))
+	for _, s := range r.servers {
+		go func(s server) {
+			slog.Info("server started", "name", s.name, "addr", s.addr)
+			if err := s.serve(); err != nil {
+				serveErrors <- fmt.Errorf("%s: %w", s.name, err)
+			}
+		}(s)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case err = <-serveErrors:
+		slog.Error("server failed", "error", err)
+	}
+
+	return errors.Join(err, r.shutdown())
+}
+
+func (r *Runner) shutdown() error {
+	for _, f := range r.onShutdown {
+		f()
+	}
+	time.Sleep(r.config.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.ShutdownTimeout)
+	defer cancel()
+
+	errs := make([]error, len(r.servers))
+	var wg sync.WaitGroup
+	for i, s := range r.servers {
+		wg.Add(1)
+		go func(i int, s server) {
+			defer wg.Done()
+			if err := s.shutdown(ctx); err != nil {
+				errs[i] = fmt.Errorf("shutdown %s: %w", s.name, err)
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i].closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", r.closers[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
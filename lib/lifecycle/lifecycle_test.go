@@ -0,0 +1,192 @@
+package lifecycle
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func waitForServer(t *testing.T, addr string) {
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server on %s didn't start", addr)
+}
+
+func TestRunner(t *testing.T) {
+	config := Config{ShutdownTimeout: time.Second}
+
+	t.Run("should call the shutdown hooks and closers in reverse order if the context is cancelled", func(t *testing.T) {
+		// given
+		var calls []string
+		runner := NewRunner(config)
+		runner.OnShutdown(func() { calls = append(calls, "not-ready") })
+		runner.AddCloser("db", closerFunc(func() error {
+			calls = append(calls, "db")
+			return nil
+		}))
+		runner.AddCloser("client", closerFunc(func() error {
+			calls = append(calls, "client")
+			return nil
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		err := runner.Run(ctx)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"not-ready", "client", "db"}, calls)
+	})
+
+	t.Run("should return the errors of the closers", func(t *testing.T) {
+		// given
+		runner := NewRunner(config)
+		runner.AddCloser("db", closerFunc(func() error { return errors.New("close failed") }))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		err := runner.Run(ctx)
+
+		// then
+		assert.ErrorContains(t, err, "close db: close failed")
+	})
+
+	t.Run("should shut down and return the error if a server fails", func(t *testing.T) {
+		// given
+		closed := false
+		runner := NewRunner(config)
+		runner.AddHTTPServer("REST-Server", "invalid-address", http.NotFoundHandler())
+		runner.AddCloser("db", closerFunc(func() error {
+			closed = true
+			return nil
+		}))
+
+		// when
+		err := runner.Run(context.Background())
+
+		// then
+		assert.ErrorContains(t, err, "REST-Server")
+		assert.True(t, closed)
+	})
+
+	t.Run("should drain in-flight http requests", func(t *testing.T) {
+		// given
+		addr := freeAddr(t)
+		started := make(chan struct{})
+		runner := NewRunner(config)
+		runner.AddHTTPServer("REST-Server", addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- runner.Run(ctx) }()
+		waitForServer(t, addr)
+
+		status := make(chan int)
+		go func() {
+			res, err := http.Get(fmt.Sprintf("http://%s/", addr))
+			if err != nil {
+				status <- 0
+				return
+			}
+			status <- res.StatusCode
+		}()
+		<-started
+
+		// when
+		cancel()
+
+		// then
+		assert.Equal(t, http.StatusAccepted, <-status)
+		assert.NoError(t, <-done)
+		_, err := net.Dial("tcp", addr)
+		assert.Error(t, err)
+	})
+
+	t.Run("should serve https with the tls config of the server", func(t *testing.T) {
+		// given
+		certified := httptest.NewTLSServer(http.NotFoundHandler())
+		certificates, client := certified.TLS.Certificates, certified.Client()
+		certified.Close()
+
+		addr := freeAddr(t)
+		runner := NewRunner(config)
+		runner.AddServer("TLS-Server", &http.Server{
+			Addr:      addr,
+			Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusAccepted) }),
+			TLSConfig: &tls.Config{Certificates: certificates},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- runner.Run(ctx) }()
+		waitForServer(t, addr)
+
+		// when
+		res, err := client.Get(fmt.Sprintf("https://%s/", addr))
+		cancel()
+
+		// then
+		if assert.NoError(t, err) {
+			res.Body.Close()
+			assert.Equal(t, http.StatusAccepted, res.StatusCode)
+		}
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("should stop the grpc server", func(t *testing.T) {
+		// given
+		addr := freeAddr(t)
+		runner := NewRunner(config)
+		runner.AddGRPCServer("GRPC-Server", addr, grpc.NewServer())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- runner.Run(ctx) }()
+		waitForServer(t, addr)
+
+		// when
+		cancel()
+
+		// then
+		assert.NoError(t, <-done)
+		_, err := net.Dial("tcp", addr)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// RequestIDHeader is used to send the request id to the called services
+const RequestIDHeader = "X-Request-ID"
+
+// requestKey is private, so the request can't clash with other values in the context
+type requestKey struct{}
+
+type request struct {
+	id    string
+	route string
+	// userID is set after the request was authenticated, so the outer middlewares like the access log see it too
+	userID atomic.Uint64
+}
+
+// WithRequest returns a copy of ctx, which belongs to the request with the id.
+// The route is only needed if it isn't matched by a router.Router like for RPCs.
+func WithRequest(ctx context.Context, id string, route string) context.Context {
+	return context.WithValue(ctx, requestKey{}, &request{id: id, route: route})
+}
+
+// RequestID returns the id of the request of ctx or an empty string
+func RequestID(ctx context.Context) string {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		return req.id
+	}
+	return ""
+}
+
+// SetUserID records the authenticated user of the request of ctx. User ids start with 1.
+func SetUserID(ctx context.Context, userID uint64) {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		req.userID.Store(userID)
+	}
+}
+
+// UserID returns the authenticated user of the request of ctx
+func UserID(ctx context.Context) (uint64, bool) {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		userID := req.userID.Load()
+		return userID, userID != 0
+	}
+	return 0, false
+}
+
+// NewRequestID generates a random id for a request, which didn't bring its own
+func NewRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
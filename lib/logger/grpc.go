@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDMetadata is the metadata key of the request id, gRPC only allows lowercase keys
+var requestIDMetadata = strings.ToLower(RequestIDHeader)
+
+// UnaryServerInterceptor continues the request of the calling service or starts a new one with the method as route
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadata); len(values) > 0 && len(values[0]) <= 128 {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = NewRequestID()
+	}
+
+	return handler(WithRequest(ctx, id, info.FullMethod), req)
+}
+
+// UnaryClientInterceptor sends the request id of ctx with the metadata of the RPC
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := RequestID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadata, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// ServerOption returns the interceptor of the gRPC-server as option for grpc.NewServer
+func ServerOption() grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(UnaryServerInterceptor)
+}
+
+// DialOption returns the interceptor of the gRPC-client as option for grpc.Dial
+func DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientInterceptor)
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGRPC(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.UserService/MoveUserAmount"}
+
+	t.Run("UnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should continue the request of the metadata", func(t *testing.T) {
+			// given
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDMetadata, "my-id"))
+			var handlerCtx context.Context
+
+			// when
+			_, err := UnaryServerInterceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				handlerCtx = ctx
+				return nil, nil
+			})
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, "my-id", RequestID(handlerCtx))
+			assert.Equal(t, info.FullMethod, handlerCtx.Value(requestKey{}).(*request).route)
+		})
+
+		t.Run("should generate a request id", func(t *testing.T) {
+			// given
+			var handlerCtx context.Context
+
+			// when
+			_, err := UnaryServerInterceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+				handlerCtx = ctx
+				return nil, nil
+			})
+
+			// then
+			assert.NoError(t, err)
+			assert.Len(t, RequestID(handlerCtx), 32)
+		})
+	})
+
+	t.Run("UnaryClientInterceptor", func(t *testing.T) {
+		t.Run("should send the request id with the metadata", func(t *testing.T) {
+			// given
+			ctx := WithRequest(context.Background(), "my-id", "")
+			var md metadata.MD
+
+			// when
+			err := UnaryClientInterceptor(ctx, info.FullMethod, nil, nil, nil,
+				func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					md, _ = metadata.FromOutgoingContext(ctx)
+					return nil
+				})
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"my-id"}, md.Get(requestIDMetadata))
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+)
+
+// contextHandler adds the fields of the request context to every record before it is written
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		record.AddAttrs(slog.String("request_id", req.id))
+		if userID := req.userID.Load(); userID != 0 {
+			record.AddAttrs(slog.Uint64("user_id", userID))
+		}
+
+		route := req.route
+		if route == "" {
+			route = router.PatternFromContext(ctx)
+		}
+		if route != "" {
+			record.AddAttrs(slog.String("route", route))
+		}
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
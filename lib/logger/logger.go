@@ -0,0 +1,51 @@
+// Package logger provides the structured logging of all services based on log/slog.
+// Every record, which is logged with a request context, carries the request id, user id and route of the request.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type Config struct {
+	// Level is one of DEBUG, INFO, WARN or ERROR
+	Level slog.Level `env:"LEVEL" envDefault:"INFO"`
+	// Format is json or text, text is easier to read for local debugging
+	Format string `env:"FORMAT" envDefault:"json"`
+}
+
+// Validate is called by the appconfig-package
+func (config Config) Validate() error {
+	switch config.Format {
+	case FormatJSON, FormatText, "":
+		return nil
+	}
+	return fmt.Errorf("unknown log format %q", config.Format)
+}
+
+// New creates a logger, which writes the records to w and adds the fields of the request context
+func New(config Config, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: config.Level}
+
+	var handler slog.Handler
+	if config.Format == FormatText {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	return slog.New(&contextHandler{handler})
+}
+
+// SetDefault replaces the default logger with a logger writing to stderr.
+// Messages of the log-package are written to it as well.
+func SetDefault(config Config) {
+	slog.SetDefault(New(config, os.Stderr))
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("should write JSON records with the fields of the request", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		log := New(Config{Level: slog.LevelInfo, Format: FormatJSON}, buf)
+		ctx := WithRequest(context.Background(), "my-id", "/proto.BookService/ValidateChapterId")
+		SetUserID(ctx, 42)
+
+		// when
+		log.InfoContext(ctx, "validated", "chapter", 1)
+
+		// then
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, "validated", record["msg"])
+		assert.Equal(t, "my-id", record["request_id"])
+		assert.Equal(t, float64(42), record["user_id"])
+		assert.Equal(t, "/proto.BookService/ValidateChapterId", record["route"])
+		assert.Equal(t, float64(1), record["chapter"])
+	})
+
+	t.Run("should use the pattern of the matched route", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		log := New(Config{Level: slog.LevelInfo, Format: FormatJSON}, buf).With("service", "book-service")
+
+		r := router.New()
+		r.GET("/books/:bookid<uint>", func(w http.ResponseWriter, r *http.Request) {
+			log.InfoContext(r.Context(), "found book")
+		})
+		req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+		req = router.RecordPattern(req.WithContext(WithRequest(req.Context(), "my-id", "")))
+
+		// when
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		// then
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "/books/:bookid<uint>", record["route"])
+		assert.Equal(t, "book-service", record["service"])
+		assert.NotContains(t, record, "user_id")
+	})
+
+	t.Run("should not write records below the level", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		log := New(Config{Level: slog.LevelWarn, Format: FormatJSON}, buf)
+
+		// when
+		log.Info("not written")
+		log.Warn("written")
+
+		// then
+		assert.NotContains(t, buf.String(), "not written")
+		assert.Contains(t, buf.String(), "written")
+	})
+
+	t.Run("should write text records", func(t *testing.T) {
+		// given
+		buf := &bytes.Buffer{}
+		log := New(Config{Level: slog.LevelInfo, Format: FormatText}, buf)
+
+		// when
+		log.InfoContext(WithRequest(context.Background(), "my-id", ""), "hello")
+
+		// then
+		assert.Contains(t, buf.String(), "msg=hello request_id=my-id")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should accept the known formats", func(t *testing.T) {
+		assert.NoError(t, Config{Format: FormatJSON}.Validate())
+		assert.NoError(t, Config{Format: FormatText}.Validate())
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		assert.EqualError(t, Config{Format: "xml"}.Validate(), `unknown log format "xml"`)
+	})
+}
+
+func TestContext(t *testing.T) {
+	t.Run("should return no fields without a request", func(t *testing.T) {
+		// given
+		ctx := context.Background()
+		SetUserID(ctx, 1)
+
+		// when
+		id := RequestID(ctx)
+		_, ok := UserID(ctx)
+
+		// then
+		assert.Empty(t, id)
+		assert.False(t, ok)
+	})
+
+	t.Run("should share the user id with the parent contexts", func(t *testing.T) {
+		// given
+		ctx := WithRequest(context.Background(), "my-id", "")
+		child := context.WithValue(ctx, struct{}{}, "value")
+
+		// when
+		SetUserID(child, 7)
+
+		// then
+		userID, ok := UserID(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(7), userID)
+	})
+
+	t.Run("should generate different request ids", func(t *testing.T) {
+		// when
+		first, second := NewRequestID(), NewRequestID()
+
+		// then
+		assert.Len(t, first, 32)
+		assert.NotEqual(t, first, second)
+	})
+}
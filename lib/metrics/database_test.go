@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type statsFunc func() sql.DBStats
+
+func (fn statsFunc) Stats() sql.DBStats {
+	return fn()
+}
+
+func TestDBStats(t *testing.T) {
+	t.Run("should export the pool statistics with the name of the database", func(t *testing.T) {
+		// given
+		collector := newDBStatsCollector("books", statsFunc(func() sql.DBStats {
+			return sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 2, Idle: 1, WaitCount: 5, WaitDuration: 2 * time.Second}
+		}))
+
+		expected := `
+# HELP go_sql_in_use_connections The number of connections currently in use.
+# TYPE go_sql_in_use_connections gauge
+go_sql_in_use_connections{db_name="books"} 2
+# HELP go_sql_wait_duration_seconds_total The total time blocked waiting for a new connection.
+# TYPE go_sql_wait_duration_seconds_total counter
+go_sql_wait_duration_seconds_total{db_name="books"} 2
+`
+
+		// when
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "go_sql_in_use_connections", "go_sql_wait_duration_seconds_total")
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 9, testutil.CollectAndCount(collector))
+	})
+
+	t.Run("should not register the same name twice", func(t *testing.T) {
+		// given
+		provider := statsFunc(func() sql.DBStats { return sql.DBStats{} })
+		assert.NoError(t, RegisterDBStats("test-database", provider))
+
+		// when
+		err := RegisterDBStats("test-database", provider)
+
+		// then
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcServerHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Number of RPCs completed on the server by service, method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+	grpcServerHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Duration of RPCs handled by the server by service and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+	grpcClientHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_handled_total",
+		Help: "Number of RPCs completed by the client by service, method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+	grpcClientHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "Duration of RPCs until the client received the response by service and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+)
+
+// splitMethod splits a full method like "/proto.BookService/GetBook" into service and method
+func splitMethod(fullMethod string) (string, string) {
+	service, method, found := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !found {
+		return "unknown", "unknown"
+	}
+	return service, method
+}
+
+func observe(handled *prometheus.CounterVec, handlingSeconds *prometheus.HistogramVec, fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	handled.WithLabelValues(service, method, status.Code(err).String()).Inc()
+	handlingSeconds.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+}
+
+// UnaryServerInterceptor counts and times every unary RPC of a gRPC-server
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(grpcServerHandled, grpcServerHandlingSeconds, info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamServerInterceptor counts and times every streaming RPC of a gRPC-server until the stream is closed
+func StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observe(grpcServerHandled, grpcServerHandlingSeconds, info.FullMethod, start, err)
+	return err
+}
+
+// ServerOptions returns the interceptors of the gRPC-server as options for grpc.NewServer
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor),
+	}
+}
+
+// UnaryClientInterceptor counts and times every unary RPC of a gRPC-client
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	observe(grpcClientHandled, grpcClientHandlingSeconds, method, start, err)
+	return err
+}
+
+// DialOption returns the interceptor of the gRPC-client as option for grpc.Dial
+func DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientInterceptor)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPC(t *testing.T) {
+	t.Run("splitMethod", func(t *testing.T) {
+		t.Run("should split the full method into service and method", func(t *testing.T) {
+			// when
+			service, method := splitMethod("/proto.BookService/GetBook")
+
+			// then
+			assert.Equal(t, "proto.BookService", service)
+			assert.Equal(t, "GetBook", method)
+		})
+
+		t.Run("should return unknown for invalid methods", func(t *testing.T) {
+			// when
+			service, method := splitMethod("invalid")
+
+			// then
+			assert.Equal(t, "unknown", service)
+			assert.Equal(t, "unknown", method)
+		})
+	})
+
+	t.Run("UnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should count the RPC with its status code", func(t *testing.T) {
+			// given
+			counter := grpcServerHandled.WithLabelValues("proto.BookService", "GetBook", codes.NotFound.String())
+			before := testutil.ToFloat64(counter)
+			info := &grpc.UnaryServerInfo{FullMethod: "/proto.BookService/GetBook"}
+
+			// when
+			_, err := UnaryServerInterceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			})
+
+			// then
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	})
+
+	t.Run("StreamServerInterceptor", func(t *testing.T) {
+		t.Run("should count the stream when it is closed", func(t *testing.T) {
+			// given
+			counter := grpcServerHandled.WithLabelValues("grpc.health.v1.Health", "Watch", codes.OK.String())
+			before := testutil.ToFloat64(counter)
+			info := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}
+
+			// when
+			err := StreamServerInterceptor(nil, nil, info, func(srv any, stream grpc.ServerStream) error {
+				return nil
+			})
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	})
+
+	t.Run("UnaryClientInterceptor", func(t *testing.T) {
+		t.Run("should count the RPC with its status code", func(t *testing.T) {
+			// given
+			counter := grpcClientHandled.WithLabelValues("proto.UserService", "ValidateToken", codes.Unavailable.String())
+			before := testutil.ToFloat64(counter)
+
+			// when
+			err := UnaryClientInterceptor(context.Background(), "/proto.UserService/ValidateToken", nil, nil, nil,
+				func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					return status.Error(codes.Unavailable, "unavailable")
+				})
+
+			// then
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	})
+}
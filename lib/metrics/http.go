@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// unmatchedRoute is used as route label for requests no route matched, so unknown paths don't create new series
+const unmatchedRoute = "unmatched"
+
+// otherMethod is used as method label for requests with a non-standard method, so clients can't create new series with arbitrary methods
+const otherMethod = "OTHER"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of handled HTTP requests by method, route pattern and status.",
+	}, []string{"method", "route", "status"})
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of handled HTTP requests by method, route pattern and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served.",
+	})
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(b []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	return recorder.ResponseWriter.Write(b)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
+
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethod
+	}
+}
+
+// HTTP counts every request and observes its duration. The requests are labelled
+// with the pattern of the route of a router.Router, not with the path, so ids in
+// the path don't create a new series for every request.
+func HTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		r = router.RecordPattern(r)
+
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		route := router.MatchedPattern(r)
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		method := methodLabel(r.Method)
+		status := strconv.Itoa(recorder.status)
+		httpRequests.WithLabelValues(method, route, status).Inc()
+		httpRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP(t *testing.T) {
+	r := router.New()
+	r.GET("/books/:bookid<uint>", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.GET("/books", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("books"))
+	})
+	handler := HTTP(r)
+
+	t.Run("should label the request with the route pattern and status", func(t *testing.T) {
+		// given
+		counter := httpRequests.WithLabelValues(http.MethodGet, "/books/:bookid<uint>", "418")
+		before := testutil.ToFloat64(counter)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/books/1", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/books/2", nil))
+
+		// then
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+
+	t.Run("should use 200 if the handler only writes the body", func(t *testing.T) {
+		// given
+		counter := httpRequests.WithLabelValues(http.MethodGet, "/books", "200")
+		before := testutil.ToFloat64(counter)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/books", nil))
+
+		// then
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+
+	t.Run("should label unknown paths as unmatched", func(t *testing.T) {
+		// given
+		counter := httpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")
+		before := testutil.ToFloat64(counter)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown/1", nil))
+
+		// then
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+
+	t.Run("should label non-standard methods as other", func(t *testing.T) {
+		// given
+		counter := httpRequests.WithLabelValues(otherMethod, unmatchedRoute, "404")
+		before := testutil.ToFloat64(counter)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PROPFIND", "/unknown", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("X-RANDOM-1", "/unknown", nil))
+
+		// then
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+
+	t.Run("should observe the duration", func(t *testing.T) {
+		// given
+		before := testutil.CollectAndCount(httpRequestDuration)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/books", nil))
+
+		// then
+		assert.Equal(t, before+1, testutil.CollectAndCount(httpRequestDuration))
+	})
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("should serve the registered metrics", func(t *testing.T) {
+		// given
+		HTTP(router.New()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+		// when
+		Handler().ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "http_requests_total")
+		assert.Contains(t, w.Body.String(), "go_goroutines")
+	})
+}
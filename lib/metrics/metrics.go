@@ -0,0 +1,14 @@
+// Package metrics provides the Prometheus metrics of all services.
+// The metrics are registered at the default registry, which also contains the Go runtime and process metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler serves all registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
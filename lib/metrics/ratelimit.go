@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var rateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rate_limited_requests_total",
+	Help: "Number of requests rejected by the rate limit of a route.",
+}, []string{"route"})
+
+// ObserveRateLimited counts a request, which was rejected by the rate limit of the route
+func ObserveRateLimited(route string) {
+	rateLimitedRequests.WithLabelValues(route).Inc()
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRateLimited(t *testing.T) {
+	t.Run("should count the rejected requests by route", func(t *testing.T) {
+		// when
+		ObserveRateLimited("*/api/v1/login")
+		ObserveRateLimited("*/api/v1/login")
+
+		// then
+		assert.Equal(t, 2.0, testutil.ToFloat64(rateLimitedRequests.WithLabelValues("*/api/v1/login")))
+	})
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var sharedCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "singleflight_calls_total",
+	Help: "Number of deduplicated calls by whether the result of a concurrent call was shared instead of executing the call.",
+}, []string{"shared"})
+
+// ObserveSharedCall counts a call of utils.DoShared. shared is true,
+// if the caller got the result of another caller instead of executing the call itself.
+func ObserveSharedCall(shared bool) {
+	sharedCalls.WithLabelValues(strconv.FormatBool(shared)).Inc()
+}
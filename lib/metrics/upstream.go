@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// upstreamError is used as status label for requests, which didn't get a response from the upstream
+const upstreamError = "error"
+
+var (
+	upstreamRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "upstream_requests_total",
+		Help: "Number of requests forwarded to an upstream by upstream and status.",
+	}, []string{"upstream", "status"})
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "upstream_request_duration_seconds",
+		Help:    "Duration until the response headers of the upstream were received by upstream.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"upstream"})
+	upstreamRequestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "upstream_requests_in_flight",
+		Help: "Number of requests currently waiting for the response headers of an upstream.",
+	}, []string{"upstream"})
+	upstreamUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "upstream_up",
+		Help: "Whether the last health check of an upstream of a mapping succeeded (1) or not (0).",
+	}, []string{"mapping", "upstream"})
+)
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+// RoundTripper counts and times every request sent by next, labelled with the host of the request as upstream
+func RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &roundTripper{next: next}
+}
+
+func (rt *roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	upstream := r.URL.Host
+	start := time.Now()
+
+	upstreamRequestsInFlight.WithLabelValues(upstream).Inc()
+	defer upstreamRequestsInFlight.WithLabelValues(upstream).Dec()
+
+	resp, err := rt.next.RoundTrip(r)
+
+	upstreamRequestDuration.WithLabelValues(upstream).Observe(time.Since(start).Seconds())
+	if err != nil {
+		upstreamRequests.WithLabelValues(upstream, upstreamError).Inc()
+		return nil, err
+	}
+	upstreamRequests.WithLabelValues(upstream, strconv.Itoa(resp.StatusCode)).Inc()
+	return resp, nil
+}
+
+// SetUpstreamHealth records the result of the last health check of the upstream. The mapping tells apart
+// the same upstream used by several mappings, whose health is checked and ejected independently.
+func SetUpstreamHealth(mapping, upstream string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	upstreamUp.WithLabelValues(mapping, upstream).Set(value)
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestRoundTripper(t *testing.T) {
+	t.Run("should count the request with the host and status", func(t *testing.T) {
+		// given
+		counter := upstreamRequests.WithLabelValues("book:8080", "201")
+		before := testutil.ToFloat64(counter)
+		rt := RoundTripper(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusCreated, Body: http.NoBody}, nil
+		}))
+
+		// when
+		resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodPost, "http://book:8080/api/v1/books", nil))
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		assert.Equal(t, 0.0, testutil.ToFloat64(upstreamRequestsInFlight.WithLabelValues("book:8080")))
+	})
+
+	t.Run("should count failed requests as error", func(t *testing.T) {
+		// given
+		counter := upstreamRequests.WithLabelValues("user:8080", upstreamError)
+		before := testutil.ToFloat64(counter)
+		rt := RoundTripper(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}))
+
+		// when
+		_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://user:8080/api/v1/users", nil))
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+}
+
+func TestSetUpstreamHealth(t *testing.T) {
+	t.Run("should export the health of the upstream", func(t *testing.T) {
+		// when
+		SetUpstreamHealth("/", "web:8080", true)
+		SetUpstreamHealth("/", "web:8081", false)
+		SetUpstreamHealth("/api/v1/books", "web:8080", false)
+
+		// then
+		assert.Equal(t, 1.0, testutil.ToFloat64(upstreamUp.WithLabelValues("/", "web:8080")))
+		assert.Equal(t, 0.0, testutil.ToFloat64(upstreamUp.WithLabelValues("/", "web:8081")))
+		assert.Equal(t, 0.0, testutil.ToFloat64(upstreamUp.WithLabelValues("/api/v1/books", "web:8080")))
+	})
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+)
+
+// BodyLimit answers with 413 Request Entity Too Large, if the request body is
+// larger than maxBytes. Reading more than maxBytes from the body returns an error.
+func BodyLimit(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				problem.Write(w, r, problem.TooLarge(maxBytes))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	var readErr error
+	handler := BodyLimit(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	t.Run("should return 413 REQUEST ENTITY TOO LARGE if the content length is too large", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader("too large"))
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("should fail reading the body if the unknown content length is too large", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader("too large"))
+		r.ContentLength = -1
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		var maxBytesError *http.MaxBytesError
+		assert.ErrorAs(t, readErr, &maxBytesError)
+	})
+
+	t.Run("should read small bodies", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader("ok"))
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.NoError(t, readErr)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins can contain "*" to allow every origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS sets the CORS headers for allowed origins and answers preflight requests
+func CORS(config CORSConfig) func(http.Handler) http.Handler {
+	if len(config.AllowedMethods) == 0 {
+		config.AllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	allowAll := slices.Contains(config.AllowedOrigins, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Add("Vary", "Origin")
+			if !allowAll && !slices.Contains(config.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAll && !config.AllowCredentials {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// preflight request
+			header.Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+			if len(config.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				header.Set("Access-Control-Allow-Headers", requested)
+			}
+			if config.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
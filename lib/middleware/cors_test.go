@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	called := false
+	handler := CORS(CORSConfig{
+		AllowedOrigins: []string{"http://localhost:5173"},
+		MaxAge:         time.Hour,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	t.Run("should set the allowed origin", func(t *testing.T) {
+		// given
+		called = false
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Origin", "http://localhost:5173")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.True(t, called)
+		assert.Equal(t, "http://localhost:5173", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("should not set headers for unknown origins", func(t *testing.T) {
+		// given
+		called = false
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Origin", "http://evil.com")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.True(t, called)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("should answer preflight requests", func(t *testing.T) {
+		// given
+		called = false
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("OPTIONS", "/", nil)
+		r.Header.Set("Origin", "http://localhost:5173")
+		r.Header.Set("Access-Control-Request-Method", "PATCH")
+		r.Header.Set("Access-Control-Request-Headers", "Authorization")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "GET, POST, PUT, PATCH, DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+	})
+}
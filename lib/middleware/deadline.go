@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
+)
+
+// Deadline applies the deadline of the calling service, which client.NewRequest sends in the
+// X-Request-Timeout header, to the request context.
+func Deadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout, ok := client.ParseTimeout(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline(t *testing.T) {
+	t.Run("should not set a deadline without the timeout header", func(t *testing.T) {
+		// given
+		var hasDeadline bool
+		handler := Deadline(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, hasDeadline = r.Context().Deadline()
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.False(t, hasDeadline)
+	})
+
+	t.Run("should set the deadline of the timeout header", func(t *testing.T) {
+		// given
+		var deadline time.Time
+		handler := Deadline(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, _ = r.Context().Deadline()
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(client.TimeoutHeader, "5000")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+	})
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gzipWriter  *gzip.Writer
+	wroteHeader bool
+}
+
+// WriteHeader decides if the response is compressed. Responses without a body
+// or with an encoding set by the handler are passed through.
+func (gw *gzipResponseWriter) WriteHeader(status int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+
+	header := gw.Header()
+	if status != http.StatusNoContent && status != http.StatusNotModified && header.Get("Content-Encoding") == "" {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", "gzip")
+		gw.gzipWriter = gzipWriterPool.Get().(*gzip.Writer)
+		gw.gzipWriter.Reset(gw.ResponseWriter)
+	}
+	header.Add("Vary", "Accept-Encoding")
+
+	gw.ResponseWriter.WriteHeader(status)
+}
+
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		if gw.Header().Get("Content-Type") == "" {
+			gw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.gzipWriter == nil {
+		return gw.ResponseWriter.Write(b)
+	}
+	return gw.gzipWriter.Write(b)
+}
+
+func (gw *gzipResponseWriter) Flush() {
+	if gw.gzipWriter != nil {
+		gw.gzipWriter.Flush()
+	}
+	if flusher, ok := gw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+func (gw *gzipResponseWriter) close() {
+	if gw.gzipWriter == nil {
+		return
+	}
+	gw.gzipWriter.Close()
+	gzipWriterPool.Put(gw.gzipWriter)
+	gw.gzipWriter = nil
+}
+
+// Gzip compresses the response, if the client accepts gzip
+func Gzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) == "gzip" && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzip(t *testing.T) {
+	handler := Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hello":"world"}`))
+	}))
+
+	t.Run("should compress the response if the client accepts gzip", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", "deflate, gzip")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(w.Body)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"hello":"world"}`, string(body))
+	})
+
+	t.Run("should not compress the response if the client doesn't accept gzip", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", "gzip;q=0")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, `{"hello":"world"}`, w.Body.String())
+	})
+
+	t.Run("should not compress responses without body", func(t *testing.T) {
+		// given
+		handler := Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Body.Bytes())
+	})
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/tracing"
+)
+
+type Config struct {
+	MaxBodySize        int64         `env:"MAX_BODY_SIZE" envDefault:"1048576"`
+	RequestTimeout     time.Duration `env:"REQUEST_TIMEOUT" envDefault:"30s"`
+	CorsAllowedOrigins []string      `env:"CORS_ALLOWED_ORIGINS" envSeparator:","`
+}
+
+// Chain wraps the handler with the middlewares. The first middleware is the outermost one.
+func Chain(handler http.Handler, middlewares ...router.HandlerMiddleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Default wraps the handler with the middlewares every service uses.
+// The metrics and spans are labelled with the route pattern, if handler is a router.Router.
+// CORS is only active, if allowed origins are configured.
+func Default(handler http.Handler, config Config) http.Handler {
+	middlewares := []router.HandlerMiddleware{
+		tracing.HTTP,
+		RequestID,
+		AccessLog,
+		metrics.HTTP,
+		Recovery,
+	}
+	if len(config.CorsAllowedOrigins) > 0 {
+		middlewares = append(middlewares, CORS(CORSConfig{AllowedOrigins: config.CorsAllowedOrigins}))
+	}
+	if config.MaxBodySize > 0 {
+		middlewares = append(middlewares, BodyLimit(config.MaxBodySize))
+	}
+	middlewares = append(middlewares, Gzip, Deadline)
+	if config.RequestTimeout > 0 {
+		middlewares = append(middlewares, Timeout(config.RequestTimeout))
+	}
+
+	return Chain(handler, middlewares...)
+}
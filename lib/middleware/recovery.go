@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+)
+
+// Recovery answers with 500 Internal Server Error, if the handler panics
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				// the handler wants net/http to abort the response
+				panic(err)
+			}
+
+			slog.ErrorContext(r.Context(), "handler panicked", "method", r.Method, "path", r.URL.Path, "error", err, "stack", string(debug.Stack()))
+			problem.Write(w, r, fmt.Errorf("panic: %v", err))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecovery(t *testing.T) {
+	t.Run("should return 500 INTERNAL SERVER ERROR if the handler panics", func(t *testing.T) {
+		// given
+		handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("something went wrong")
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("should not touch the response if the handler doesn't panic", func(t *testing.T) {
+		// given
+		handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should repanic http.ErrAbortHandler", func(t *testing.T) {
+		// given
+		handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		// then
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		})
+	})
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+)
+
+const RequestIDHeader = logger.RequestIDHeader
+
+// RequestID reuses the X-Request-ID header of the request or generates a new id.
+// The id is sent back in the response and can be read with GetRequestID.
+// The logger adds it to every record logged with the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = logger.NewRequestID()
+			r.Header.Set(RequestIDHeader, id)
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequest(r.Context(), id, "")))
+	})
+}
+
+// GetRequestID returns the id set by the RequestID middleware or an empty string
+func GetRequestID(ctx context.Context) string {
+	return logger.RequestID(ctx)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("should generate a request id", func(t *testing.T) {
+		// given
+		var id string
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = GetRequestID(r.Context())
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Len(t, id, 32)
+		assert.Equal(t, id, w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("should reuse the request id of the request", func(t *testing.T) {
+		// given
+		var id string
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = GetRequestID(r.Context())
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(RequestIDHeader, "my-id")
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, "my-id", id)
+		assert.Equal(t, "my-id", w.Header().Get(RequestIDHeader))
+	})
+}
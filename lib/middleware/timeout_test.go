@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Run("should cancel the request context after the timeout", func(t *testing.T) {
+		// given
+		var err error
+		handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			err = r.Context().Err()
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should keep an earlier deadline", func(t *testing.T) {
+		// given
+		var deadline time.Time
+		handler := Timeout(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, _ = r.Context().Deadline()
+		}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+	})
+
+	t.Run("should pass the response writer through", func(t *testing.T) {
+		// given
+		var flushable bool
+		handler := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, flushable = w.(http.Flusher)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		handler.ServeHTTP(w, r)
+
+		// then
+		assert.True(t, flushable)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+}
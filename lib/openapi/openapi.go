@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+)
+
+const Version = "3.0.3"
+
+var operationIDReplacer = strings.NewReplacer("/", "_", "{", "", "}", "", "-", "_")
+
+// Generate creates the OpenAPI document for the routes
+func Generate(info Info, routes []*router.Route) *Document {
+	generator := newSchemaGenerator()
+	document := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	for _, route := range routes {
+		path := openAPIPath(route.Pattern)
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			document.Paths[path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = generator.operation(route)
+	}
+
+	document.Components.Schemas = generator.schemas
+	return document
+}
+
+// Handler serves the OpenAPI document of all routes of the router as JSON.
+// The document is generated on the first request, so routes registered after the handler are included.
+func Handler(r *router.Router, info Info) http.HandlerFunc {
+	var once sync.Once
+	var document []byte
+	var err error
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		once.Do(func() {
+			document, err = json.Marshal(Generate(info, r.Routes()))
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(document)
+	}
+}
+
+// openAPIPath converts a router pattern like /books/:bookid<uint> into /books/{bookid}
+func openAPIPath(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if len(segment) > 1 && segment[0] == ':' {
+			name, _, _ := strings.Cut(segment[1:], "<")
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func paramSchema(typeName string) *Schema {
+	switch typeName {
+	case "int":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "uint":
+		return &Schema{Type: "integer", Format: "int64", Minimum: minimum(0)}
+	case "float":
+		return &Schema{Type: "number", Format: "double"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func (generator *schemaGenerator) operation(route *router.Route) *Operation {
+	operation := &Operation{
+		Summary:     route.Summary,
+		OperationID: strings.ToLower(route.Method) + operationIDReplacer.Replace(openAPIPath(route.Pattern)),
+		Responses:   make(map[string]*Response),
+	}
+
+	for _, param := range route.Params {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     param.Name,
+			In:       "path",
+			Required: true,
+			Schema:   paramSchema(param.Type),
+		})
+	}
+	for _, param := range route.QueryParams {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:   param.Name,
+			In:     "query",
+			Schema: paramSchema(param.Type),
+		})
+	}
+
+	if route.RequestType != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(generator.schema(route.RequestType)),
+		}
+	}
+
+	for _, response := range route.Responses {
+		operation.Responses[strconv.Itoa(response.Status)] = generator.response(response.Status, response.Type)
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+
+	return operation
+}
+
+func (generator *schemaGenerator) response(status int, t reflect.Type) *Response {
+	response := &Response{Description: http.StatusText(status)}
+	if t != nil {
+		response.Content = jsonContent(generator.schema(t))
+	}
+	return response
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBook struct {
+	ID       uint64      `json:"id"`
+	Name     string      `json:"name"`
+	Price    *uint64     `json:"price"`
+	Tags     []string    `json:"tags,omitempty"`
+	Author   testAuthor  `json:"author"`
+	Chapters []*testBook `json:"chapters"`
+	Internal string      `json:"-"`
+	hidden   string
+}
+
+type testAuthor struct {
+	Name string
+}
+
+func TestGenerate(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	t.Run("should convert the patterns and params", func(t *testing.T) {
+		// given
+		r := router.New()
+		r.GET("/books/:bookid<uint>/chapters/:name", handler).
+			Describe("Get a chapter").
+			Query("draft", "bool")
+
+		// when
+		document := Generate(Info{Title: "Books", Version: "1.0.0"}, r.Routes())
+
+		// then
+		assert.Equal(t, Version, document.OpenAPI)
+		operation := (*document.Paths["/books/{bookid}/chapters/{name}"])["get"]
+		assert.NotNil(t, operation)
+		assert.Equal(t, "Get a chapter", operation.Summary)
+		assert.Equal(t, "get_books_bookid_chapters_name", operation.OperationID)
+		assert.Equal(t, []Parameter{
+			{Name: "bookid", In: "path", Required: true, Schema: &Schema{Type: "integer", Format: "int64", Minimum: minimum(0)}},
+			{Name: "name", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+			{Name: "draft", In: "query", Schema: &Schema{Type: "boolean"}},
+		}, operation.Parameters)
+		assert.Equal(t, &Response{Description: "OK"}, operation.Responses["200"])
+	})
+
+	t.Run("should reference named structs in the components", func(t *testing.T) {
+		// given
+		r := router.New()
+		r.POST("/books", handler).
+			Request(testBook{}).
+			Response(http.StatusCreated, nil)
+		r.GET("/books", handler).
+			Response(http.StatusOK, []*testBook{})
+
+		// when
+		document := Generate(Info{Title: "Books", Version: "1.0.0"}, r.Routes())
+
+		// then
+		ref := &Schema{Ref: "#/components/schemas/testBook"}
+		post := (*document.Paths["/books"])["post"]
+		assert.Equal(t, ref, post.RequestBody.Content["application/json"].Schema)
+		assert.Equal(t, &Response{Description: "Created"}, post.Responses["201"])
+
+		get := (*document.Paths["/books"])["get"]
+		assert.Equal(t, &Schema{Type: "array", Items: ref}, get.Responses["200"].Content["application/json"].Schema)
+
+		book := document.Components.Schemas["testBook"]
+		assert.Equal(t, []string{"id", "name", "author", "chapters"}, book.Required)
+		assert.Len(t, book.Properties, 6)
+		assert.Equal(t, &Schema{Type: "integer", Format: "int64", Minimum: minimum(0), Nullable: true}, book.Properties["price"])
+		assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, book.Properties["tags"])
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/testAuthor"}, book.Properties["author"])
+		assert.Equal(t, &Schema{Type: "array", Items: ref}, book.Properties["chapters"])
+
+		author := document.Components.Schemas["testAuthor"]
+		assert.Equal(t, &Schema{Type: "string"}, author.Properties["Name"])
+	})
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("should serve the document of all routes", func(t *testing.T) {
+		// given
+		r := router.New()
+		r.GET("/openapi.json", Handler(r, Info{Title: "Books", Version: "1.0.0"}))
+		r.DELETE("/books/:bookid<uint>", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+
+		// when
+		r.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var document Document
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&document))
+		assert.Equal(t, "Books", document.Info.Title)
+		assert.Contains(t, document.Paths, "/openapi.json")
+		assert.Contains(t, document.Paths, "/books/{bookid}")
+	})
+}
@@ -0,0 +1,143 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaGenerator converts go types into schemas like encoding/json would
+// encode them. Named structs are added to the components and referenced.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func minimum(value float64) *float64 {
+	return &value
+}
+
+func (generator *schemaGenerator) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+	if t.Implements(jsonMarshalerType) {
+		// the custom encoding can't be known
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := generator.schema(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: minimum(0)}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64", Minimum: minimum(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: generator.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generator.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return generator.structSchema(t)
+		}
+		return generator.ref(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// ref adds the struct to the components, if it isn't already known, and references it
+func (generator *schemaGenerator) ref(t reflect.Type) *Schema {
+	name, ok := generator.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := generator.schemas[name]; taken {
+			pkg := t.PkgPath()
+			name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+		}
+		generator.names[t] = name
+
+		// reserve the name before generating the properties, so recursive types terminate
+		generator.schemas[name] = &Schema{}
+		*generator.schemas[name] = *generator.structSchema(t)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (generator *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	generator.addFields(schema, t)
+	return schema
+}
+
+func (generator *schemaGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				generator.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = generator.schema(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
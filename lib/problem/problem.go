@@ -0,0 +1,106 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+)
+
+const ContentType = "application/problem+json"
+
+// Details is the RFC 7807 problem details object. Reason and Code are extension
+// members, so clients can distinguish problems without parsing the detail.
+type Details struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	Reason   string `json:"reason"`
+	Errors   any    `json:"errors,omitempty"`
+}
+
+// Problem is implemented by errors which describe themselves as problem details
+type Problem interface {
+	Problem() *Details
+}
+
+// New returns the details for a *shared_types.Error with the given values
+func New(code shared_types.Code, reason string, detail string) *Details {
+	return FromError(shared_types.NewError(code, reason, detail))
+}
+
+// FromError converts the error into problem details. Errors which are neither
+// a Problem nor a *shared_types.Error are converted with shared_types.AsError,
+// their message is not exposed.
+func FromError(err error) *Details {
+	var problem Problem
+	if errors.As(err, &problem) {
+		return problem.Problem()
+	}
+
+	sharedErr := shared_types.AsError(err)
+	status := sharedErr.Code.ToHTTPStatusCode()
+	return &Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: sharedErr.Message,
+		Code:   sharedErr.Code.String(),
+		Reason: sharedErr.Reason,
+	}
+}
+
+// Write answers the request with the problem details of the error
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	details := FromError(err)
+	if details.Instance == "" && r != nil {
+		details.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(details.Status)
+	if err := json.NewEncoder(w).Encode(details); err != nil {
+		ctx := context.Background()
+		if r != nil {
+			ctx = r.Context()
+		}
+		slog.ErrorContext(ctx, "could not write the problem details", "error", err)
+	}
+}
+
+func (details *Details) Error() string {
+	if details.Detail == "" {
+		return details.Title
+	}
+	return details.Detail
+}
+
+func (details *Details) Problem() *Details {
+	return details
+}
+
+// TooLarge returns the details for a request body larger than maxBytes
+func TooLarge(maxBytes int64) *Details {
+	return &Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusRequestEntityTooLarge),
+		Status: http.StatusRequestEntityTooLarge,
+		Detail: fmt.Sprintf("request body must not be larger than %d bytes", maxBytes),
+		Code:   shared_types.InvalidArgument.String(),
+		Reason: "REQUEST_BODY_TOO_LARGE",
+	}
+}
+
+// WithStatus overrides the HTTP status, for problems without a matching Code like 402 Payment Required
+func (details *Details) WithStatus(status int) *Details {
+	details.Status = status
+	details.Title = http.StatusText(status)
+	return details
+}
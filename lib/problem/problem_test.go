@@ -0,0 +1,79 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("should write a shared error as problem details", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/v1/books/1", nil)
+		err := shared_types.NewError(shared_types.NotFound, "BOOK_NOT_FOUND", "can't find the book")
+
+		// when
+		Write(w, r, fmt.Errorf("load book: %w", err))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "can't find the book",
+			"instance": "/api/v1/books/1",
+			"code": "NotFound",
+			"reason": "BOOK_NOT_FOUND"
+		}`, w.Body.String())
+	})
+
+	t.Run("should not expose the message of unknown errors", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/v1/books", nil)
+
+		// when
+		Write(w, r, errors.New("pq: connection refused"))
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Internal Server Error",
+			"status": 500,
+			"detail": "internal server error",
+			"instance": "/api/v1/books",
+			"code": "Internal",
+			"reason": "INTERNAL"
+		}`, w.Body.String())
+	})
+
+	t.Run("should write the details of a Problem", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/api/v1/books", nil)
+
+		// when
+		Write(w, r, TooLarge(10))
+
+		// then
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Request Entity Too Large",
+			"status": 413,
+			"detail": "request body must not be larger than 10 bytes",
+			"instance": "/api/v1/books",
+			"code": "InvalidArgument",
+			"reason": "REQUEST_BODY_TOO_LARGE"
+		}`, w.Body.String())
+	})
+}
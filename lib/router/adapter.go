@@ -0,0 +1,55 @@
+package router
+
+import (
+	"context"
+	"net/http"
+)
+
+// HandlerMiddleware is the common middleware signature of the net/http ecosystem
+type HandlerMiddleware func(http.Handler) http.Handler
+
+// responseWriterKey hands a ResponseWriter, which was replaced by an adapted
+// HandlerMiddleware, over to the next handler, because Next only passes the request
+type responseWriterKey struct{}
+
+type responseWriterHandoff struct {
+	w http.ResponseWriter
+}
+
+func withResponseWriter(r *http.Request, w http.ResponseWriter) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), responseWriterKey{}, &responseWriterHandoff{w}))
+}
+
+// responseWriter returns the ResponseWriter handed over with the request or w, if there is none.
+// The handoff is consumed, so later handlers use the ResponseWriter they are called with.
+func responseWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if handoff, ok := r.Context().Value(responseWriterKey{}).(*responseWriterHandoff); ok && handoff.w != nil {
+		w, handoff.w = handoff.w, nil
+	}
+	return w
+}
+
+// FromHandlerMiddleware converts a HandlerMiddleware into a MiddleWareFunc.
+// If the middleware wraps the ResponseWriter, the wrapped one is used by all following handlers.
+func FromHandlerMiddleware(middleware HandlerMiddleware) MiddleWareFunc {
+	return func(w http.ResponseWriter, r *http.Request, next Next) {
+		middleware(http.HandlerFunc(func(wrapped http.ResponseWriter, r *http.Request) {
+			if wrapped != w {
+				r = withResponseWriter(r, wrapped)
+			}
+			next(r)
+		})).ServeHTTP(w, r)
+	}
+}
+
+// ToHandlerMiddleware converts a MiddleWareFunc into a HandlerMiddleware,
+// so it can be used with plain net/http handlers.
+func ToHandlerMiddleware(middleware MiddleWareFunc) HandlerMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			middleware(w, r, func(r *http.Request) {
+				next.ServeHTTP(responseWriter(w, r), r)
+			})
+		})
+	}
+}
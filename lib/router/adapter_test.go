@@ -0,0 +1,120 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type headerWriter struct {
+	http.ResponseWriter
+}
+
+func (w headerWriter) WriteHeader(status int) {
+	w.Header().Set("X-Wrapped", "true")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func TestAdapter(t *testing.T) {
+	t.Run("FromHandlerMiddleware should run code after the handler", func(t *testing.T) {
+		// given
+		router := New()
+		var calls []string
+		router.USE("/", FromHandlerMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, "before")
+				next.ServeHTTP(w, r)
+				calls = append(calls, "after")
+			})
+		}))
+		router.GET("/", func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "handler")
+		})
+
+		// when
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+		// then
+		assert.Equal(t, []string{"before", "handler", "after"}, calls)
+	})
+
+	t.Run("FromHandlerMiddleware should pass the wrapped ResponseWriter to the handler", func(t *testing.T) {
+		// given
+		router := New()
+		router.GET("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}, FromHandlerMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(headerWriter{w}, r)
+			})
+		}))
+
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		// then
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "true", w.Header().Get("X-Wrapped"))
+	})
+
+	t.Run("FromHandlerMiddleware should not call the handler if the middleware doesn't call next", func(t *testing.T) {
+		// given
+		router := New()
+		called := false
+		router.GET("/", func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}, FromHandlerMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			})
+		}))
+
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ToHandlerMiddleware should call the next handler with the request of the middleware", func(t *testing.T) {
+		// given
+		var value string
+		handler := ToHandlerMiddleware(func(w http.ResponseWriter, r *http.Request, next Next) {
+			next(WithParam(r, "hello", "world"))
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value, _ = Param[string](r, "hello")
+		}))
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+		// then
+		assert.Equal(t, "world", value)
+	})
+
+	t.Run("ToHandlerMiddleware should not call the next handler if next isn't called", func(t *testing.T) {
+		// given
+		called := false
+		handler := ToHandlerMiddleware(func(w http.ResponseWriter, r *http.Request, next Next) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		w := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
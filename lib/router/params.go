@@ -0,0 +1,99 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+)
+
+// paramKey is private, so the route params can't clash with other values in the request context
+type paramKey string
+
+type ParamType interface {
+	~string | ~int64 | ~uint64 | ~float64 | ~bool
+}
+
+type paramParser func(string) (any, error)
+
+var paramParsers = map[string]paramParser{
+	"string": func(value string) (any, error) {
+		return value, nil
+	},
+	"int": func(value string) (any, error) {
+		return strconv.ParseInt(value, 10, 64)
+	},
+	"uint": func(value string) (any, error) {
+		return strconv.ParseUint(value, 10, 64)
+	},
+	"float": func(value string) (any, error) {
+		return strconv.ParseFloat(value, 64)
+	},
+	"bool": func(value string) (any, error) {
+		return strconv.ParseBool(value)
+	},
+}
+
+type param struct {
+	name     string
+	typeName string
+}
+
+// parseParam parses a pattern segment like ":bookid" or ":bookid<uint>".
+// Params without a type are strings.
+func parseParam(segment string) param {
+	name, typeName, found := strings.Cut(segment[1:], "<")
+	if !found {
+		return param{name, "string"}
+	}
+
+	typeName, found = strings.CutSuffix(typeName, ">")
+	if !found || name == "" {
+		panic("router: invalid param " + segment)
+	}
+	if _, ok := paramParsers[typeName]; !ok {
+		panic("router: unknown param type " + typeName + " in " + segment)
+	}
+
+	return param{name, typeName}
+}
+
+func (p param) String() string {
+	return ":" + p.name + "<" + p.typeName + ">"
+}
+
+// Param returns the value of the route param with the given name.
+// The second return value is false, if the param doesn't exist or has another type.
+// Params declared as <int>, <uint>, <float> or <bool> are stored as int64, uint64, float64 and bool.
+func Param[T ParamType](r *http.Request, name string) (T, bool) {
+	value, ok := r.Context().Value(paramKey(name)).(T)
+	return value, ok
+}
+
+// WithParam returns a shallow copy of r with the route param set to value.
+// The router uses it to store the params, so it is also useful for testing handlers.
+func WithParam(r *http.Request, name string, value any) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), paramKey(name), value))
+}
+
+// createRequestContext converts the raw param values and stores every param,
+// which wasn't already set by a previous middleware, in the request context.
+func createRequestContext(r *http.Request, params []param, values []string) (*http.Request, error) {
+	for i, param := range params {
+		if r.Context().Value(paramKey(param.name)) != nil {
+			continue
+		}
+
+		value, err := paramParsers[param.typeName](values[i])
+		if err != nil {
+			return nil, shared_types.NewError(shared_types.InvalidArgument, "INVALID_PARAM", fmt.Sprintf("param %s must be of type %s", param.name, param.typeName))
+		}
+
+		r = WithParam(r, param.name, value)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+// patternKey is private, so the recorded pattern can't clash with other values in the request context
+type patternKey struct{}
+
+// RecordPattern returns a shallow copy of r, in which the router records the pattern of the matched route.
+// Middlewares wrapping the router read it with MatchedPattern after the request was served.
+// If an outer middleware already prepared the request, r is returned, so all of them see the pattern.
+func RecordPattern(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(patternKey{}).(*atomic.Value); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), patternKey{}, &atomic.Value{}))
+}
+
+// MatchedPattern returns the pattern of the route, which served the request,
+// or "" if no route matched or the request wasn't prepared with RecordPattern.
+func MatchedPattern(r *http.Request) string {
+	return PatternFromContext(r.Context())
+}
+
+// PatternFromContext is like MatchedPattern for code, which only has the context of the request.
+// Inside of the handler the pattern is already recorded.
+func PatternFromContext(ctx context.Context) string {
+	recorded, ok := ctx.Value(patternKey{}).(*atomic.Value)
+	if !ok {
+		return ""
+	}
+	pattern, _ := recorded.Load().(string)
+	return pattern
+}
+
+// recordPattern is atomic, because handlers may be served in another goroutine like with http.TimeoutHandler
+func recordPattern(r *http.Request, pattern string) {
+	if recorded, ok := r.Context().Value(patternKey{}).(*atomic.Value); ok {
+		recorded.Store(pattern)
+	}
+}
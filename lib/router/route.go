@@ -0,0 +1,60 @@
+package router
+
+import (
+	"reflect"
+)
+
+type RouteParam struct {
+	Name string
+	// Type is one of the param types: string, int, uint, float or bool
+	Type string
+}
+
+type RouteResponse struct {
+	Status int
+	// Type is the type of the JSON body or nil, if the response has no body
+	Type reflect.Type
+}
+
+// Route describes a registered route. The metadata is optional and
+// only used for documentation like the OpenAPI spec.
+type Route struct {
+	Method      string
+	Pattern     string
+	Params      []RouteParam
+	QueryParams []RouteParam
+	Summary     string
+	RequestType reflect.Type
+	Responses   []RouteResponse
+}
+
+func (route *Route) Describe(summary string) *Route {
+	route.Summary = summary
+	return route
+}
+
+// Query documents a query param with one of the param types
+func (route *Route) Query(name string, typeName string) *Route {
+	if _, ok := paramParsers[typeName]; !ok {
+		panic("router: unknown param type " + typeName + " for query param " + name)
+	}
+	route.QueryParams = append(route.QueryParams, RouteParam{name, typeName})
+	return route
+}
+
+// Request documents the JSON body of the request with the type of body
+func (route *Route) Request(body any) *Route {
+	route.RequestType = reflect.TypeOf(body)
+	return route
+}
+
+// Response documents a possible response. Use nil as body for responses without a body.
+func (route *Route) Response(status int, body any) *Route {
+	route.Responses = append(route.Responses, RouteResponse{status, reflect.TypeOf(body)})
+	return route
+}
+
+// Routes returns all routes in the order they were registered
+func (router *Router) Routes() []*Route {
+	return append([]*Route(nil), router.routeList...)
+}
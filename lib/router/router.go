@@ -1,158 +1,236 @@
 package router
 
 import (
-	"context"
 	"net/http"
-	"regexp"
-)
+	"sort"
+	"strings"
 
-type route struct {
-	method  string
-	pattern *regexp.Regexp
-	handler http.HandlerFunc
-	params  []string
-}
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+)
 
 type Next func(r *http.Request)
 type MiddleWareFunc func(w http.ResponseWriter, r *http.Request, next Next)
 
 type middleware struct {
-	pattern *regexp.Regexp
+	order   int
 	handler MiddleWareFunc
-	params  []string
+	params  []param
+}
+
+type matchedMiddleware struct {
+	*middleware
+	values []string
+}
+
+// registry holds the trees, which are shared by a router and all of its groups
+type registry struct {
+	routes          *node
+	routeList       []*Route
+	middlewares     *node
+	middlewareCount int
 }
 
 type Router struct {
-	routes      []route
-	middlewares []middleware
+	*registry
+	prefix string
+	chain  []MiddleWareFunc
 }
 
 func New() *Router {
-	return &Router{}
+	return &Router{
+		registry: &registry{
+			routes:      newNode(),
+			middlewares: newNode(),
+		},
+	}
 }
 
-func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	for _, middleware := range router.middlewares {
-
-		matches := middleware.pattern.FindStringSubmatch(r.URL.Path)
+// Group returns a sub-router for all routes below prefix. The middlewares are
+// only called for routes registered on the group or its nested groups, after
+// the middlewares of the parent groups and before the middlewares of the route.
+// Use "" as pattern to register a route for the prefix itself.
+func (router *Router) Group(prefix string, middlewares ...MiddleWareFunc) *Router {
+	chain := make([]MiddleWareFunc, 0, len(router.chain)+len(middlewares))
+	chain = append(chain, router.chain...)
+	chain = append(chain, middlewares...)
+
+	return &Router{
+		registry: router.registry,
+		prefix:   router.prefix + strings.TrimSuffix(prefix, "/"),
+		chain:    chain,
+	}
+}
 
-		if len(matches) > 0 {
-			r = createRequestContext(r, middleware.params, matches[1:])
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
 
-			nextWasCalled := false
-			next := func(req *http.Request) {
-				nextWasCalled = true
-				r = req
-			}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		router.serveRoute(w, r, segments)
+	}
 
-			middleware.handler(w, r, next)
-			if !nextWasCalled {
+	middlewares := router.matchMiddlewares(segments)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware, next := middlewares[i], handler
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			r, err := createRequestContext(r, middleware.params, middleware.values)
+			if err != nil {
+				problem.Write(w, r, err)
 				return
 			}
+
+			middleware.handler(w, r, func(r *http.Request) {
+				next(responseWriter(w, r), r)
+			})
 		}
 	}
 
-	for _, route := range router.routes {
-		if r.Method != route.method {
-			continue
-		}
+	handler(w, r)
+}
 
-		matches := route.pattern.FindStringSubmatch(r.URL.Path)
+func (router *Router) serveRoute(w http.ResponseWriter, r *http.Request, segments []string) {
+	// a route with a handler for the method is preferred, so a static route with other methods
+	// doesn't hide a param route, otherwise the allowed methods of the first matching route are returned
+	node, values := router.routes.lookup(segments, nil, handles(r.Method))
+	if node == nil {
+		node, values = router.routes.lookup(segments, nil, hasHandlers)
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	recordPattern(r, node.pattern)
+
+	method := r.Method
+	handler, ok := node.handlers[method]
+	if !ok && method == http.MethodHead {
+		// net/http discards the body of responses to HEAD requests,
+		// so the GET handler can answer them as well
+		method = http.MethodGet
+		handler, ok = node.handlers[method]
+	}
 
-		if len(matches) > 0 {
-			// Check if the middleware already set the route params
-			for i, param := range route.params {
-				value := r.Context().Value(param)
-				if value == nil {
-					r = r.WithContext(context.WithValue(r.Context(), param, matches[1:][i]))
-				}
-			}
-			route.handler(w, r)
+	if !ok {
+		w.Header().Set("Allow", allowedMethods(node))
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
 			return
 		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
 	}
 
-	w.WriteHeader(http.StatusNotFound)
+	r, err := createRequestContext(r, node.params, values)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	handler(w, r)
 }
 
-func createRequestContext(r *http.Request, paramKeys []string, paramValues []string) *http.Request {
-	if len(paramKeys) == 0 {
-		return r
-	}
+func (router *Router) matchMiddlewares(segments []string) []matchedMiddleware {
+	var matched []matchedMiddleware
+	router.middlewares.collect(segments, nil, func(n *node, values []string) {
+		for _, middleware := range n.middlewares {
+			matched = append(matched, matchedMiddleware{middleware, values})
+		}
+	})
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].order < matched[j].order
+	})
 
-	ctx := r.Context()
-	for i := 0; i < len(paramKeys); i++ {
-		ctx = context.WithValue(ctx, paramKeys[i], paramValues[i])
+	return matched
+}
+
+func allowedMethods(n *node) string {
+	methods := make([]string, 0, len(n.handlers)+2)
+	for method := range n.handlers {
+		methods = append(methods, method)
+	}
+	if _, ok := n.handlers[http.MethodGet]; ok {
+		if _, ok := n.handlers[http.MethodHead]; !ok {
+			methods = append(methods, http.MethodHead)
+		}
+	}
+	if _, ok := n.handlers[http.MethodOptions]; !ok {
+		methods = append(methods, http.MethodOptions)
 	}
 
-	return r.WithContext(ctx)
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
 }
 
-func (router *Router) addRoute(method string, pattern string, handler http.HandlerFunc) {
-	paramMatcher := regexp.MustCompile(":([a-zA-Z]+)")
-	paramMatches := paramMatcher.FindAllStringSubmatch(pattern, -1)
+// chain wraps the handler, so that the middlewares are called in the given order
+// and every middleware only continues with the next one if it calls next.
+func chain(handler http.HandlerFunc, middlewares []MiddleWareFunc) http.HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware, next := middlewares[i], handler
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			middleware(w, r, func(r *http.Request) {
+				next(responseWriter(w, r), r)
+			})
+		}
+	}
+	return handler
+}
 
-	params := make([]string, len(paramMatches))
+// Handle registers the handler for the method and pattern. The middlewares are
+// only called for this route, after the middlewares of the groups.
+func (router *Router) Handle(method string, pattern string, handler http.HandlerFunc, middlewares ...MiddleWareFunc) *Route {
+	pattern = router.prefix + pattern
+	node := router.routes.insert(pattern)
 
-	if len(paramMatches) > 0 {
-		pattern = paramMatcher.ReplaceAllLiteralString(pattern, "([^/]+)")
+	if _, ok := node.handlers[method]; ok {
+		panic("router: a handler for " + method + " " + pattern + " is already registered")
+	}
 
-		for i, match := range paramMatches {
-			params[i] = match[1]
-		}
+	if len(router.chain) > 0 || len(middlewares) > 0 {
+		handler = chain(handler, append(router.chain[:len(router.chain):len(router.chain)], middlewares...))
 	}
+	node.handlers[method] = handler
+	node.pattern = pattern
 
-	router.routes = append(router.routes, route{
-		method:  method,
-		pattern: regexp.MustCompile("^" + pattern + "$"),
-		handler: handler,
-		params:  params,
-	})
+	route := &Route{Method: method, Pattern: pattern}
+	for _, param := range node.params {
+		route.Params = append(route.Params, RouteParam{param.name, param.typeName})
+	}
+	router.routeList = append(router.routeList, route)
+
+	return route
 }
 
-func (router *Router) GET(pattern string, handler http.HandlerFunc) {
-	router.addRoute(http.MethodGet, pattern, handler)
+func (router *Router) GET(pattern string, handler http.HandlerFunc, middlewares ...MiddleWareFunc) *Route {
+	return router.Handle(http.MethodGet, pattern, handler, middlewares...)
 }
 
-func (router *Router) POST(pattern string, handler http.HandlerFunc) {
-	router.addRoute(http.MethodPost, pattern, handler)
+func (router *Router) POST(pattern string, handler http.HandlerFunc, middlewares ...MiddleWareFunc) *Route {
+	return router.Handle(http.MethodPost, pattern, handler, middlewares...)
 }
 
-func (router *Router) PUT(pattern string, handler http.HandlerFunc) {
-	router.addRoute(http.MethodPut, pattern, handler)
+func (router *Router) PUT(pattern string, handler http.HandlerFunc, middlewares ...MiddleWareFunc) *Route {
+	return router.Handle(http.MethodPut, pattern, handler, middlewares...)
 }
 
-func (router *Router) PATCH(pattern string, handler http.HandlerFunc) {
-	router.addRoute(http.MethodPatch, pattern, handler)
+func (router *Router) PATCH(pattern string, handler http.HandlerFunc, middlewares ...MiddleWareFunc) *Route {
+	return router.Handle(http.MethodPatch, pattern, handler, middlewares...)
 }
 
-func (router *Router) DELETE(pattern string, handler http.HandlerFunc) {
-	router.addRoute(http.MethodDelete, pattern, handler)
+func (router *Router) DELETE(pattern string, handler http.HandlerFunc, middlewares ...MiddleWareFunc) *Route {
+	return router.Handle(http.MethodDelete, pattern, handler, middlewares...)
 }
 
+// USE registers a middleware for the pattern and all paths below it. It is called
+// before the route is matched, so it also runs for unknown paths and methods.
 func (router *Router) USE(pattern string, handler MiddleWareFunc) *Router {
-	var params []string
-
-	pattern = pattern + "(.*)"
-
-	paramMatcher := regexp.MustCompile(":([a-zA-Z]+)")
-	paramMatches := paramMatcher.FindAllStringSubmatch(pattern, -1)
-
-	params = make([]string, len(paramMatches))
-
-	if len(paramMatches) > 0 {
-		pattern = paramMatcher.ReplaceAllLiteralString(pattern, "([^/]+)")
-
-		for i, match := range paramMatches {
-			params[i] = match[1]
-		}
-	}
+	node := router.middlewares.insert(router.prefix + pattern)
 
-	router.middlewares = append(router.middlewares, middleware{
-		pattern: regexp.MustCompile("^" + pattern + "$"),
+	node.middlewares = append(node.middlewares, &middleware{
+		order:   router.middlewareCount,
 		handler: handler,
-		params:  params,
+		params:  node.params,
 	})
+	router.middlewareCount++
 
 	return router
 }
@@ -2,10 +2,12 @@ package router
 
 import (
 	"context"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -56,8 +58,8 @@ func TestRouter(t *testing.T) {
 
 		// then
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Equal(t, "route", ctx.Value("route"))
-		assert.Equal(t, "params", ctx.Value("params"))
+		assert.Equal(t, "route", ctx.Value(paramKey("route")))
+		assert.Equal(t, "params", ctx.Value(paramKey("params")))
 	})
 
 	t.Run("should not call middleware", func(t *testing.T) {
@@ -84,7 +86,7 @@ func TestRouter(t *testing.T) {
 		router := New()
 
 		router.USE("/the/route/without/params", func(w http.ResponseWriter, r *http.Request, next Next) {
-			next(createRequestContext(r, []string{"hello"}, []string{"world"}))
+			next(WithParam(r, "hello", "world"))
 		})
 
 		var ctx context.Context
@@ -99,7 +101,7 @@ func TestRouter(t *testing.T) {
 		router.ServeHTTP(w, r)
 
 		// then
-		assert.Equal(t, "world", ctx.Value("hello"))
+		assert.Equal(t, "world", ctx.Value(paramKey("hello")))
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
@@ -140,8 +142,8 @@ func TestRouter(t *testing.T) {
 
 		// then
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Equal(t, "route", ctx.Value("route"))
-		assert.Equal(t, "params", ctx.Value("params"))
+		assert.Equal(t, "route", ctx.Value(paramKey("route")))
+		assert.Equal(t, "params", ctx.Value(paramKey("params")))
 	})
 
 	t.Run("don't route if next in middleware is not called", func(t *testing.T) {
@@ -168,4 +170,466 @@ func TestRouter(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, s, []byte{})
 	})
+	t.Run("should return 405 METHOD NOT ALLOWED with allowed methods if path is known", func(t *testing.T) {
+		// given
+		router := New()
+		router.GET("/the/route", func(w http.ResponseWriter, r *http.Request) {})
+		router.POST("/the/route", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/the/route", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, HEAD, OPTIONS, POST", w.Header().Get("Allow"))
+	})
+
+	t.Run("should answer HEAD with the GET handler", func(t *testing.T) {
+		// given
+		router := New()
+		called := false
+		router.GET("/the/route", func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("HEAD", "/the/route", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.True(t, called)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("should answer OPTIONS with the allowed methods", func(t *testing.T) {
+		// given
+		router := New()
+		router.PATCH("/the/:route", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("OPTIONS", "/the/route", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "OPTIONS, PATCH", w.Header().Get("Allow"))
+	})
+
+	t.Run("should prefer static segments over params", func(t *testing.T) {
+		// given
+		router := New()
+		var matched string
+		router.GET("/users/:userid", func(w http.ResponseWriter, r *http.Request) {
+			matched, _ = Param[string](r, "userid")
+		})
+		router.GET("/users/me", func(w http.ResponseWriter, r *http.Request) {
+			matched = "static"
+		})
+
+		// when
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/me", nil))
+
+		// then
+		assert.Equal(t, "static", matched)
+
+		// when
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+
+		// then
+		assert.Equal(t, "42", matched)
+	})
+
+	t.Run("should fall back to params if the static branch doesn't match", func(t *testing.T) {
+		// given
+		router := New()
+		var matched string
+		router.GET("/books/new", func(w http.ResponseWriter, r *http.Request) {})
+		router.GET("/books/:bookid/chapters", func(w http.ResponseWriter, r *http.Request) {
+			matched, _ = Param[string](r, "bookid")
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/books/new/chapters", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "new", matched)
+	})
+
+	t.Run("should fall back to params if the static route doesn't handle the method", func(t *testing.T) {
+		// given
+		router := New()
+		var matched string
+		router.POST("/books/new", func(w http.ResponseWriter, r *http.Request) {
+			matched = "static"
+		})
+		router.GET("/books/:bookid", func(w http.ResponseWriter, r *http.Request) {
+			matched, _ = Param[string](r, "bookid")
+		})
+
+		// when
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/books/new", nil))
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "new", matched)
+
+		// when
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/books/new", nil))
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "static", matched)
+
+		// when
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("DELETE", "/books/new", nil))
+
+		// then
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "OPTIONS, POST", w.Header().Get("Allow"))
+	})
+
+	t.Run("should call middlewares in registration order", func(t *testing.T) {
+		// given
+		router := New()
+		var calls []string
+		router.USE("/the/:route", func(w http.ResponseWriter, r *http.Request, next Next) {
+			calls = append(calls, "param")
+			next(r)
+		})
+		router.USE("/", func(w http.ResponseWriter, r *http.Request, next Next) {
+			calls = append(calls, "root")
+			next(r)
+		})
+		router.USE("/the/route", func(w http.ResponseWriter, r *http.Request, next Next) {
+			calls = append(calls, "static")
+			next(r)
+		})
+		router.GET("/the/route/sub", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route/sub", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"param", "root", "static"}, calls)
+	})
+
+	t.Run("should convert typed params", func(t *testing.T) {
+		// given
+		router := New()
+		var bookId uint64
+		var offset int64
+		var found bool
+		router.GET("/books/:bookid<uint>/chapters/:offset<int>", func(w http.ResponseWriter, r *http.Request) {
+			bookId, found = Param[uint64](r, "bookid")
+			offset, _ = Param[int64](r, "offset")
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/books/42/chapters/-1", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, found)
+		assert.Equal(t, uint64(42), bookId)
+		assert.Equal(t, int64(-1), offset)
+	})
+
+	t.Run("should return 400 BAD REQUEST if a typed param can't be converted", func(t *testing.T) {
+		// given
+		router := New()
+		called := false
+		router.GET("/books/:bookid<uint>", func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/books/abc", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"reason":"INVALID_PARAM"`)
+	})
+
+	t.Run("should return 400 BAD REQUEST before the middleware if a typed param can't be converted", func(t *testing.T) {
+		// given
+		router := New()
+		called := false
+		router.USE("/books/:bookid<uint>", func(w http.ResponseWriter, r *http.Request, next Next) {
+			called = true
+			next(r)
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/books/-1/chapters", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"reason":"INVALID_PARAM"`)
+	})
+
+	t.Run("Param should not return params of another type", func(t *testing.T) {
+		// given
+		r := WithParam(httptest.NewRequest("GET", "/", nil), "bookid", "1")
+
+		// when
+		_, uintFound := Param[uint64](r, "bookid")
+		value, stringFound := Param[string](r, "bookid")
+		_, missingFound := Param[string](r, "missing")
+
+		// then
+		assert.False(t, uintFound)
+		assert.True(t, stringFound)
+		assert.Equal(t, "1", value)
+		assert.False(t, missingFound)
+	})
+
+	t.Run("should not clash with plain string context keys", func(t *testing.T) {
+		// given
+		router := New()
+		var ctx context.Context
+		router.GET("/:user", func(w http.ResponseWriter, r *http.Request) {
+			ctx = r.Context()
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/alice", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Nil(t, ctx.Value("user"))
+		assert.Equal(t, "alice", ctx.Value(paramKey("user")))
+	})
+
+	t.Run("should panic on unknown param types", func(t *testing.T) {
+		// given
+		router := New()
+
+		// then
+		assert.Panics(t, func() {
+			router.GET("/books/:bookid<uuid>", func(w http.ResponseWriter, r *http.Request) {})
+		})
+	})
+
+	t.Run("should call group middlewares only for routes of the group", func(t *testing.T) {
+		// given
+		router := New()
+		var calls []string
+		books := router.Group("/books", func(w http.ResponseWriter, r *http.Request, next Next) {
+			calls = append(calls, "books")
+			next(r)
+		})
+		books.GET("", func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "handler")
+		})
+		router.GET("/books/new", func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "new")
+		})
+
+		// when
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/books", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/books/new", nil))
+
+		// then
+		assert.Equal(t, []string{"books", "handler", "new"}, calls)
+	})
+
+	t.Run("should call group, nested group and route middlewares in order", func(t *testing.T) {
+		// given
+		router := New()
+		var calls []string
+		middleware := func(name string) MiddleWareFunc {
+			return func(w http.ResponseWriter, r *http.Request, next Next) {
+				calls = append(calls, name)
+				next(r)
+			}
+		}
+
+		book := router.Group("/books", middleware("books")).Group("/:bookid<uint>", middleware("book"))
+		book.Handle(http.MethodPut, "/chapters", func(w http.ResponseWriter, r *http.Request) {
+			bookId, _ := Param[uint64](r, "bookid")
+			assert.Equal(t, uint64(1), bookId)
+			calls = append(calls, "handler")
+		}, middleware("route"))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("PUT", "/books/1/chapters", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"books", "book", "route", "handler"}, calls)
+	})
+
+	t.Run("should not call the handler if a group middleware doesn't call next", func(t *testing.T) {
+		// given
+		router := New()
+		called := false
+		group := router.Group("/books", func(w http.ResponseWriter, r *http.Request, next Next) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		group.GET("", func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/books", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should pass the request of the middleware to the handler", func(t *testing.T) {
+		// given
+		router := New()
+		var value string
+		router.GET("/books", func(w http.ResponseWriter, r *http.Request) {
+			value, _ = Param[string](r, "hello")
+		}, func(w http.ResponseWriter, r *http.Request, next Next) {
+			next(WithParam(r, "hello", "world"))
+		})
+
+		// when
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/books", nil))
+
+		// then
+		assert.Equal(t, "world", value)
+	})
+
+	t.Run("should not call group middlewares for unknown methods", func(t *testing.T) {
+		// given
+		router := New()
+		called := false
+		group := router.Group("/books", func(w http.ResponseWriter, r *http.Request, next Next) {
+			called = true
+			next(r)
+		})
+		group.GET("", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/books", nil)
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.False(t, called)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("should return the registered routes with their metadata", func(t *testing.T) {
+		// given
+		router := New()
+		router.GET("/books", func(w http.ResponseWriter, r *http.Request) {}).
+			Describe("Get all books").
+			Query("userId", "uint").
+			Response(http.StatusOK, []string{})
+		router.Group("/books/:bookid<uint>").PATCH("", func(w http.ResponseWriter, r *http.Request) {}).
+			Request(struct{ Name string }{}).
+			Response(http.StatusNoContent, nil)
+
+		// when
+		routes := router.Routes()
+
+		// then
+		assert.Len(t, routes, 2)
+		assert.Equal(t, http.MethodGet, routes[0].Method)
+		assert.Equal(t, "/books", routes[0].Pattern)
+		assert.Equal(t, "Get all books", routes[0].Summary)
+		assert.Equal(t, []RouteParam{{"userId", "uint"}}, routes[0].QueryParams)
+		assert.Equal(t, reflect.TypeOf([]string{}), routes[0].Responses[0].Type)
+
+		assert.Equal(t, "/books/:bookid<uint>", routes[1].Pattern)
+		assert.Equal(t, []RouteParam{{"bookid", "uint"}}, routes[1].Params)
+		assert.Equal(t, reflect.TypeOf(struct{ Name string }{}), routes[1].RequestType)
+		assert.Equal(t, []RouteResponse{{http.StatusNoContent, nil}}, routes[1].Responses)
+	})
+
+	t.Run("should record the pattern of the matched route", func(t *testing.T) {
+		// given
+		router := New()
+		router.Group("/books").GET("/:bookid<uint>", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		r := RecordPattern(httptest.NewRequest("GET", "/books/1", nil))
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/books/:bookid<uint>", MatchedPattern(r))
+	})
+
+	t.Run("should not record a pattern if no route matched", func(t *testing.T) {
+		// given
+		router := New()
+		router.GET("/books", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		r := RecordPattern(httptest.NewRequest("GET", "/unknown", nil))
+
+		// when
+		router.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "", MatchedPattern(r))
+	})
+
+	t.Run("should record the pattern for all middlewares which prepared the request", func(t *testing.T) {
+		// given
+		router := New()
+		router.GET("/books", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		outer := RecordPattern(httptest.NewRequest("GET", "/books", nil))
+		inner := RecordPattern(outer)
+
+		// when
+		router.ServeHTTP(w, inner)
+
+		// then
+		assert.Equal(t, "/books", MatchedPattern(outer))
+		assert.Equal(t, "/books", MatchedPattern(inner))
+	})
 }
@@ -0,0 +1,133 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+)
+
+type node struct {
+	children    map[string]*node
+	param       *node
+	paramDef    param
+	params      []param
+	pattern     string
+	handlers    map[string]http.HandlerFunc
+	middlewares []*middleware
+}
+
+func newNode() *node {
+	return &node{
+		children: make(map[string]*node),
+		handlers: make(map[string]http.HandlerFunc),
+	}
+}
+
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(segment string) bool {
+	return len(segment) > 1 && segment[0] == ':'
+}
+
+// insert walks the tree along the segments of the pattern and creates every
+// missing node. It returns the node of the last segment, which knows the names
+// and types of all params in the order they appear in the pattern.
+func (n *node) insert(pattern string) *node {
+	var params []param
+	current := n
+
+	for _, segment := range splitPath(pattern) {
+		if isParam(segment) {
+			def := parseParam(segment)
+			if current.param == nil {
+				current.param = newNode()
+				current.param.paramDef = def
+			} else if current.param.paramDef != def {
+				panic("router: conflicting params " + current.param.paramDef.String() + " and " + def.String() + " in pattern " + pattern)
+			}
+			params = append(params, def)
+			current = current.param
+			continue
+		}
+
+		child, ok := current.children[segment]
+		if !ok {
+			child = newNode()
+			current.children[segment] = child
+		}
+		current = child
+	}
+
+	current.params = params
+	return current
+}
+
+// lookup finds the node matching all segments, which is accepted by accepts. Static segments take
+// precedence over params, if the static branch leads to a dead end or to a node, which isn't accepted,
+// the param branch is tried. The param values are returned in the order they appear in the path.
+func (n *node) lookup(segments []string, values []string, accepts func(*node) bool) (*node, []string) {
+	if len(segments) == 0 {
+		if !accepts(n) {
+			return nil, nil
+		}
+		return n, values
+	}
+
+	segment := segments[0]
+
+	if child, ok := n.children[segment]; ok {
+		if found, foundValues := child.lookup(segments[1:], values, accepts); found != nil {
+			return found, foundValues
+		}
+	}
+
+	if n.param != nil && segment != "" {
+		if found, foundValues := n.param.lookup(segments[1:], append(values, segment), accepts); found != nil {
+			return found, foundValues
+		}
+	}
+
+	return nil, nil
+}
+
+// hasHandlers accepts every node with a route
+func hasHandlers(n *node) bool {
+	return len(n.handlers) > 0
+}
+
+// handles accepts the nodes with a handler for the method, HEAD is also handled by GET
+func handles(method string) func(*node) bool {
+	return func(n *node) bool {
+		if _, ok := n.handlers[method]; ok {
+			return true
+		}
+		_, ok := n.handlers[http.MethodGet]
+		return ok && method == http.MethodHead
+	}
+}
+
+// collect calls fn for every node whose pattern is a prefix of the path,
+// together with the param values up to this node. Unlike lookup every matching
+// branch is visited, because every matching middleware has to be called.
+func (n *node) collect(segments []string, values []string, fn func(*node, []string)) {
+	fn(n, values)
+
+	if len(segments) == 0 {
+		return
+	}
+
+	segment := segments[0]
+
+	if child, ok := n.children[segment]; ok {
+		child.collect(segments[1:], values, fn)
+	}
+
+	if n.param != nil && segment != "" {
+		n.param.collect(segments[1:], append(values[:len(values):len(values)], segment), fn)
+	}
+}
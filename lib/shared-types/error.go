@@ -0,0 +1,124 @@
+package shared_types
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details of all gRPC errors
+const ErrorDomain = "hsfl-master-ai-cloud-engineering"
+
+// Error pairs a Code with a machine-readable reason like "BOOK_NOT_FOUND" and a message for humans.
+// It is rendered as application/problem+json by the problem-package and as gRPC status with ErrorInfo details.
+// The wrapped cause is only part of Error(), so it shows up in logs but is never sent to clients.
+type Error struct {
+	Code    Code
+	Reason  string
+	Message string
+	Err     error
+}
+
+func NewError(code Code, reason string, message string) *Error {
+	return &Error{Code: code, Reason: reason, Message: message}
+}
+
+// WrapError returns an *Error with err as cause
+func WrapError(err error, code Code, reason string, message string) *Error {
+	return &Error{Code: code, Reason: reason, Message: message, Err: err}
+}
+
+func (err *Error) Error() string {
+	if err.Err == nil {
+		return err.Message
+	}
+	return err.Message + ": " + err.Err.Error()
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Is reports whether target is an *Error with the same code and reason,
+// so errors.Is works with predefined errors even if they were wrapped.
+func (err *Error) Is(target error) bool {
+	targetErr, ok := target.(*Error)
+	return ok && targetErr.Code == err.Code && targetErr.Reason == err.Reason
+}
+
+// AsError returns the first *Error in the chain of err. Errors of an exceeded
+// context deadline become DeadlineExceeded, all other errors become Internal.
+func AsError(err error) *Error {
+	var sharedErr *Error
+	switch {
+	case errors.As(err, &sharedErr):
+		return sharedErr
+	case errors.Is(err, context.DeadlineExceeded):
+		return WrapError(err, DeadlineExceeded, "DEADLINE_EXCEEDED", "deadline exceeded")
+	default:
+		return WrapError(err, Internal, "INTERNAL", "internal server error")
+	}
+}
+
+// WrapNotFound returns a NotFound error with the reason, if err is sql.ErrNoRows. Other errors of a
+// repository keep their code with AsError, so a failing database isn't reported as a missing row.
+func WrapNotFound(err error, reason string, message string) *Error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return WrapError(err, NotFound, reason, message)
+	}
+	return AsError(err)
+}
+
+// CodeOf returns the Code of err, it is OK for nil
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+	return AsError(err).Code
+}
+
+// GRPCStatus is used by grpc to convert the error into a status
+func (err *Error) GRPCStatus() *status.Status {
+	st := status.New(err.Code.ToGRPCStatusCode(), err.Message)
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   err.Reason,
+		Domain:   ErrorDomain,
+		Metadata: map[string]string{"code": err.Code.String()},
+	})
+	if detailsErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromGRPCError converts an error returned by a grpc client back into an *Error.
+// Errors without ErrorInfo details get the reason "UNKNOWN". Their message is only kept, if they aren't
+// Internal or Unavailable, otherwise transport errors would reach the clients, it stays in the cause instead.
+func FromGRPCError(err error) *Error {
+	var sharedErr *Error
+	if errors.As(err, &sharedErr) {
+		return sharedErr
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return WrapError(err, Internal, "UNKNOWN", "internal server error")
+	}
+
+	code := fromGRPCStatusCode(st.Code())
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+			return NewError(code, info.Reason, st.Message())
+		}
+	}
+	switch code {
+	case Internal:
+		return WrapError(err, code, "UNKNOWN", "internal server error")
+	case Unavailable:
+		return WrapError(err, code, "UNKNOWN", "service unavailable")
+	}
+	return NewError(code, "UNKNOWN", st.Message())
+}
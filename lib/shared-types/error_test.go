@@ -0,0 +1,141 @@
+package shared_types
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestError(t *testing.T) {
+	t.Run("should be converted into a grpc status with ErrorInfo details", func(t *testing.T) {
+		// given
+		err := NewError(NotFound, "CHAPTER_NOT_FOUND", "chapter not found")
+
+		// when
+		st, ok := status.FromError(err)
+
+		// then
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "chapter not found", st.Message())
+		assert.Len(t, st.Details(), 1)
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "CHAPTER_NOT_FOUND", info.Reason)
+		assert.Equal(t, ErrorDomain, info.Domain)
+	})
+
+	t.Run("FromGRPCError", func(t *testing.T) {
+		t.Run("should restore the error from the status details", func(t *testing.T) {
+			// given
+			grpcErr := NewError(InvalidArgument, "CHAPTER_NOT_PUBLISHED", "chapter is not published").GRPCStatus().Err()
+
+			// when
+			err := FromGRPCError(grpcErr)
+
+			// then
+			assert.Equal(t, NewError(InvalidArgument, "CHAPTER_NOT_PUBLISHED", "chapter is not published"), err)
+		})
+
+		t.Run("should use the reason UNKNOWN for a status without details", func(t *testing.T) {
+			// given
+			grpcErr := status.Error(codes.Unauthenticated, "invalid token")
+
+			// when
+			err := FromGRPCError(grpcErr)
+
+			// then
+			assert.Equal(t, NewError(Unauthenticated, "UNKNOWN", "invalid token"), err)
+		})
+
+		t.Run("should hide the message of internal and unavailable statuses", func(t *testing.T) {
+			// given
+			grpcErr := status.Error(codes.Unavailable, "connection error: dial tcp 10.0.0.7:9090: connect: connection refused")
+
+			// when
+			err := FromGRPCError(grpcErr)
+
+			// then
+			assert.Equal(t, Unavailable, err.Code)
+			assert.Equal(t, "service unavailable", err.Message)
+			assert.ErrorIs(t, err, grpcErr)
+		})
+
+		t.Run("should return internal errors for other errors", func(t *testing.T) {
+			// given
+			cause := errors.New("connection refused")
+
+			// when
+			err := FromGRPCError(cause)
+
+			// then
+			assert.Equal(t, Internal, err.Code)
+			assert.Equal(t, "internal server error", err.Message)
+			assert.ErrorIs(t, err, cause)
+		})
+	})
+	t.Run("should be found with errors.As and errors.Is if it is wrapped", func(t *testing.T) {
+		// given
+		cause := errors.New("sql: no rows in result set")
+		err := fmt.Errorf("load book: %w", WrapError(cause, NotFound, "BOOK_NOT_FOUND", "book not found"))
+
+		// when
+		var sharedErr *Error
+		ok := errors.As(err, &sharedErr)
+
+		// then
+		assert.True(t, ok)
+		assert.Equal(t, NotFound, sharedErr.Code)
+		assert.ErrorIs(t, err, cause)
+		assert.ErrorIs(t, err, NewError(NotFound, "BOOK_NOT_FOUND", "another message"))
+		assert.NotErrorIs(t, err, NewError(NotFound, "CHAPTER_NOT_FOUND", "book not found"))
+		assert.Equal(t, "load book: book not found: sql: no rows in result set", err.Error())
+	})
+
+	t.Run("WrapNotFound", func(t *testing.T) {
+		t.Run("should return NotFound for a missing row", func(t *testing.T) {
+			// when
+			err := WrapNotFound(fmt.Errorf("scan: %w", sql.ErrNoRows), "BOOK_NOT_FOUND", "book not found")
+
+			// then
+			assert.Equal(t, NotFound, err.Code)
+			assert.Equal(t, "BOOK_NOT_FOUND", err.Reason)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+		})
+
+		t.Run("should keep the code of other errors", func(t *testing.T) {
+			// when
+			internal := WrapNotFound(errors.New("connection refused"), "BOOK_NOT_FOUND", "book not found")
+			deadline := WrapNotFound(context.DeadlineExceeded, "BOOK_NOT_FOUND", "book not found")
+
+			// then
+			assert.Equal(t, Internal, internal.Code)
+			assert.Equal(t, DeadlineExceeded, deadline.Code)
+		})
+	})
+
+	t.Run("CodeOf", func(t *testing.T) {
+		tests := []struct {
+			name string
+			err  error
+			code Code
+		}{
+			{"nil", nil, OK},
+			{"shared error", fmt.Errorf("wrapped: %w", NewError(PermissionDenied, "NOT_BOOK_OWNER", "not the owner")), PermissionDenied},
+			{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), DeadlineExceeded},
+			{"other error", errors.New("connection refused"), Internal},
+		}
+
+		for _, test := range tests {
+			t.Run("should return the code of "+test.name, func(t *testing.T) {
+				assert.Equal(t, test.code, CodeOf(test.err))
+			})
+		}
+	})
+}
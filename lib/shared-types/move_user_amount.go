@@ -1,15 +1,11 @@
 package shared_types
 
 type MoveBalanceRequest struct {
-	UserId          uint64 `json:"userId"`
-	ReceivingUserId uint64 `json:"receivingUserId"`
+	UserId          uint64 `json:"userId" validate:"required"`
+	ReceivingUserId uint64 `json:"receivingUserId" validate:"required"`
 	Amount          int64  `json:"amount"`
 }
 
-func (r *MoveBalanceRequest) IsValid() bool {
-	return r.UserId != 0 && r.ReceivingUserId != 0
-}
-
 type MoveBalanceResponse struct {
 	Success bool `json:"success"`
 }
@@ -14,6 +14,13 @@ const (
 	NotFound
 	Internal
 	Unauthenticated
+	PermissionDenied
+	AlreadyExists
+	FailedPrecondition
+	ResourceExhausted
+	Unavailable
+	DeadlineExceeded
+	Aborted
 )
 
 func (c Code) String() string {
@@ -28,16 +35,31 @@ func (c Code) String() string {
 		return "Internal"
 	case Unauthenticated:
 		return "Unauthenticated"
+	case PermissionDenied:
+		return "PermissionDenied"
+	case AlreadyExists:
+		return "AlreadyExists"
+	case FailedPrecondition:
+		return "FailedPrecondition"
+	case ResourceExhausted:
+		return "ResourceExhausted"
+	case Unavailable:
+		return "Unavailable"
+	case DeadlineExceeded:
+		return "DeadlineExceeded"
+	case Aborted:
+		return "Aborted"
 	default:
 		return "Unknown"
 	}
 }
 
+// ToHTTPStatusCode maps the code like the HTTP mapping of google.rpc.Code
 func (c Code) ToHTTPStatusCode() int {
 	switch c {
 	case OK:
 		return http.StatusOK
-	case InvalidArgument:
+	case InvalidArgument, FailedPrecondition:
 		return http.StatusBadRequest
 	case NotFound:
 		return http.StatusNotFound
@@ -45,6 +67,16 @@ func (c Code) ToHTTPStatusCode() int {
 		return http.StatusInternalServerError
 	case Unauthenticated:
 		return http.StatusUnauthorized
+	case PermissionDenied:
+		return http.StatusForbidden
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
 	default:
 		return http.StatusInternalServerError
 	}
@@ -62,7 +94,50 @@ func (c Code) ToGRPCStatusCode() codes.Code {
 		return codes.Internal
 	case Unauthenticated:
 		return codes.Unauthenticated
+	case PermissionDenied:
+		return codes.PermissionDenied
+	case AlreadyExists:
+		return codes.AlreadyExists
+	case FailedPrecondition:
+		return codes.FailedPrecondition
+	case ResourceExhausted:
+		return codes.ResourceExhausted
+	case Unavailable:
+		return codes.Unavailable
+	case DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case Aborted:
+		return codes.Aborted
 	default:
 		return codes.Internal
 	}
 }
+
+func fromGRPCStatusCode(code codes.Code) Code {
+	switch code {
+	case codes.OK:
+		return OK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return InvalidArgument
+	case codes.NotFound:
+		return NotFound
+	case codes.Unauthenticated:
+		return Unauthenticated
+	case codes.PermissionDenied:
+		return PermissionDenied
+	case codes.AlreadyExists:
+		return AlreadyExists
+	case codes.FailedPrecondition:
+		return FailedPrecondition
+	case codes.ResourceExhausted:
+		return ResourceExhausted
+	case codes.Unavailable:
+		return Unavailable
+	case codes.DeadlineExceeded:
+		return DeadlineExceeded
+	case codes.Aborted:
+		return Aborted
+	default:
+		return Internal
+	}
+}
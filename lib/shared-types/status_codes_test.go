@@ -0,0 +1,49 @@
+package shared_types
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		code       Code
+		name       string
+		httpStatus int
+		grpcCode   codes.Code
+	}{
+		{OK, "OK", http.StatusOK, codes.OK},
+		{InvalidArgument, "InvalidArgument", http.StatusBadRequest, codes.InvalidArgument},
+		{NotFound, "NotFound", http.StatusNotFound, codes.NotFound},
+		{Internal, "Internal", http.StatusInternalServerError, codes.Internal},
+		{Unauthenticated, "Unauthenticated", http.StatusUnauthorized, codes.Unauthenticated},
+		{PermissionDenied, "PermissionDenied", http.StatusForbidden, codes.PermissionDenied},
+		{AlreadyExists, "AlreadyExists", http.StatusConflict, codes.AlreadyExists},
+		{FailedPrecondition, "FailedPrecondition", http.StatusBadRequest, codes.FailedPrecondition},
+		{ResourceExhausted, "ResourceExhausted", http.StatusTooManyRequests, codes.ResourceExhausted},
+		{Unavailable, "Unavailable", http.StatusServiceUnavailable, codes.Unavailable},
+		{DeadlineExceeded, "DeadlineExceeded", http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{Aborted, "Aborted", http.StatusConflict, codes.Aborted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.name, test.code.String())
+			assert.Equal(t, test.httpStatus, test.code.ToHTTPStatusCode())
+			assert.Equal(t, test.grpcCode, test.code.ToGRPCStatusCode())
+			assert.Equal(t, test.code, fromGRPCStatusCode(test.grpcCode))
+		})
+	}
+
+	t.Run("unknown codes should be internal errors", func(t *testing.T) {
+		code := Code(100)
+
+		assert.Equal(t, "Unknown", code.String())
+		assert.Equal(t, http.StatusInternalServerError, code.ToHTTPStatusCode())
+		assert.Equal(t, codes.Internal, code.ToGRPCStatusCode())
+		assert.Equal(t, Internal, fromGRPCStatusCode(codes.Unknown))
+	})
+}
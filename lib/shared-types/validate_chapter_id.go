@@ -1,15 +1,11 @@
 package shared_types
 
 type ValidateChapterIdRequest struct {
-	UserId    uint64 `json:"userId"`
-	ChapterId uint64 `json:"chapterId"`
+	UserId    uint64 `json:"userId" validate:"required"`
+	ChapterId uint64 `json:"chapterId" validate:"required"`
 	BookId    uint64 `json:"bookId"`
 }
 
-func (r *ValidateChapterIdRequest) IsValid() bool {
-	return r.UserId != 0 && r.ChapterId != 0
-}
-
 type ValidateChapterIdResponse struct {
 	ChapterId       uint64 `json:"chapterId"`
 	BookId          uint64 `json:"bookId"`
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGRPC(t *testing.T) {
+	t.Run("should continue the trace of the client on the server", func(t *testing.T) {
+		// given
+		recorder := useRecorder(t)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		srv := grpc.NewServer(ServerOption())
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+		go srv.Serve(listener)
+		defer srv.Stop()
+
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()), DialOption())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		// when
+		_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool { return len(recorder.Ended()) == 2 }, time.Second, 10*time.Millisecond)
+
+		spans := map[trace.SpanKind]trace.SpanContext{}
+		var serverParent trace.SpanContext
+		for _, span := range recorder.Ended() {
+			spans[span.SpanKind()] = span.SpanContext()
+			if span.SpanKind() == trace.SpanKindServer {
+				serverParent = span.Parent()
+			}
+		}
+		assert.Equal(t, spans[trace.SpanKindClient].TraceID(), spans[trace.SpanKindServer].TraceID())
+		assert.Equal(t, spans[trace.SpanKindClient].SpanID(), serverParent.SpanID())
+	})
+}
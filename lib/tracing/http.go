@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTP continues the trace of the request or starts a new one and creates a span for every request.
+// The span is named with the method and the pattern of the route, if handler is a router.Router.
+func HTTP(next http.Handler) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = router.RecordPattern(r)
+
+		next.ServeHTTP(w, r)
+
+		if pattern := router.MatchedPattern(r); pattern != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + pattern)
+			span.SetAttributes(semconv.HTTPRoute(pattern))
+		}
+	})
+
+	return otelhttp.NewHandler(handler, "http.server", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method
+	}))
+}
+
+// Transport creates a client span for every request sent by next and injects its trace context into the headers
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(next)
+}
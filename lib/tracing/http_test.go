@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHTTP(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("should continue the trace of the request", func(t *testing.T) {
+		// given
+		recorder := useRecorder(t)
+		var spanContext trace.SpanContext
+		handler := HTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			spanContext = trace.SpanContextFromContext(r.Context())
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/books", nil)
+		r.Header.Set("traceparent", traceparent)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		// then
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	})
+
+	t.Run("should name the span with the route pattern", func(t *testing.T) {
+		// given
+		recorder := useRecorder(t)
+		r := router.New()
+		r.GET("/books/:bookid<uint>", func(w http.ResponseWriter, r *http.Request) {})
+		handler := HTTP(r)
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/books/1", nil))
+
+		// then
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET /books/:bookid<uint>", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), semconv.HTTPRoute("/books/:bookid<uint>"))
+	})
+
+	t.Run("should name the span only with the method if no route matched", func(t *testing.T) {
+		// given
+		recorder := useRecorder(t)
+		handler := HTTP(router.New())
+
+		// when
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown/1", nil))
+
+		// then
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET", spans[0].Name())
+	})
+}
+
+func TestTransport(t *testing.T) {
+	t.Run("should send the trace context of the client span", func(t *testing.T) {
+		// given
+		recorder := useRecorder(t)
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: Transport(http.DefaultTransport)}
+
+		// when
+		resp, err := client.Get(server.URL)
+
+		// then
+		assert.NoError(t, err)
+		resp.Body.Close()
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Contains(t, header.Get("traceparent"), spans[0].SpanContext().TraceID().String())
+	})
+}
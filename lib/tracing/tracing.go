@@ -0,0 +1,97 @@
+// Package tracing provides the OpenTelemetry tracing of all services.
+// The trace context is propagated with the W3C traceparent- and tracestate-headers over HTTP and gRPC.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// closeTimeout limits how long Close waits for the remaining spans to be exported
+const closeTimeout = 5 * time.Second
+
+type Config struct {
+	// Exporter is one of none, otlp or stdout. The OTLP-exporter is configured
+	// with the standard OTEL_EXPORTER_OTLP_*-variables like OTEL_EXPORTER_OTLP_ENDPOINT.
+	Exporter    string  `env:"EXPORTER" envDefault:"none"`
+	SampleRatio float64 `env:"SAMPLE_RATIO" envDefault:"1" validate:"min=0,max=1"`
+}
+
+// Validate is called by the appconfig-package, so an unknown exporter is reported with the other problems of the configuration
+func (config Config) Validate() error {
+	switch config.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout, "":
+		return nil
+	}
+	return fmt.Errorf("unknown trace exporter %q", config.Exporter)
+}
+
+type Provider struct {
+	provider *sdktrace.TracerProvider
+}
+
+// NewProvider installs the global tracer provider for the service and the W3C propagators.
+// The propagators are also installed without an exporter, so incoming trace contexts are still passed on.
+func NewProvider(ctx context.Context, serviceName string, config Config) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.Exporter {
+	case ExporterNone, "":
+		return &Provider{}, nil
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return &Provider{provider}, nil
+}
+
+// Close exports the remaining spans and stops the provider
+func (p *Provider) Close() error {
+	if p.provider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	return p.provider.Shutdown(ctx)
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useRecorder installs a provider, which records all spans, and restores the previous provider after the test
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Run("should install only the propagators without an exporter", func(t *testing.T) {
+		// when
+		provider, err := NewProvider(context.Background(), "test-service", Config{Exporter: ExporterNone, SampleRatio: 1})
+
+		// then
+		assert.NoError(t, err)
+		assert.Nil(t, provider.provider)
+		assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+		assert.NoError(t, provider.Close())
+	})
+
+	t.Run("should create a provider with the stdout exporter", func(t *testing.T) {
+		// given
+		previous := otel.GetTracerProvider()
+		defer otel.SetTracerProvider(previous)
+
+		// when
+		provider, err := NewProvider(context.Background(), "test-service", Config{Exporter: ExporterStdout, SampleRatio: 1})
+
+		// then
+		assert.NoError(t, err)
+		assert.NotNil(t, provider.provider)
+		assert.Equal(t, provider.provider, otel.GetTracerProvider())
+		assert.NoError(t, provider.Close())
+	})
+
+	t.Run("should return an error for unknown exporters", func(t *testing.T) {
+		// when
+		_, err := NewProvider(context.Background(), "test-service", Config{Exporter: "jaeger"})
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should accept the known exporters", func(t *testing.T) {
+		for _, exporter := range []string{ExporterNone, ExporterOTLP, ExporterStdout} {
+			assert.NoError(t, Config{Exporter: exporter}.Validate())
+		}
+	})
+
+	t.Run("should reject unknown exporters", func(t *testing.T) {
+		assert.EqualError(t, Config{Exporter: "jaeger"}.Validate(), `unknown trace exporter "jaeger"`)
+	})
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"golang.org/x/sync/singleflight"
+)
+
+// SharedCallTimeout limits a shared call, whose first caller has no deadline
+var SharedCallTimeout = 30 * time.Second
+
+// DoShared calls fn only once for concurrent calls with the same key like singleflight.Group.Do.
+// The result is shared with the other callers, so fn isn't cancelled with ctx,
+// but DoShared returns ctx.Err() as soon as ctx is done. fn keeps the deadline of ctx
+// or gets SharedCallTimeout, so the callers waiting for it aren't blocked forever.
+// Every finished call is counted by whether it got the result of another caller.
+func DoShared[T any](ctx context.Context, g *singleflight.Group, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	executed := false
+	ch := g.DoChan(key, func() (interface{}, error) {
+		executed = true
+		sharedCtx, cancel := detach(ctx)
+		defer cancel()
+		return fn(sharedCtx)
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case result := <-ch:
+		metrics.ObserveSharedCall(!executed)
+		if result.Err != nil {
+			var zero T
+			return zero, result.Err
+		}
+		return result.Val.(T), nil
+	}
+}
+
+// detach returns a context, which isn't cancelled with ctx, but ends at its deadline or after SharedCallTimeout
+func detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	}
+	return context.WithTimeout(context.WithoutCancel(ctx), SharedCallTimeout)
+}
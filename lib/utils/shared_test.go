@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/singleflight"
+)
+
+func TestDoShared(t *testing.T) {
+	t.Run("should return the result of fn", func(t *testing.T) {
+		// given
+		g := &singleflight.Group{}
+
+		// when
+		value, err := DoShared(context.Background(), g, "key", func(ctx context.Context) (int, error) {
+			return 42, nil
+		})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("should return the error of fn", func(t *testing.T) {
+		// given
+		g := &singleflight.Group{}
+
+		// when
+		value, err := DoShared(context.Background(), g, "key", func(ctx context.Context) (*int, error) {
+			return nil, errors.New("failed")
+		})
+
+		// then
+		assert.Error(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("should return when the context is cancelled without cancelling fn", func(t *testing.T) {
+		// given
+		g := &singleflight.Group{}
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		defer close(release)
+		var fnCtx context.Context
+
+		// when
+		_, err := DoShared(ctx, g, "key", func(ctx context.Context) (int, error) {
+			fnCtx = ctx
+			cancel()
+			<-release
+			return 1, nil
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NoError(t, fnCtx.Err())
+	})
+	t.Run("should keep the deadline of the context for fn", func(t *testing.T) {
+		// given
+		g := &singleflight.Group{}
+		deadline := time.Now().Add(time.Minute)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		var fnDeadline time.Time
+
+		// when
+		DoShared(ctx, g, "key", func(ctx context.Context) (int, error) {
+			fnDeadline, _ = ctx.Deadline()
+			return 1, nil
+		})
+
+		// then
+		assert.Equal(t, deadline, fnDeadline)
+	})
+
+	t.Run("should limit fn without a deadline", func(t *testing.T) {
+		// given
+		g := &singleflight.Group{}
+		SharedCallTimeout = time.Millisecond
+		defer func() { SharedCallTimeout = 30 * time.Second }()
+
+		// when
+		_, err := DoShared(context.Background(), g, "key", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+
+		// then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Decode decodes the JSON body of the request into v and validates it.
+// Unknown fields are rejected. All errors are of type *Error.
+func Decode(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return &Error{Status: http.StatusBadRequest, Message: "request body must only contain a single JSON value"}
+	}
+
+	return Validate(v)
+}
+
+func decodeError(err error) *Error {
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	var maxBytesError *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return &Error{Status: http.StatusBadRequest, Message: "request body must not be empty"}
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		return &Error{Status: http.StatusBadRequest, Message: "request body must be valid JSON"}
+	case errors.As(err, &typeError):
+		if typeError.Field == "" {
+			return &Error{Status: http.StatusBadRequest, Message: "request body must be a JSON " + jsonType(typeError.Type)}
+		}
+		return invalidFields(FieldError{typeError.Field, "must be a " + jsonType(typeError.Type)})
+	case errors.As(err, &maxBytesError):
+		return &Error{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("request body must not be larger than %d bytes", maxBytesError.Limit),
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no error type for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return invalidFields(FieldError{field, "is unknown"})
+	default:
+		return &Error{Status: http.StatusBadRequest, Message: err.Error()}
+	}
+}
+
+func invalidFields(fields ...FieldError) *Error {
+	return &Error{Status: http.StatusBadRequest, Message: "request body is invalid", Fields: fields}
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeRequest struct {
+	Name  string  `json:"name" validate:"required"`
+	Price *uint64 `json:"price"`
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should decode and validate the body", func(t *testing.T) {
+		// given
+		r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"Book","price":5}`))
+
+		// when
+		var request decodeRequest
+		err := Decode(r, &request)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "Book", request.Name)
+		assert.Equal(t, uint64(5), *request.Price)
+	})
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		fields  []FieldError
+	}{
+		{"empty body", ``, "request body must not be empty", nil},
+		{"invalid JSON", `{"name":`, "request body must be valid JSON", nil},
+		{"malformed JSON", `{name}`, "request body must be valid JSON", nil},
+		{"wrong body type", `[]`, "request body must be a JSON object", nil},
+		{"wrong field type", `{"name":"Book","price":"5"}`, "request body is invalid", []FieldError{{"price", "must be a non-negative integer"}}},
+		{"unknown field", `{"name":"Book","id":999}`, "request body is invalid", []FieldError{{"id", "is unknown"}}},
+		{"multiple values", `{"name":"Book"}{}`, "request body must only contain a single JSON value", nil},
+		{"failed validation", `{"price":5}`, "request body is invalid", []FieldError{{"name", "is required"}}},
+	}
+
+	for _, test := range tests {
+		t.Run("should return 400 BAD REQUEST for "+test.name, func(t *testing.T) {
+			// given
+			r := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+
+			// when
+			var request decodeRequest
+			err := Decode(r, &request)
+
+			// then
+			var validationError *Error
+			assert.ErrorAs(t, err, &validationError)
+			assert.Equal(t, http.StatusBadRequest, validationError.Status)
+			assert.Equal(t, test.message, validationError.Message)
+			assert.Equal(t, test.fields, validationError.Fields)
+		})
+	}
+
+	t.Run("should return 413 REQUEST ENTITY TOO LARGE if the body exceeds the limit", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"a long name"}`))
+		r.Body = http.MaxBytesReader(w, r.Body, 10)
+
+		// when
+		var request decodeRequest
+		err := Decode(r, &request)
+
+		// then
+		var validationError *Error
+		assert.ErrorAs(t, err, &validationError)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, validationError.Status)
+	})
+}
+
+func TestError(t *testing.T) {
+	t.Run("should be written as problem details with the invalid fields", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/api/v1/books", nil)
+		err := invalidFields(FieldError{"name", "is required"})
+
+		// when
+		problem.Write(w, r, err)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "request body is invalid",
+			"instance": "/api/v1/books",
+			"code": "InvalidArgument",
+			"reason": "INVALID_REQUEST_BODY",
+			"errors": [{"field": "name", "message": "is required"}]
+		}`, w.Body.String())
+	})
+
+	t.Run("should be written with the reason REQUEST_BODY_TOO_LARGE for too large bodies", func(t *testing.T) {
+		// given
+		err := &Error{Status: http.StatusRequestEntityTooLarge, Message: "too large"}
+
+		// when
+		details := err.Problem()
+
+		// then
+		assert.Equal(t, http.StatusRequestEntityTooLarge, details.Status)
+		assert.Equal(t, "REQUEST_BODY_TOO_LARGE", details.Reason)
+		assert.Nil(t, details.Errors)
+	})
+}
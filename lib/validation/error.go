@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is returned by Decode and Validate. It is written by problem.Write
+// with the invalid fields as "errors" extension member.
+type Error struct {
+	Status  int
+	Message string
+	Fields  []FieldError
+}
+
+func (err *Error) Error() string {
+	if len(err.Fields) == 0 {
+		return err.Message
+	}
+
+	fields := make([]string, len(err.Fields))
+	for i, field := range err.Fields {
+		fields[i] = field.Field + " " + field.Message
+	}
+	return err.Message + ": " + strings.Join(fields, ", ")
+}
+
+func (err *Error) Problem() *problem.Details {
+	reason := "INVALID_REQUEST_BODY"
+	if err.Status == http.StatusRequestEntityTooLarge {
+		reason = "REQUEST_BODY_TOO_LARGE"
+	}
+
+	details := &problem.Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(err.Status),
+		Status: err.Status,
+		Detail: err.Message,
+		Code:   shared_types.InvalidArgument.String(),
+		Reason: reason,
+	}
+	if len(err.Fields) > 0 {
+		details.Errors = err.Fields
+	}
+	return details
+}
@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks the rules in the validate-tags of the struct fields:
+//
+//	required  the value must not be the zero value, pointers must not be nil
+//	email     the string must be an email address
+//	min=n     strings must have at least n characters, slices and maps n items and numbers a value of n
+//	max=n     like min but the upper bound
+//
+// Rules of pointer fields are applied to the value, if the pointer isn't nil.
+// Nested structs are validated as well. The returned error is of type *Error.
+func Validate(v any) error {
+	var fields []FieldError
+	validateValue(reflect.ValueOf(v), "", &fields)
+
+	if len(fields) > 0 {
+		return invalidFields(fields...)
+	}
+	return nil
+}
+
+// ValidateValue checks a single value against the rules of a validate-tag like "min=1,max=65535"
+// and returns the message of the first violated rule or an empty string.
+func ValidateValue(value any, rules string) string {
+	return validateField(reflect.ValueOf(value), rules)
+}
+
+func validateValue(value reflect.Value, path string, fields *[]FieldError) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		validateStruct(value, path, fields)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	}
+}
+
+func validateStruct(value reflect.Value, path string, fields *[]FieldError) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		fieldValue := value.Field(i)
+		if message := validateField(fieldValue, field.Tag.Get("validate")); message != "" {
+			*fields = append(*fields, FieldError{name, message})
+			continue
+		}
+
+		if field.Anonymous {
+			validateValue(fieldValue, path, fields)
+		} else {
+			validateValue(fieldValue, name, fields)
+		}
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// validateField returns the message of the first violated rule or an empty string
+func validateField(value reflect.Value, tag string) string {
+	if tag == "" {
+		return ""
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, argument, _ := strings.Cut(rule, "=")
+
+		if name == "required" {
+			if value.IsZero() {
+				return "is required"
+			}
+			continue
+		}
+
+		// the remaining rules only apply to set values
+		current := value
+		for current.Kind() == reflect.Pointer {
+			if current.IsNil() {
+				break
+			}
+			current = current.Elem()
+		}
+		if current.Kind() == reflect.Pointer {
+			continue
+		}
+
+		var message string
+		switch name {
+		case "email":
+			message = validateEmail(current)
+		case "min":
+			message = validateBound(current, parseBound(rule, argument), true)
+		case "max":
+			message = validateBound(current, parseBound(rule, argument), false)
+		default:
+			panic("validation: unknown rule " + rule)
+		}
+		if message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
+
+func parseBound(rule string, argument string) float64 {
+	bound, err := strconv.ParseFloat(argument, 64)
+	if err != nil {
+		panic("validation: invalid rule " + rule)
+	}
+	return bound
+}
+
+func validateEmail(value reflect.Value) string {
+	if value.Kind() != reflect.String {
+		panic("validation: email can only be used for strings")
+	}
+
+	address, err := mail.ParseAddress(value.String())
+	if err != nil || address.Address != value.String() {
+		return "must be a valid email address"
+	}
+	return ""
+}
+
+func validateBound(value reflect.Value, bound float64, isMin bool) string {
+	var actual float64
+	var unit string
+
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		panic(fmt.Sprintf("validation: min and max can't be used for %s", value.Type()))
+	}
+
+	formatted := strconv.FormatFloat(bound, 'f', -1, 64) + unit
+	if isMin && actual < bound {
+		if unit == "" {
+			return "must be at least " + formatted
+		}
+		return "must have at least " + formatted
+	}
+	if !isMin && actual > bound {
+		if unit == "" {
+			return "must be at most " + formatted
+		}
+		return "must have at most " + formatted
+	}
+	return ""
+}
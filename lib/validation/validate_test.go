@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type item struct {
+	Name string `json:"name" validate:"required,max=3"`
+}
+
+type validateRequest struct {
+	Email    string   `json:"email" validate:"required,email"`
+	Name     string   `json:"name" validate:"min=2,max=5"`
+	Age      int      `json:"age" validate:"min=18,max=130"`
+	Amount   *uint64  `json:"amount" validate:"required,min=1"`
+	Status   *int     `json:"status" validate:"min=0,max=1"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Address  address  `json:"address"`
+	Items    []item   `json:"items"`
+	Optional *address `json:"optional"`
+	internal string   `validate:"required"`
+	NoTag    string
+}
+
+func TestValidate(t *testing.T) {
+	amount := func(value uint64) *uint64 { return &value }
+	status := func(value int) *int { return &value }
+
+	valid := func() validateRequest {
+		return validateRequest{
+			Email:   "test@test.com",
+			Name:    "Jöhn",
+			Age:     20,
+			Amount:  amount(1),
+			Tags:    []string{"a"},
+			Address: address{"Flensburg"},
+			Items:   []item{{"abc"}},
+		}
+	}
+
+	t.Run("should accept a valid struct", func(t *testing.T) {
+		// given
+		request := valid()
+		request.Status = status(1)
+
+		// when
+		err := Validate(&request)
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(request *validateRequest)
+		field  FieldError
+	}{
+		{"missing string", func(r *validateRequest) { r.Email = "" }, FieldError{"email", "is required"}},
+		{"invalid email", func(r *validateRequest) { r.Email = "test" }, FieldError{"email", "must be a valid email address"}},
+		{"email with name", func(r *validateRequest) { r.Email = "Test <test@test.com>" }, FieldError{"email", "must be a valid email address"}},
+		{"short string", func(r *validateRequest) { r.Name = "a" }, FieldError{"name", "must have at least 2 characters"}},
+		{"long string", func(r *validateRequest) { r.Name = "abcdef" }, FieldError{"name", "must have at most 5 characters"}},
+		{"small number", func(r *validateRequest) { r.Age = 17 }, FieldError{"age", "must be at least 18"}},
+		{"large number", func(r *validateRequest) { r.Age = 131 }, FieldError{"age", "must be at most 130"}},
+		{"nil pointer", func(r *validateRequest) { r.Amount = nil }, FieldError{"amount", "is required"}},
+		{"small pointer value", func(r *validateRequest) { r.Amount = amount(0) }, FieldError{"amount", "must be at least 1"}},
+		{"large pointer value", func(r *validateRequest) { r.Status = status(2) }, FieldError{"status", "must be at most 1"}},
+		{"long slice", func(r *validateRequest) { r.Tags = []string{"a", "b", "c"} }, FieldError{"tags", "must have at most 2 items"}},
+		{"nested struct", func(r *validateRequest) { r.Address.City = "" }, FieldError{"address.city", "is required"}},
+		{"slice item", func(r *validateRequest) { r.Items = append(r.Items, item{"abcd"}) }, FieldError{"items[1].name", "must have at most 3 characters"}},
+		{"nested pointer", func(r *validateRequest) { r.Optional = &address{} }, FieldError{"optional.city", "is required"}},
+	}
+
+	for _, test := range tests {
+		t.Run("should reject "+test.name, func(t *testing.T) {
+			// given
+			request := valid()
+			test.modify(&request)
+
+			// when
+			err := Validate(&request)
+
+			// then
+			var validationError *Error
+			assert.ErrorAs(t, err, &validationError)
+			assert.Equal(t, []FieldError{test.field}, validationError.Fields)
+		})
+	}
+
+	t.Run("should report every invalid field", func(t *testing.T) {
+		// given
+		request := validateRequest{Name: "a", Age: 18}
+
+		// when
+		err := Validate(&request)
+
+		// then
+		var validationError *Error
+		assert.ErrorAs(t, err, &validationError)
+		assert.Equal(t, []FieldError{
+			{"email", "is required"},
+			{"name", "must have at least 2 characters"},
+			{"amount", "is required"},
+			{"address.city", "is required"},
+		}, validationError.Fields)
+		assert.Equal(t, "request body is invalid: email is required, name must have at least 2 characters, amount is required, address.city is required", err.Error())
+	})
+
+	t.Run("should panic on unknown rules", func(t *testing.T) {
+		// given
+		request := struct {
+			Name string `validate:"unknown"`
+		}{"name"}
+
+		// when
+		validate := func() { Validate(&request) }
+
+		// then
+		assert.PanicsWithValue(t, "validation: unknown rule unknown", validate)
+	})
+}
+
+func TestValidateValue(t *testing.T) {
+	t.Run("should return an empty message for a valid value", func(t *testing.T) {
+		assert.Equal(t, "", ValidateValue(uint16(8080), "min=1,max=65535"))
+	})
+
+	t.Run("should return the message of the first violated rule", func(t *testing.T) {
+		assert.Equal(t, "must be at least 1", ValidateValue(uint16(0), "min=1,max=65535"))
+		assert.Equal(t, "is required", ValidateValue("", "required,email"))
+	})
+}
@@ -9,6 +9,7 @@
 package books_mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
@@ -39,102 +40,102 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(arg0 []*model.Book) error {
+func (m *MockRepository) Create(ctx context.Context, books []*model.Book) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", ctx, books)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, books any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, books)
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(arg0 []*model.Book) error {
+func (m *MockRepository) Delete(ctx context.Context, books []*model.Book) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", ctx, books)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(arg0 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, books any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, books)
 }
 
 // FindAll mocks base method.
-func (m *MockRepository) FindAll() ([]*model.Book, error) {
+func (m *MockRepository) FindAll(ctx context.Context) ([]*model.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx)
 	ret0, _ := ret[0].([]*model.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockRepositoryMockRecorder) FindAll() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll), ctx)
 }
 
 // FindAllByUserId mocks base method.
-func (m *MockRepository) FindAllByUserId(id uint64) ([]*model.Book, error) {
+func (m *MockRepository) FindAllByUserId(ctx context.Context, id uint64) ([]*model.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllByUserId", id)
+	ret := m.ctrl.Call(m, "FindAllByUserId", ctx, id)
 	ret0, _ := ret[0].([]*model.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAllByUserId indicates an expected call of FindAllByUserId.
-func (mr *MockRepositoryMockRecorder) FindAllByUserId(id any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAllByUserId(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByUserId", reflect.TypeOf((*MockRepository)(nil).FindAllByUserId), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByUserId", reflect.TypeOf((*MockRepository)(nil).FindAllByUserId), ctx, id)
 }
 
 // FindById mocks base method.
-func (m *MockRepository) FindById(id uint64) (*model.Book, error) {
+func (m *MockRepository) FindById(ctx context.Context, id uint64) (*model.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindById", id)
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
 	ret0, _ := ret[0].(*model.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindById indicates an expected call of FindById.
-func (mr *MockRepositoryMockRecorder) FindById(id any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockRepository)(nil).FindById), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockRepository)(nil).FindById), ctx, id)
 }
 
 // Migrate mocks base method.
-func (m *MockRepository) Migrate() error {
+func (m *MockRepository) Migrate(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Migrate")
+	ret := m.ctrl.Call(m, "Migrate", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Migrate indicates an expected call of Migrate.
-func (mr *MockRepositoryMockRecorder) Migrate() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Migrate(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockRepository)(nil).Migrate))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockRepository)(nil).Migrate), ctx)
 }
 
 // Update mocks base method.
-func (m *MockRepository) Update(id uint64, updateBook *model.BookPatch) error {
+func (m *MockRepository) Update(ctx context.Context, id uint64, updateBook *model.BookPatch) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, updateBook)
+	ret := m.ctrl.Call(m, "Update", ctx, id, updateBook)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(id, updateBook any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, updateBook any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), id, updateBook)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, updateBook)
 }
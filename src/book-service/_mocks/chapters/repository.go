@@ -9,6 +9,7 @@
 package chapters_mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
@@ -39,95 +40,95 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(arg0 []*model.Chapter) error {
+func (m *MockRepository) Create(ctx context.Context, chapters []*model.Chapter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", ctx, chapters)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, chapters any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, chapters)
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(arg0 []*model.Chapter) error {
+func (m *MockRepository) Delete(ctx context.Context, chapters []*model.Chapter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", ctx, chapters)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(arg0 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, chapters any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, chapters)
 }
 
 // FindAllPreviewsByBookId mocks base method.
-func (m *MockRepository) FindAllPreviewsByBookId(bookId uint64) ([]*model.ChapterPreview, error) {
+func (m *MockRepository) FindAllPreviewsByBookId(ctx context.Context, bookId uint64) ([]*model.ChapterPreview, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllPreviewsByBookId", bookId)
+	ret := m.ctrl.Call(m, "FindAllPreviewsByBookId", ctx, bookId)
 	ret0, _ := ret[0].([]*model.ChapterPreview)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAllPreviewsByBookId indicates an expected call of FindAllPreviewsByBookId.
-func (mr *MockRepositoryMockRecorder) FindAllPreviewsByBookId(bookId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAllPreviewsByBookId(ctx, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllPreviewsByBookId", reflect.TypeOf((*MockRepository)(nil).FindAllPreviewsByBookId), bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllPreviewsByBookId", reflect.TypeOf((*MockRepository)(nil).FindAllPreviewsByBookId), ctx, bookId)
 }
 
 // FindByIdAndBookId mocks base method.
-func (m *MockRepository) FindByIdAndBookId(id, bookId uint64) (*model.Chapter, error) {
+func (m *MockRepository) FindByIdAndBookId(ctx context.Context, id, bookId uint64) (*model.Chapter, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByIdAndBookId", id, bookId)
+	ret := m.ctrl.Call(m, "FindByIdAndBookId", ctx, id, bookId)
 	ret0, _ := ret[0].(*model.Chapter)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByIdAndBookId indicates an expected call of FindByIdAndBookId.
-func (mr *MockRepositoryMockRecorder) FindByIdAndBookId(id, bookId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindByIdAndBookId(ctx, id, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIdAndBookId", reflect.TypeOf((*MockRepository)(nil).FindByIdAndBookId), id, bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIdAndBookId", reflect.TypeOf((*MockRepository)(nil).FindByIdAndBookId), ctx, id, bookId)
 }
 
 // Migrate mocks base method.
-func (m *MockRepository) Migrate() error {
+func (m *MockRepository) Migrate(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Migrate")
+	ret := m.ctrl.Call(m, "Migrate", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Migrate indicates an expected call of Migrate.
-func (mr *MockRepositoryMockRecorder) Migrate() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Migrate(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockRepository)(nil).Migrate))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockRepository)(nil).Migrate), ctx)
 }
 
 // Update mocks base method.
-func (m *MockRepository) Update(id, bookId uint64, updateChapter *model.ChapterPatch) error {
+func (m *MockRepository) Update(ctx context.Context, id, bookId uint64, updateChapter *model.ChapterPatch) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, bookId, updateChapter)
+	ret := m.ctrl.Call(m, "Update", ctx, id, bookId, updateChapter)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(id, bookId, updateChapter any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, bookId, updateChapter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), id, bookId, updateChapter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, bookId, updateChapter)
 }
 
 // ValidateChapterId mocks base method.
-func (m *MockRepository) ValidateChapterId(id, bookId uint64) (*model.Chapter, *uint64, error) {
+func (m *MockRepository) ValidateChapterId(ctx context.Context, id, bookId uint64) (*model.Chapter, *uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateChapterId", id, bookId)
+	ret := m.ctrl.Call(m, "ValidateChapterId", ctx, id, bookId)
 	ret0, _ := ret[0].(*model.Chapter)
 	ret1, _ := ret[1].(*uint64)
 	ret2, _ := ret[2].(error)
@@ -135,7 +136,7 @@ func (m *MockRepository) ValidateChapterId(id, bookId uint64) (*model.Chapter, *
 }
 
 // ValidateChapterId indicates an expected call of ValidateChapterId.
-func (mr *MockRepositoryMockRecorder) ValidateChapterId(id, bookId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ValidateChapterId(ctx, id, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChapterId", reflect.TypeOf((*MockRepository)(nil).ValidateChapterId), id, bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChapterId", reflect.TypeOf((*MockRepository)(nil).ValidateChapterId), ctx, id, bookId)
 }
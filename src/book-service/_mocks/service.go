@@ -9,6 +9,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
@@ -39,17 +40,16 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // ValidateChapterId mocks base method.
-func (m *MockService) ValidateChapterId(userId, chapterId, bookId uint64) (*shared_types.ValidateChapterIdResponse, shared_types.Code, error) {
+func (m *MockService) ValidateChapterId(ctx context.Context, userId, chapterId, bookId uint64) (*shared_types.ValidateChapterIdResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateChapterId", userId, chapterId, bookId)
+	ret := m.ctrl.Call(m, "ValidateChapterId", ctx, userId, chapterId, bookId)
 	ret0, _ := ret[0].(*shared_types.ValidateChapterIdResponse)
-	ret1, _ := ret[1].(shared_types.Code)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ValidateChapterId indicates an expected call of ValidateChapterId.
-func (mr *MockServiceMockRecorder) ValidateChapterId(userId, chapterId, bookId any) *gomock.Call {
+func (mr *MockServiceMockRecorder) ValidateChapterId(ctx, userId, chapterId, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChapterId", reflect.TypeOf((*MockService)(nil).ValidateChapterId), userId, chapterId, bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChapterId", reflect.TypeOf((*MockService)(nil).ValidateChapterId), ctx, userId, chapterId, bookId)
 }
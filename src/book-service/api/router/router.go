@@ -7,8 +7,12 @@ import (
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
 
 	books_controller "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/controller"
+	books_model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
 	chapters_controller "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/controller"
+	chapters_model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/openapi"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 )
 
 type Router struct {
@@ -22,25 +26,58 @@ func New(
 	healthController health.Controller,
 ) *Router {
 	booksRouter := router.New()
+	booksRouter.GET("/openapi.json", openapi.Handler(booksRouter, openapi.Info{Title: "Book-Service", Version: "1.0.0"}))
 	booksRouter.GET("/health", healthController.ProvideHealth)
-	booksRouter.POST("/valdiate-chapter-id", chapterController.ValidateChapterId)
+	booksRouter.GET("/health/live", healthController.ProvideLiveness)
+	booksRouter.GET("/health/ready", healthController.ProvideReadiness)
+	booksRouter.POST("/valdiate-chapter-id", chapterController.ValidateChapterId).
+		Describe("Validates that a chapter can be bought").
+		Request(shared_types.ValidateChapterIdRequest{}).
+		Response(http.StatusOK, shared_types.ValidateChapterIdResponse{})
 
-	booksRouter.USE("/api/v1/books", authController.AuthenticationMiddleware)
-	booksRouter.GET("/api/v1/books", booksController.GetBooks)
-	booksRouter.POST("/api/v1/books", booksController.PostBook)
+	books := booksRouter.Group("/api/v1/books", authController.AuthenticationMiddleware)
+	books.GET("", booksController.GetBooks).
+		Describe("Get all books").
+		Query("userId", "uint").
+		Response(http.StatusOK, []*books_model.Book{})
+	books.POST("", booksController.PostBook).
+		Describe("Create a book").
+		Request(books_controller.CreateBookRequest{}).
+		Response(http.StatusOK, nil)
 
-	booksRouter.USE("/api/v1/books/:bookid", booksController.LoadBookMiddleware)
-	booksRouter.GET("/api/v1/books/:bookid", booksController.GetBook)
-	booksRouter.PATCH("/api/v1/books/:bookid", booksController.PatchBook)
-	booksRouter.DELETE("/api/v1/books/:bookid", booksController.DeleteBook)
+	book := books.Group("/:bookid<uint>", booksController.LoadBookMiddleware)
+	book.GET("", booksController.GetBook).
+		Describe("Get a book").
+		Response(http.StatusOK, books_model.Book{}).
+		Response(http.StatusNotFound, nil)
+	book.PATCH("", booksController.PatchBook).
+		Describe("Update a book").
+		Request(books_controller.UpdateBookRequest{}).
+		Response(http.StatusOK, nil)
+	book.DELETE("", booksController.DeleteBook).
+		Describe("Delete a book").
+		Response(http.StatusOK, nil)
 
-	booksRouter.GET("/api/v1/books/:bookid/chapters", chapterController.GetChaptersForBook)
-	booksRouter.POST("/api/v1/books/:bookid/chapters", chapterController.PostChapter)
+	book.GET("/chapters", chapterController.GetChaptersForBook).
+		Describe("Get the previews of all chapters of a book").
+		Response(http.StatusOK, []*chapters_model.ChapterPreview{})
+	book.POST("/chapters", chapterController.PostChapter).
+		Describe("Create a chapter").
+		Request(chapters_controller.CreateChapterRequest{}).
+		Response(http.StatusOK, nil)
 
-	booksRouter.USE("/api/v1/books/:bookid/chapters/:chapterid", chapterController.LoadChapterMiddleware)
-	booksRouter.GET("/api/v1/books/:bookid/chapters/:chapterid", chapterController.GetChapterForBook)
-	booksRouter.PATCH("/api/v1/books/:bookid/chapters/:chapterid", chapterController.PatchChapter)
-	booksRouter.DELETE("/api/v1/books/:bookid/chapters/:chapterid", chapterController.DeleteChapter)
+	chapter := book.Group("/chapters/:chapterid<uint>", chapterController.LoadChapterMiddleware)
+	chapter.GET("", chapterController.GetChapterForBook).
+		Describe("Get a chapter").
+		Response(http.StatusOK, chapters_model.Chapter{}).
+		Response(http.StatusPaymentRequired, nil)
+	chapter.PATCH("", chapterController.PatchChapter).
+		Describe("Update a chapter").
+		Request(chapters_controller.UpdateChapterRequest{}).
+		Response(http.StatusOK, nil)
+	chapter.DELETE("", chapterController.DeleteChapter).
+		Describe("Delete a chapter").
+		Response(http.StatusOK, nil)
 
 	return &Router{booksRouter}
 }
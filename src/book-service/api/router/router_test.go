@@ -1,7 +1,7 @@
 package router
 
 import (
-	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +10,7 @@ import (
 	chapters_mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/_mocks/chapters"
 	auth_mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware/_mocks"
 	health_mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/openapi"
 	libRouter "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -77,14 +78,14 @@ func TestRouter(t *testing.T) {
 
 			authController.
 				EXPECT().
-				AuthenticationMiddleware(w, r, gomock.Any()).
+				AuthenticationMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				LoadBookMiddleware(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1")), gomock.Any()).
+				LoadBookMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					w.WriteHeader(http.StatusNotFound)
 				}).Times(1)
@@ -103,12 +104,12 @@ func TestRouter(t *testing.T) {
 
 			authController.
 				EXPECT().
-				AuthenticationMiddleware(w, r, gomock.Any()).
+				AuthenticationMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
-			req := r.WithContext(context.WithValue(r.Context(), "bookid", "1"))
+			req := libRouter.WithParam(r, "bookid", uint64(1))
 			booksController.
 				EXPECT().
 				LoadBookMiddleware(w, req, gomock.Any()).
@@ -126,28 +127,33 @@ func TestRouter(t *testing.T) {
 			// then
 			assert.Equal(t, http.StatusOK, w.Code)
 		})
+
+		t.Run("Book should not be loaded for unknown sub-paths", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/api/v1/books/1/unknown", nil)
+
+			// when
+			router.ServeHTTP(w, r)
+
+			// then
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
 	})
 
 	t.Run("/api/v1/books", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not GET or POST", func(t *testing.T) {
-			tests := []string{"DELETE", "PUT", "HEAD", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not GET or POST", func(t *testing.T) {
+			tests := []string{"DELETE", "PUT", "CONNECT", "TRACE", "PATCH"}
 			for _, test := range tests {
 				// given
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(test, "/api/v1/books", nil)
 
-				authController.
-					EXPECT().
-					AuthenticationMiddleware(w, r, gomock.Any()).
-					Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
-						next(r)
-					}).Times(1)
-
 				// when
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 		t.Run("should call GET handler", func(t *testing.T) {
@@ -200,33 +206,19 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/books/:bookid", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not GET, DELETE or PATCH", func(t *testing.T) {
-			tests := []string{"POST", "HEAD", "CONNECT", "OPTIONS", "TRACE", "PUT"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not GET, DELETE or PATCH", func(t *testing.T) {
+			tests := []string{"POST", "CONNECT", "TRACE", "PUT"}
 
 			for _, test := range tests {
 				// given
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(test, "/api/v1/books/1", nil)
 
-				authController.
-					EXPECT().
-					AuthenticationMiddleware(w, r, gomock.Any()).
-					Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
-						next(r)
-					}).Times(1)
-
-				booksController.
-					EXPECT().
-					LoadBookMiddleware(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1")), gomock.Any()).
-					Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
-						next(r)
-					}).Times(1)
-
 				// when
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -237,21 +229,21 @@ func TestRouter(t *testing.T) {
 
 			authController.
 				EXPECT().
-				AuthenticationMiddleware(w, r, gomock.Any()).
+				AuthenticationMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				LoadBookMiddleware(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1")), gomock.Any()).
+				LoadBookMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				GetBook(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1"))).
+				GetBook(w, libRouter.WithParam(r, "bookid", uint64(1))).
 				Times(1)
 
 			// when
@@ -268,21 +260,21 @@ func TestRouter(t *testing.T) {
 
 			authController.
 				EXPECT().
-				AuthenticationMiddleware(w, r, gomock.Any()).
+				AuthenticationMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				LoadBookMiddleware(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1")), gomock.Any()).
+				LoadBookMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				PatchBook(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1"))).
+				PatchBook(w, libRouter.WithParam(r, "bookid", uint64(1))).
 				Times(1)
 
 			// when
@@ -299,21 +291,21 @@ func TestRouter(t *testing.T) {
 
 			authController.
 				EXPECT().
-				AuthenticationMiddleware(w, r, gomock.Any()).
+				AuthenticationMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				LoadBookMiddleware(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1")), gomock.Any()).
+				LoadBookMiddleware(w, libRouter.WithParam(r, "bookid", uint64(1)), gomock.Any()).
 				Do(func(w http.ResponseWriter, r *http.Request, next libRouter.Next) {
 					next(r)
 				}).Times(1)
 
 			booksController.
 				EXPECT().
-				DeleteBook(w, r.WithContext(context.WithValue(r.Context(), "bookid", "1"))).
+				DeleteBook(w, libRouter.WithParam(r, "bookid", uint64(1))).
 				Times(1)
 
 			// when
@@ -323,4 +315,24 @@ func TestRouter(t *testing.T) {
 			assert.Equal(t, http.StatusOK, w.Code)
 		})
 	})
+
+	t.Run("/openapi.json", func(t *testing.T) {
+		t.Run("should return the spec of all routes", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/openapi.json", nil)
+
+			// when
+			router.ServeHTTP(w, r)
+
+			// then
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var document openapi.Document
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&document))
+			assert.Contains(t, document.Paths, "/api/v1/books/{bookid}/chapters/{chapterid}")
+			assert.Contains(t, document.Components.Schemas, "CreateBookRequest")
+			assert.Contains(t, document.Components.Schemas, "Chapter")
+		})
+	})
 }
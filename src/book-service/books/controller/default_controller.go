@@ -10,7 +10,11 @@ import (
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
 	books_repository "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/repository"
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/utils"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/validation"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -20,6 +24,8 @@ const (
 	MiddleWareBook bookContext = "book"
 )
 
+var ErrNotBookOwner = shared_types.NewError(shared_types.PermissionDenied, "NOT_BOOK_OWNER", "you are not the owner of the book")
+
 type DefaultController struct {
 	bookRepository books_repository.Repository
 	g              *singleflight.Group
@@ -39,61 +45,51 @@ func (ctrl *DefaultController) GetBooks(w http.ResponseWriter, r *http.Request)
 	if userId != "" {
 		id, err := strconv.ParseUint(userId, 10, 64)
 		if err != nil {
-			http.Error(w, "could not parse userid", http.StatusBadRequest)
+			problem.Write(w, r, shared_types.NewError(shared_types.InvalidArgument, "INVALID_USER_ID", "could not parse userId"))
 			return
 		}
-		newBooks, err, _ := ctrl.g.Do(fmt.Sprintf("books-%d", id), func() (interface{}, error) {
-			return ctrl.bookRepository.FindAllByUserId(id)
+		books, err = utils.DoShared(r.Context(), ctrl.g, fmt.Sprintf("books-%d", id), func(ctx context.Context) ([]*model.Book, error) {
+			return ctrl.bookRepository.FindAllByUserId(ctx, id)
 		})
 		if err != nil {
-			http.Error(w, "Error while getting the books", http.StatusBadRequest)
+			problem.Write(w, r, err)
 			return
 		}
-		books = newBooks.([]*model.Book)
 	} else {
-		newBooks, err, _ := ctrl.g.Do("books", func() (interface{}, error) {
-			return ctrl.bookRepository.FindAll()
+		var err error
+		books, err = utils.DoShared(r.Context(), ctrl.g, "books", func(ctx context.Context) ([]*model.Book, error) {
+			return ctrl.bookRepository.FindAll(ctx)
 		})
 		if err != nil {
-			http.Error(w, "Error while getting the books", http.StatusInternalServerError)
+			problem.Write(w, r, err)
 			return
 		}
-		books = newBooks.([]*model.Book)
 	}
 
 	w.Header().Add("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(books)
 }
 
-type createBookRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
-
-func (r createBookRequest) isValid() bool {
-	return r.Name != "" && r.Description != ""
+type CreateBookRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"required"`
 }
 
 func (ctrl *DefaultController) PostBook(w http.ResponseWriter, r *http.Request) {
 	userId := r.Context().Value(auth_middleware.AuthenticatedUserId).(uint64)
 
-	var request createBookRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.isValid() {
-		w.WriteHeader(http.StatusBadRequest)
+	var request CreateBookRequest
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	if err := ctrl.bookRepository.Create([]*model.Book{{
+	if err := ctrl.bookRepository.Create(r.Context(), []*model.Book{{
 		Name:        request.Name,
 		AuthorID:    userId,
 		Description: request.Description,
 	}}); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 }
@@ -105,8 +101,8 @@ func (ctrl *DefaultController) GetBook(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(book)
 }
 
-type updateBookRequest struct {
-	Name        string `json:"name"`
+type UpdateBookRequest struct {
+	Name        string `json:"name" validate:"max=100"`
 	Description string `json:"description"`
 }
 
@@ -115,13 +111,13 @@ func (ctrl *DefaultController) PatchBook(w http.ResponseWriter, r *http.Request)
 	book := r.Context().Value(MiddleWareBook).(*model.Book)
 
 	if userId != book.AuthorID {
-		w.WriteHeader(http.StatusUnauthorized)
+		problem.Write(w, r, ErrNotBookOwner)
 		return
 	}
 
-	var request updateBookRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	var request UpdateBookRequest
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -133,8 +129,8 @@ func (ctrl *DefaultController) PatchBook(w http.ResponseWriter, r *http.Request)
 		patchBook.Description = &request.Description
 	}
 
-	if err := ctrl.bookRepository.Update(book.ID, &patchBook); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.bookRepository.Update(r.Context(), book.ID, &patchBook); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 }
@@ -144,34 +140,30 @@ func (ctrl *DefaultController) DeleteBook(w http.ResponseWriter, r *http.Request
 	book := r.Context().Value(MiddleWareBook).(*model.Book)
 
 	if userId != book.AuthorID {
-		w.WriteHeader(http.StatusUnauthorized)
+		problem.Write(w, r, ErrNotBookOwner)
 		return
 	}
 
-	if err := ctrl.bookRepository.Delete([]*model.Book{book}); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.bookRepository.Delete(r.Context(), []*model.Book{book}); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 }
 
 func (ctrl *DefaultController) LoadBookMiddleware(w http.ResponseWriter, r *http.Request, next router.Next) {
-	bookId := r.Context().Value("bookid").(string)
-
-	id, err := strconv.ParseUint(bookId, 10, 64)
-	if err != nil {
-		http.Error(w, "can't parse the bookId", http.StatusBadRequest)
+	id, ok := router.Param[uint64](r, "bookid")
+	if !ok {
+		problem.Write(w, r, shared_types.NewError(shared_types.InvalidArgument, "BOOK_ID_MISSING", "can't find the bookId"))
 		return
 	}
 
-	newBook, err, _ := ctrl.g.Do(fmt.Sprintf("book-%d", id), func() (interface{}, error) {
-		return ctrl.bookRepository.FindById(id)
+	book, err := utils.DoShared(r.Context(), ctrl.g, fmt.Sprintf("book-%d", id), func(ctx context.Context) (*model.Book, error) {
+		return ctrl.bookRepository.FindById(ctx, id)
 	})
 	if err != nil {
-		http.Error(w, "can't find the book", http.StatusNotFound)
+		problem.Write(w, r, shared_types.WrapNotFound(err, "BOOK_NOT_FOUND", "can't find the book"))
 		return
 	}
-	book := newBook.(*model.Book)
-
 	ctx := context.WithValue(r.Context(), MiddleWareBook, book)
 	next(r.WithContext(ctx))
 }
@@ -2,6 +2,7 @@ package books_controller
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"io"
@@ -13,6 +14,7 @@ import (
 	books_mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/_mocks/books"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
 	authMiddleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -30,7 +32,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				FindAll().
+				FindAll(gomock.Any()).
 				Return(nil, errors.New("query failed")).
 				Times(1)
 
@@ -48,7 +50,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				FindAll().
+				FindAll(gomock.Any()).
 				Return([]*model.Book{{ID: 999}}, nil).
 				Times(1)
 
@@ -86,7 +88,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				FindAllByUserId(uint64(1)).
+				FindAllByUserId(gomock.Any(), uint64(1)).
 				Return([]*model.Book{{ID: 999}}, nil).
 				Times(1)
 
@@ -147,6 +149,34 @@ func TestBookDefaultController(t *testing.T) {
 			}
 		})
 
+		t.Run("should return 400 BAD REQUEST with the invalid fields", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/books",
+				strings.NewReader(`{"name":"`+strings.Repeat("a", 101)+`"}`))
+			r = r.WithContext(context.WithValue(r.Context(), authMiddleware.AuthenticatedUserId, uint64(1)))
+
+			// when
+			controller.PostBook(w, r)
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "request body is invalid",
+				"instance": "/api/v1/books",
+				"code": "InvalidArgument",
+				"reason": "INVALID_REQUEST_BODY",
+				"errors": [
+					{"field": "name", "message": "must have at most 100 characters"},
+					{"field": "description", "message": "is required"}
+				]
+			}`, w.Body.String())
+		})
+
 		t.Run("should return 500 INTERNAL SERVER ERROR if persisting failed", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
@@ -157,7 +187,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				Create([]*model.Book{{Name: "test book", AuthorID: userId, Description: "amazing book"}}).
+				Create(gomock.Any(), []*model.Book{{Name: "test book", AuthorID: userId, Description: "amazing book"}}).
 				Return(errors.New("database error"))
 
 			// when
@@ -177,7 +207,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				Create([]*model.Book{{Name: "test book", AuthorID: userId, Description: "amazing book"}}).
+				Create(gomock.Any(), []*model.Book{{Name: "test book", AuthorID: userId, Description: "amazing book"}}).
 				Return(nil)
 
 			// when
@@ -248,7 +278,7 @@ func TestBookDefaultController(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PATCH", "/api/v1/books/1",
-				strings.NewReader(`{"id": 999}`))
+				strings.NewReader(`{}`))
 			dbBook := &model.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -260,7 +290,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				Update(uint64(1), &model.BookPatch{}).
+				Update(gomock.Any(), uint64(1), &model.BookPatch{}).
 				Return(errors.New("database error"))
 
 			// when
@@ -274,7 +304,7 @@ func TestBookDefaultController(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/api/v1/books/1",
-				strings.NewReader(`{"id": 999}`))
+				strings.NewReader(`{}`))
 			dbBook := &model.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -286,7 +316,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				Update(uint64(1), &model.BookPatch{}).
+				Update(gomock.Any(), uint64(1), &model.BookPatch{}).
 				Return(errors.New("database error"))
 
 			// when
@@ -296,11 +326,11 @@ func TestBookDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, w.Code)
 		})
 
-		t.Run("should return 401 If you are not the creator of the book", func(t *testing.T) {
+		t.Run("should return 403 FORBIDDEN if you are not the creator of the book", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/api/v1/books/1",
-				strings.NewReader(`{"id": 999}`))
+				strings.NewReader(`{}`))
 			dbBook := &model.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -314,7 +344,7 @@ func TestBookDefaultController(t *testing.T) {
 			controller.PatchBook(w, r)
 
 			// then
-			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, http.StatusForbidden, w.Code)
 		})
 
 		t.Run("should update one book", func(t *testing.T) {
@@ -334,7 +364,7 @@ func TestBookDefaultController(t *testing.T) {
 			newDescription := "a fine book"
 			bookRepository.
 				EXPECT().
-				Update(uint64(1), &model.BookPatch{Description: &newDescription}).
+				Update(gomock.Any(), uint64(1), &model.BookPatch{Description: &newDescription}).
 				Return(nil)
 
 			// when
@@ -361,7 +391,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				Delete([]*model.Book{dbBook}).
+				Delete(gomock.Any(), []*model.Book{dbBook}).
 				Return(errors.New("database error"))
 
 			// when
@@ -371,7 +401,7 @@ func TestBookDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, w.Code)
 		})
 
-		t.Run("should return 401 if not the user who created the book", func(t *testing.T) {
+		t.Run("should return 403 FORBIDDEN if not the user who created the book", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("DELETE", "/api/v1/books/1", nil)
@@ -388,7 +418,7 @@ func TestBookDefaultController(t *testing.T) {
 			controller.DeleteBook(w, r)
 
 			// then
-			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, http.StatusForbidden, w.Code)
 		})
 
 		t.Run("should return 200 OK", func(t *testing.T) {
@@ -406,7 +436,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				Delete([]*model.Book{dbBook}).
+				Delete(gomock.Any(), []*model.Book{dbBook}).
 				Return(nil)
 
 			// when
@@ -418,11 +448,10 @@ func TestBookDefaultController(t *testing.T) {
 	})
 
 	t.Run("LoadBookMiddleware", func(t *testing.T) {
-		t.Run("Should return 400 if the bookid cannot be parsed", func(t *testing.T) {
+		t.Run("Should return 400 if the bookid is missing", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/books/aaa", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "bookid", "aaa"))
 
 			// when
 			called := false
@@ -434,16 +463,16 @@ func TestBookDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, w.Code)
 		})
 
-		t.Run("Should return 404 if the query fails", func(t *testing.T) {
+		t.Run("Should return 404 if the book doesn't exist", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/books/1", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "bookid", "1"))
+			r = router.WithParam(r, "bookid", uint64(1))
 
 			bookRepository.
 				EXPECT().
-				FindById(uint64(1)).
-				Return(nil, errors.New("database error"))
+				FindById(gomock.Any(), uint64(1)).
+				Return(nil, sql.ErrNoRows)
 
 			// when
 			called := false
@@ -453,13 +482,37 @@ func TestBookDefaultController(t *testing.T) {
 
 			assert.Equal(t, false, called)
 			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), `"reason":"BOOK_NOT_FOUND"`)
+		})
+
+		t.Run("Should return 500 if the query fails", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/api/v1/books/1", nil)
+			r = router.WithParam(r, "bookid", uint64(1))
+
+			bookRepository.
+				EXPECT().
+				FindById(gomock.Any(), uint64(1)).
+				Return(nil, errors.New("database error"))
+
+			// when
+			called := false
+			controller.LoadBookMiddleware(w, r, func(r *http.Request) {
+				called = true
+			})
+
+			assert.Equal(t, false, called)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.NotContains(t, w.Body.String(), "database error")
 		})
 
 		t.Run("Should return 200 if it succeeds", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/books/1", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "bookid", "1"))
+			r = router.WithParam(r, "bookid", uint64(1))
 			dbBook := &model.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -469,7 +522,7 @@ func TestBookDefaultController(t *testing.T) {
 
 			bookRepository.
 				EXPECT().
-				FindById(uint64(1)).
+				FindById(gomock.Any(), uint64(1)).
 				Return(dbBook, nil)
 
 			// when
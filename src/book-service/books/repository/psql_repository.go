@@ -1,9 +1,9 @@
 package books_repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
@@ -16,8 +16,7 @@ type PsqlRepository struct {
 }
 
 func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
-	dsn := config.Dsn()
-	db, err := sql.Open("postgres", dsn)
+	db, err := database.Open("postgres", config)
 	db.SetMaxOpenConns(10)
 	if err != nil {
 		return nil, err
@@ -26,6 +25,21 @@ func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
 	return &PsqlRepository{db}, nil
 }
 
+// Close closes the connection pool of the repository.
+func (repo *PsqlRepository) Close() error {
+	return repo.db.Close()
+}
+
+// Ping checks that the database can be reached.
+func (repo *PsqlRepository) Ping(ctx context.Context) error {
+	return repo.db.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool for the metrics.
+func (repo *PsqlRepository) Stats() sql.DBStats {
+	return repo.db.Stats()
+}
+
 const createBooksTable = `
 create table if not exists books (
     id			serial primary key,
@@ -36,8 +50,8 @@ create table if not exists books (
 )
 `
 
-func (repo *PsqlRepository) Migrate() error {
-	_, err := repo.db.Exec(createBooksTable)
+func (repo *PsqlRepository) Migrate(ctx context.Context) error {
+	_, err := repo.db.ExecContext(ctx, createBooksTable)
 	return err
 }
 
@@ -45,7 +59,7 @@ const createBooksBatchQuery = `
 insert into books (name, authorId, description) values %s
 `
 
-func (repo *PsqlRepository) Create(books []*model.Book) error {
+func (repo *PsqlRepository) Create(ctx context.Context, books []*model.Book) error {
 	placeholders := make([]string, len(books))
 	values := make([]interface{}, len(books)*3)
 
@@ -57,7 +71,7 @@ func (repo *PsqlRepository) Create(books []*model.Book) error {
 	}
 
 	query := fmt.Sprintf(createBooksBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, values...)
+	_, err := repo.db.ExecContext(ctx, query, values...)
 	return err
 }
 
@@ -65,10 +79,8 @@ const updateBookBatchQuery = `
 update books set name = $1, description = $2 where id = $3
 `
 
-func (repo *PsqlRepository) Update(id uint64, updateBook *model.BookPatch) error {
-	log.Println("HEEEEEEEEEEEEEEEREEEEEEEEEEEEEEEE")
-	dbBook, err := repo.FindById(id)
-	log.Println(dbBook)
+func (repo *PsqlRepository) Update(ctx context.Context, id uint64, updateBook *model.BookPatch) error {
+	dbBook, err := repo.FindById(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -79,7 +91,7 @@ func (repo *PsqlRepository) Update(id uint64, updateBook *model.BookPatch) error
 		dbBook.Description = *updateBook.Description
 	}
 
-	_, err = repo.db.Exec(updateBookBatchQuery, dbBook.Name, dbBook.Description, id)
+	_, err = repo.db.ExecContext(ctx, updateBookBatchQuery, dbBook.Name, dbBook.Description, id)
 	return err
 }
 
@@ -87,8 +99,8 @@ const findAllBooksQuery = `
 select id, name, authorId, description from books
 `
 
-func (repo *PsqlRepository) FindAll() ([]*model.Book, error) {
-	rows, err := repo.db.Query(findAllBooksQuery)
+func (repo *PsqlRepository) FindAll(ctx context.Context) ([]*model.Book, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllBooksQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +122,8 @@ const findAllBooksByUserIdQuery = `
 select id, name, authorId, description from books where authorId = $1
 `
 
-func (repo *PsqlRepository) FindAllByUserId(id uint64) ([]*model.Book, error) {
-	rows, err := repo.db.Query(findAllBooksByUserIdQuery, id)
+func (repo *PsqlRepository) FindAllByUserId(ctx context.Context, id uint64) ([]*model.Book, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllBooksByUserIdQuery, id)
 	if err != nil {
 		return nil, err
 	}
@@ -133,8 +145,8 @@ const findBooksByIDQuery = `
 select id, name, authorId, description from books where id = $1 limit 1
 `
 
-func (repo *PsqlRepository) FindById(id uint64) (*model.Book, error) {
-	row := repo.db.QueryRow(findBooksByIDQuery, id)
+func (repo *PsqlRepository) FindById(ctx context.Context, id uint64) (*model.Book, error) {
+	row := repo.db.QueryRowContext(ctx, findBooksByIDQuery, id)
 
 	var book model.Book
 	if err := row.Scan(&book.ID, &book.Name, &book.AuthorID, &book.Description); err != nil {
@@ -147,7 +159,7 @@ const deleteBooksBatchQuery = `
 delete from books where id in (%s)
 `
 
-func (repo *PsqlRepository) Delete(books []*model.Book) error {
+func (repo *PsqlRepository) Delete(ctx context.Context, books []*model.Book) error {
 	placeholders := make([]string, len(books))
 	ids := make([]interface{}, len(books))
 
@@ -157,6 +169,6 @@ func (repo *PsqlRepository) Delete(books []*model.Book) error {
 	}
 
 	query := fmt.Sprintf(deleteBooksBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, ids...)
+	_, err := repo.db.ExecContext(ctx, query, ids...)
 	return err
 }
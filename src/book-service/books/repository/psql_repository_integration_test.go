@@ -48,7 +48,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			createUserTable(t, repository.db)
 
 			// when
-			err := repository.Migrate()
+			err := repository.Migrate(context.Background())
 			// then
 			assert.NoError(t, err)
 			assertBooksTableExists(t, repository.db, "books", []string{"id", "name", "authorid", "description"})
@@ -76,7 +76,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Create(books)
+			err := repository.Create(context.Background(), books)
 
 			// then
 			assert.NoError(t, err)
@@ -103,7 +103,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Update(3, newBookData)
+			err := repository.Update(context.Background(), 3, newBookData)
 
 			// then
 			assert.NoError(t, err)
@@ -128,7 +128,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			})
 
 			// when
-			book, err := repository.FindById(4)
+			book, err := repository.FindById(context.Background(), 4)
 
 			// then
 			assert.NoError(t, err)
@@ -161,7 +161,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Delete([]*model.Book{books[1]})
+			err := repository.Delete(context.Background(), []*model.Book{books[1]})
 
 			// then
 			assert.NoError(t, err)
@@ -201,7 +201,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			}
 
 			// when
-			result, err := repository.FindAll()
+			result, err := repository.FindAll(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -240,7 +240,7 @@ func TestIntegrationPsqlBookRepository(t *testing.T) {
 			}
 
 			// when
-			result, err := repository.FindAllByUserId(1)
+			result, err := repository.FindAllByUserId(context.Background(), 1)
 
 			// then
 			assert.NoError(t, err)
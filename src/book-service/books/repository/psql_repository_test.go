@@ -1,6 +1,7 @@
 package books_repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -32,7 +33,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Create(books)
+			err := repository.Create(context.Background(), books)
 
 			// then
 			assert.Error(t, err)
@@ -61,7 +62,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Create(books)
+			err := repository.Create(context.Background(), books)
 
 			// then
 			assert.NoError(t, err)
@@ -78,7 +79,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			users, err := repository.FindById(id)
+			users, err := repository.FindById(context.Background(), id)
 
 			// then
 			assert.Error(t, err)
@@ -96,7 +97,7 @@ func TestPsqlBookRepository(t *testing.T) {
 					AddRow(1, "Updated Book", 1, "An updated description"))
 
 			// when
-			book, err := repository.FindById(id)
+			book, err := repository.FindById(context.Background(), id)
 
 			// then
 			assert.NoError(t, err)
@@ -127,7 +128,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Delete(books)
+			err := repository.Delete(context.Background(), books)
 
 			// then
 			assert.Error(t, err)
@@ -156,7 +157,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Delete(books)
+			err := repository.Delete(context.Background(), books)
 
 			// then
 			assert.NoError(t, err)
@@ -178,7 +179,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Update(1, newBookData)
+			err := repository.Update(context.Background(), 1, newBookData)
 
 			// then
 			assert.Error(t, err)
@@ -205,7 +206,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Update(2, newBookData)
+			err := repository.Update(context.Background(), 2, newBookData)
 
 			// then
 			assert.Error(t, err)
@@ -232,7 +233,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			// when
-			err := repository.Update(1, newBookData)
+			err := repository.Update(context.Background(), 1, newBookData)
 
 			// then
 			assert.NoError(t, err)
@@ -247,7 +248,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			result, err := repository.FindAll()
+			result, err := repository.FindAll(context.Background())
 
 			// then
 			assert.Error(t, err)
@@ -269,7 +270,7 @@ func TestPsqlBookRepository(t *testing.T) {
 					AddRow(3, "Book Three", 2, "A bad book"))
 
 			// when
-			result, err := repository.FindAll()
+			result, err := repository.FindAll(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -285,7 +286,7 @@ func TestPsqlBookRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			result, err := repository.FindAllByUserId(1)
+			result, err := repository.FindAllByUserId(context.Background(), 1)
 
 			// then
 			assert.Error(t, err)
@@ -306,7 +307,7 @@ func TestPsqlBookRepository(t *testing.T) {
 					AddRow(2, "Book Two", 1, "A good book"))
 
 			// when
-			result, err := repository.FindAllByUserId(1)
+			result, err := repository.FindAllByUserId(context.Background(), 1)
 
 			// then
 			assert.NoError(t, err)
@@ -314,4 +315,15 @@ func TestPsqlBookRepository(t *testing.T) {
 		})
 	})
 
+	t.Run("Close", func(t *testing.T) {
+		// given
+		dbmock.ExpectClose()
+
+		// when
+		err := repository.Close()
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, dbmock.ExpectationsWereMet())
+	})
 }
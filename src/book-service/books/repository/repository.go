@@ -1,13 +1,17 @@
 package books_repository
 
-import "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
+import (
+	"context"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
+)
 
 type Repository interface {
-	Migrate() error
-	Create([]*model.Book) error
-	Update(id uint64, updateBook *model.BookPatch) error
-	FindAll() ([]*model.Book, error)
-	FindAllByUserId(id uint64) ([]*model.Book, error)
-	FindById(id uint64) (*model.Book, error)
-	Delete([]*model.Book) error
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, books []*model.Book) error
+	Update(ctx context.Context, id uint64, updateBook *model.BookPatch) error
+	FindAll(ctx context.Context) ([]*model.Book, error)
+	FindAllByUserId(ctx context.Context, id uint64) ([]*model.Book, error)
+	FindById(ctx context.Context, id uint64) (*model.Book, error)
+	Delete(ctx context.Context, books []*model.Book) error
 }
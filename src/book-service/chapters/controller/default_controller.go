@@ -4,9 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
 
 	books_controller "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/controller"
 	books_model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
@@ -15,9 +14,11 @@ import (
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/service"
 	transaction_service_client "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/transaction-service-client"
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/utils"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/validation"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -46,16 +47,15 @@ func (ctrl *DefaultController) GetChaptersForBook(w http.ResponseWriter, r *http
 	userId := r.Context().Value(auth_middleware.AuthenticatedUserId).(uint64)
 	book := r.Context().Value(books_controller.MiddleWareBook).(*books_model.Book)
 
-	newChapters, err, _ := ctrl.g.Do(fmt.Sprintf("chapters-%d", book.ID), func() (interface{}, error) {
-		return ctrl.chapterRepository.FindAllPreviewsByBookId(book.ID)
+	chapters, err := utils.DoShared(r.Context(), ctrl.g, fmt.Sprintf("chapters-%d", book.ID), func(ctx context.Context) ([]*model.ChapterPreview, error) {
+		return ctrl.chapterRepository.FindAllPreviewsByBookId(ctx, book.ID)
 	})
 
 	if err != nil {
-		log.Println("ERROR [GetChaptersForBook - FindAllPreviewsByBookId]: ", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "could not find the chapters of the book", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
-	chapters := newChapters.([]*model.ChapterPreview)
 
 	if userId != book.AuthorID {
 		chapters = utils.Filter(chapters, func(chapter *model.ChapterPreview) bool { return chapter.Status == model.Published })
@@ -65,14 +65,10 @@ func (ctrl *DefaultController) GetChaptersForBook(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(chapters)
 }
 
-type createChapterRequest struct {
-	Name    string  `json:"name"`
-	Price   *uint64 `json:"price"`
-	Content string  `json:"content"`
-}
-
-func (r createChapterRequest) isValid() bool {
-	return r.Name != "" && r.Price != nil && r.Content != ""
+type CreateChapterRequest struct {
+	Name    string  `json:"name" validate:"required,max=100"`
+	Price   *uint64 `json:"price" validate:"required"`
+	Content string  `json:"content" validate:"required"`
 }
 
 func (ctrl *DefaultController) PostChapter(w http.ResponseWriter, r *http.Request) {
@@ -80,32 +76,26 @@ func (ctrl *DefaultController) PostChapter(w http.ResponseWriter, r *http.Reques
 	book := r.Context().Value(books_controller.MiddleWareBook).(*books_model.Book)
 
 	if userId != book.AuthorID {
-		log.Println("ERROR [PostChapter - userId != book.AuthorID]: ", "You are not the owner of the book")
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
-	var request createChapterRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("ERROR [PostChapter - Decode createChapterRequest]: ", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
+		slog.WarnContext(r.Context(), "user is not the owner of the book")
+		problem.Write(w, r, books_controller.ErrNotBookOwner)
 		return
 	}
 
-	if !request.isValid() {
-		log.Println("ERROR [PostChapter - Validate createChapterRequest]: ", "Invalid request")
-		w.WriteHeader(http.StatusBadRequest)
+	var request CreateChapterRequest
+	if err := validation.Decode(r, &request); err != nil {
+		slog.InfoContext(r.Context(), "invalid create chapter request", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	if err := ctrl.chapterRepository.Create([]*model.Chapter{{
+	if err := ctrl.chapterRepository.Create(r.Context(), []*model.Chapter{{
 		BookID:  book.ID,
 		Name:    request.Name,
 		Price:   *request.Price,
 		Content: request.Content,
 	}}); err != nil {
-		log.Println("ERROR [PostChapter - Create]: ", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "could not create the chapter", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 }
@@ -121,10 +111,14 @@ func (ctrl *DefaultController) GetChapterForBook(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err := ctrl.transactionServiceClient.CheckChapterBought(userId, chapter.ID, chapter.BookID)
+	err := ctrl.transactionServiceClient.CheckChapterBought(r.Context(), userId, chapter.ID, chapter.BookID)
+	if err != nil && shared_types.CodeOf(err) != shared_types.NotFound {
+		slog.ErrorContext(r.Context(), "could not check if the chapter was bought", "error", err)
+		problem.Write(w, r, err)
+		return
+	}
 	if err != nil {
-		log.Println("ERROR [GetChapterForBook - CheckChapterBought]: ", err.Error())
-		w.WriteHeader(http.StatusPaymentRequired)
+		problem.Write(w, r, problem.New(shared_types.PermissionDenied, "CHAPTER_NOT_BOUGHT", "you have to buy the chapter first").WithStatus(http.StatusPaymentRequired))
 		return
 	}
 
@@ -132,11 +126,11 @@ func (ctrl *DefaultController) GetChapterForBook(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(chapter)
 }
 
-type updateChapterRequest struct {
-	Name    string        `json:"name"`
+type UpdateChapterRequest struct {
+	Name    string        `json:"name" validate:"max=100"`
 	Price   *uint64       `json:"price"`
 	Content string        `json:"content"`
-	Status  *model.Status `json:"status"`
+	Status  *model.Status `json:"status" validate:"min=0,max=1"`
 }
 
 func (ctrl *DefaultController) PatchChapter(w http.ResponseWriter, r *http.Request) {
@@ -145,15 +139,15 @@ func (ctrl *DefaultController) PatchChapter(w http.ResponseWriter, r *http.Reque
 	chapter := r.Context().Value(middleWareChapter).(*model.Chapter)
 
 	if userId != book.AuthorID {
-		log.Println("ERROR [PatchChapter - userId != book.AuthorID]: ", "You are not the owner of the book")
-		w.WriteHeader(http.StatusUnauthorized)
+		slog.WarnContext(r.Context(), "user is not the owner of the book")
+		problem.Write(w, r, books_controller.ErrNotBookOwner)
 		return
 	}
 
-	var request updateChapterRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("ERROR [PatchChapter - Decode updateChapterRequest]: ", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
+	var request UpdateChapterRequest
+	if err := validation.Decode(r, &request); err != nil {
+		slog.InfoContext(r.Context(), "invalid update chapter request", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -169,20 +163,9 @@ func (ctrl *DefaultController) PatchChapter(w http.ResponseWriter, r *http.Reque
 	}
 	if request.Status != nil {
 		newstatus := *request.Status
-		if newstatus > model.Published {
-			log.Println("ERROR [DeleteChapter - userId != book.AuthorID]: ", "You cannot change status to a status greater than published")
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-		if newstatus < model.Draft {
-			log.Println("ERROR [DeleteChapter - userId != book.AuthorID]: ", "You cannot change status to a status less than draft")
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
 		if chapter.Status == model.Published && newstatus == model.Draft {
-			log.Println("ERROR [DeleteChapter - userId != book.AuthorID]: ", "You cannot change status from published to draft")
-			w.WriteHeader(http.StatusBadRequest)
+			slog.WarnContext(r.Context(), "cannot change the status from published to draft")
+			problem.Write(w, r, shared_types.NewError(shared_types.FailedPrecondition, "CHAPTER_ALREADY_PUBLISHED", "you cannot change the status from published to draft"))
 			return
 		}
 
@@ -191,9 +174,9 @@ func (ctrl *DefaultController) PatchChapter(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	if err := ctrl.chapterRepository.Update(chapter.ID, chapter.BookID, &patchChapter); err != nil {
-		log.Println("ERROR [PatchChapter - Update]: ", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.chapterRepository.Update(r.Context(), chapter.ID, chapter.BookID, &patchChapter); err != nil {
+		slog.ErrorContext(r.Context(), "could not update the chapter", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 }
@@ -204,67 +187,56 @@ func (ctrl *DefaultController) DeleteChapter(w http.ResponseWriter, r *http.Requ
 	chapter := r.Context().Value(middleWareChapter).(*model.Chapter)
 
 	if userId != book.AuthorID {
-		log.Println("ERROR [DeleteChapter - userId != book.AuthorID]: ", "You are not the owner of the book")
-		w.WriteHeader(http.StatusUnauthorized)
+		slog.WarnContext(r.Context(), "user is not the owner of the book")
+		problem.Write(w, r, books_controller.ErrNotBookOwner)
 		return
 	}
 
 	if chapter.Status == model.Published {
-		log.Println("ERROR [DeleteChapter - chapter.Status == model.Published]: ", "Cannot delete a published chapter")
-		w.WriteHeader(http.StatusBadRequest)
+		slog.WarnContext(r.Context(), "cannot delete a published chapter")
+		problem.Write(w, r, shared_types.NewError(shared_types.FailedPrecondition, "CHAPTER_ALREADY_PUBLISHED", "cannot delete a published chapter"))
 		return
 	}
 
-	if err := ctrl.chapterRepository.Delete([]*model.Chapter{chapter}); err != nil {
-		log.Println("ERROR [DeleteChapter - Delete]: ", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.chapterRepository.Delete(r.Context(), []*model.Chapter{chapter}); err != nil {
+		slog.ErrorContext(r.Context(), "could not delete the chapter", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 }
 
 func (ctrl *DefaultController) LoadChapterMiddleware(w http.ResponseWriter, r *http.Request, next router.Next) {
 	book := r.Context().Value(books_controller.MiddleWareBook).(*books_model.Book)
-	chapterId := r.Context().Value("chapterid").(string)
-
-	id, err := strconv.ParseUint(chapterId, 10, 64)
-	if err != nil {
-		log.Println("ERROR [LoadChapterMiddleware - ParseUint]: ", err.Error())
-		http.Error(w, "can't parse the chapterId", http.StatusBadRequest)
+	id, ok := router.Param[uint64](r, "chapterid")
+	if !ok {
+		problem.Write(w, r, shared_types.NewError(shared_types.InvalidArgument, "CHAPTER_ID_MISSING", "can't find the chapterId"))
 		return
 	}
 
-	newChapter, err, _ := ctrl.g.Do(fmt.Sprintf("chapter-%d", id), func() (interface{}, error) {
-		return ctrl.chapterRepository.FindByIdAndBookId(id, book.ID)
+	chapter, err := utils.DoShared(r.Context(), ctrl.g, fmt.Sprintf("chapter-%d", id), func(ctx context.Context) (*model.Chapter, error) {
+		return ctrl.chapterRepository.FindByIdAndBookId(ctx, id, book.ID)
 	})
 	if err != nil {
-		log.Println("ERROR [LoadChapterMiddleware - FindByIdAndBookId]: ", err.Error())
-		http.Error(w, "can't find the chapter", http.StatusNotFound)
+		slog.InfoContext(r.Context(), "could not find the chapter", "error", err)
+		problem.Write(w, r, shared_types.WrapNotFound(err, "CHAPTER_NOT_FOUND", "can't find the chapter"))
 		return
 	}
-	chapter := newChapter.(*model.Chapter)
-
 	ctx := context.WithValue(r.Context(), middleWareChapter, chapter)
 	next(r.WithContext(ctx))
 }
 
 func (ctrl *DefaultController) ValidateChapterId(w http.ResponseWriter, r *http.Request) {
 	var request shared_types.ValidateChapterIdRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("ERROR [ValidateChapterId - Decode ValidateChapterIdRequest]: ", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.IsValid() {
-		log.Println("ERROR [ValidateChapterId - Validate ValidateChapterIdRequest]: ", "Invalid request")
-		w.WriteHeader(http.StatusBadRequest)
+	if err := validation.Decode(r, &request); err != nil {
+		slog.InfoContext(r.Context(), "invalid validate chapter request", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	result, statusCode, err := ctrl.service.ValidateChapterId(request.UserId, request.ChapterId, request.BookId)
+	result, err := ctrl.service.ValidateChapterId(r.Context(), request.UserId, request.ChapterId, request.BookId)
 	if err != nil {
-		log.Println("ERROR [ValidateChapterId - Execute ValidateChapterId]: ", err.Error())
-		http.Error(w, err.Error(), statusCode.ToHTTPStatusCode())
+		slog.ErrorContext(r.Context(), "could not validate the chapter", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -2,6 +2,7 @@ package chapters_controller
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"io"
@@ -17,6 +18,7 @@ import (
 	booksModel "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/books/model"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
 	authMiddleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -45,7 +47,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				FindAllPreviewsByBookId(dbBook.ID).
+				FindAllPreviewsByBookId(gomock.Any(), dbBook.ID).
 				Return(nil, errors.New("query failed")).
 				Times(1)
 
@@ -71,7 +73,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				FindAllPreviewsByBookId(uint64(1)).
+				FindAllPreviewsByBookId(gomock.Any(), uint64(1)).
 				Return([]*model.ChapterPreview{{ID: 999}}, nil).
 				Times(1)
 
@@ -92,7 +94,7 @@ func TestChapterDefaultController(t *testing.T) {
 	})
 
 	t.Run("PostChapters", func(t *testing.T) {
-		t.Run("should return 401 If yoa not the author of the book", func(t *testing.T) {
+		t.Run("should return 403 FORBIDDEN if you are not the author of the book", func(t *testing.T) {
 
 			// given
 			w := httptest.NewRecorder()
@@ -110,7 +112,7 @@ func TestChapterDefaultController(t *testing.T) {
 			controller.PostChapter(w, r)
 
 			// then
-			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, http.StatusForbidden, w.Code)
 
 		})
 
@@ -185,7 +187,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				Create([]*model.Chapter{{
+				Create(gomock.Any(), []*model.Chapter{{
 					BookID:  1,
 					Name:    "test chapter",
 					Price:   10,
@@ -217,7 +219,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				Create([]*model.Chapter{{
+				Create(gomock.Any(), []*model.Chapter{{
 					BookID:  1,
 					Name:    "test chapter",
 					Price:   10,
@@ -292,19 +294,50 @@ func TestChapterDefaultController(t *testing.T) {
 
 			transactionServiceClient.
 				EXPECT().
-				CheckChapterBought(uint64(2), uint64(1), uint64(1)).
-				Return(errors.New("chapter not bought"))
+				CheckChapterBought(gomock.Any(), uint64(2), uint64(1), uint64(1)).
+				Return(shared_types.NewError(shared_types.NotFound, "TRANSACTION_NOT_FOUND", "you haven't bought this chapter"))
 
 			// when
 			controller.GetChapterForBook(w, r)
 
 			// then
-			res := w.Result()
-			var response model.Chapter
-			err := json.NewDecoder(res.Body).Decode(&response)
-
-			assert.Error(t, err)
 			assert.Equal(t, http.StatusPaymentRequired, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), `"reason":"CHAPTER_NOT_BOUGHT"`)
+		})
+
+		t.Run("should return 500 if the check fails", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/api/v1/chapters/1", nil)
+			r = r.WithContext(context.WithValue(r.Context(), authMiddleware.AuthenticatedUserId, uint64(2)))
+			dbBook := &booksModel.Book{
+				ID:          1,
+				Name:        "Book One",
+				AuthorID:    1,
+				Description: "! good book",
+			}
+			r = r.WithContext(context.WithValue(r.Context(), books_controller.MiddleWareBook, dbBook))
+			dbChapter := &model.Chapter{
+				ID:      1,
+				BookID:  1,
+				Name:    "Chapter One",
+				Price:   100,
+				Content: "Nice chapter",
+			}
+			r = r.WithContext(context.WithValue(r.Context(), middleWareChapter, dbChapter))
+
+			transactionServiceClient.
+				EXPECT().
+				CheckChapterBought(gomock.Any(), uint64(2), uint64(1), uint64(1)).
+				Return(errors.New("connection refused"))
+
+			// when
+			controller.GetChapterForBook(w, r)
+
+			// then
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.NotContains(t, w.Body.String(), `"reason":"CHAPTER_NOT_BOUGHT"`)
 		})
 
 		t.Run("should return 200 OK and chapter", func(t *testing.T) {
@@ -329,7 +362,7 @@ func TestChapterDefaultController(t *testing.T) {
 			r = r.WithContext(context.WithValue(r.Context(), middleWareChapter, dbChapter))
 			transactionServiceClient.
 				EXPECT().
-				CheckChapterBought(uint64(2), uint64(1), uint64(1)).
+				CheckChapterBought(gomock.Any(), uint64(2), uint64(1), uint64(1)).
 				Return(nil)
 
 			// when
@@ -383,11 +416,47 @@ func TestChapterDefaultController(t *testing.T) {
 			}
 		})
 
+		t.Run("should return 400 BAD REQUEST if status is unknown", func(t *testing.T) {
+			tests := []io.Reader{
+				strings.NewReader(`{"status": -1}`),
+				strings.NewReader(`{"status": 2}`),
+			}
+
+			for _, test := range tests {
+				// given
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest("PATCH", "/api/v1/chapters/1", test)
+				dbBook := &booksModel.Book{
+					ID:          1,
+					Name:        "Book One",
+					AuthorID:    1,
+					Description: "! good book",
+				}
+				r = r.WithContext(context.WithValue(r.Context(), books_controller.MiddleWareBook, dbBook))
+				dbChapter := &model.Chapter{
+					ID:      1,
+					BookID:  1,
+					Name:    "Chapter One",
+					Price:   100,
+					Content: "Nice chapter",
+				}
+				r = r.WithContext(context.WithValue(r.Context(), authMiddleware.AuthenticatedUserId, uint64(1)))
+				r = r.WithContext(context.WithValue(r.Context(), middleWareChapter, dbChapter))
+
+				// when
+				controller.PatchChapter(w, r)
+
+				// then
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), `"field":"status"`)
+			}
+		})
+
 		t.Run("should return 500 INTERNAL SERVER ERROR if query failed", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/api/v1/chapters/1",
-				strings.NewReader(`{"id": 999}`))
+				strings.NewReader(`{}`))
 			dbBook := &booksModel.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -407,7 +476,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				Update(uint64(1), uint64(1), &model.ChapterPatch{}).
+				Update(gomock.Any(), uint64(1), uint64(1), &model.ChapterPatch{}).
 				Return(errors.New("database error"))
 
 			// when
@@ -421,7 +490,7 @@ func TestChapterDefaultController(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/api/v1/chapters/1",
-				strings.NewReader(`{"id": 999}`))
+				strings.NewReader(`{}`))
 			dbBook := &booksModel.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -441,7 +510,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				Update(uint64(1), uint64(1), &model.ChapterPatch{}).
+				Update(gomock.Any(), uint64(1), uint64(1), &model.ChapterPatch{}).
 				Return(errors.New("database error"))
 
 			// when
@@ -451,11 +520,11 @@ func TestChapterDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, w.Code)
 		})
 
-		t.Run("should return 401 If you are not the creator of the chapter", func(t *testing.T) {
+		t.Run("should return 403 FORBIDDEN if you are not the creator of the chapter", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("PUT", "/api/v1/chapters/1",
-				strings.NewReader(`{"id": 999}`))
+				strings.NewReader(`{}`))
 			dbBook := &booksModel.Book{
 				ID:          1,
 				Name:        "Book One",
@@ -477,7 +546,7 @@ func TestChapterDefaultController(t *testing.T) {
 			controller.PatchChapter(w, r)
 
 			// then
-			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, http.StatusForbidden, w.Code)
 		})
 
 		t.Run("should update one chapter", func(t *testing.T) {
@@ -505,7 +574,7 @@ func TestChapterDefaultController(t *testing.T) {
 			newDescription := "a fine chapter"
 			chapterRepository.
 				EXPECT().
-				Update(uint64(1), uint64(1), &model.ChapterPatch{Content: &newDescription}).
+				Update(gomock.Any(), uint64(1), uint64(1), &model.ChapterPatch{Content: &newDescription}).
 				Return(nil)
 
 			// when
@@ -540,7 +609,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				Delete([]*model.Chapter{dbChapter}).
+				Delete(gomock.Any(), []*model.Chapter{dbChapter}).
 				Return(errors.New("database error"))
 
 			// when
@@ -550,7 +619,7 @@ func TestChapterDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, w.Code)
 		})
 
-		t.Run("should return 401 if not the user who created the chapter", func(t *testing.T) {
+		t.Run("should return 403 FORBIDDEN if not the user who created the chapter", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("DELETE", "/api/v1/chapters/1", nil)
@@ -575,7 +644,7 @@ func TestChapterDefaultController(t *testing.T) {
 			controller.DeleteChapter(w, r)
 
 			// then
-			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, http.StatusForbidden, w.Code)
 		})
 
 		t.Run("should return 400 BAD REQUEST because chapter is published", func(t *testing.T) {
@@ -630,7 +699,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				Delete([]*model.Chapter{dbChapter}).
+				Delete(gomock.Any(), []*model.Chapter{dbChapter}).
 				Return(nil)
 
 			// when
@@ -642,11 +711,10 @@ func TestChapterDefaultController(t *testing.T) {
 	})
 
 	t.Run("LoadChapterMiddleware", func(t *testing.T) {
-		t.Run("Should return 400 if the chapterid cannot be parsed", func(t *testing.T) {
+		t.Run("Should return 400 if the chapterid is missing", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/chapters/aaa", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "chapterid", "aaa"))
 			dbBook := &booksModel.Book{}
 
 			r = r.WithContext(context.WithValue(r.Context(), books_controller.MiddleWareBook, dbBook))
@@ -661,7 +729,7 @@ func TestChapterDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, w.Code)
 		})
 
-		t.Run("Should return 404 if the query fails", func(t *testing.T) {
+		t.Run("Should return 404 if the chapter doesn't exist", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/chapters/1", nil)
@@ -673,7 +741,7 @@ func TestChapterDefaultController(t *testing.T) {
 			}
 
 			r = r.WithContext(context.WithValue(r.Context(), books_controller.MiddleWareBook, dbBook))
-			r = r.WithContext(context.WithValue(r.Context(), "chapterid", "1"))
+			r = router.WithParam(r, "chapterid", uint64(1))
 			dbChapter := &model.Chapter{
 				ID:      1,
 				BookID:  1,
@@ -684,8 +752,8 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				FindByIdAndBookId(dbChapter.BookID, dbBook.ID).
-				Return(nil, errors.New("database error"))
+				FindByIdAndBookId(gomock.Any(), dbChapter.BookID, dbBook.ID).
+				Return(nil, sql.ErrNoRows)
 
 			// when
 			called := false
@@ -697,6 +765,42 @@ func TestChapterDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusNotFound, w.Code)
 		})
 
+		t.Run("Should return 500 if the query fails", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/api/v1/chapters/1", nil)
+			dbBook := &booksModel.Book{
+				ID:          1,
+				Name:        "Book One",
+				AuthorID:    1,
+				Description: "! good book",
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), books_controller.MiddleWareBook, dbBook))
+			r = router.WithParam(r, "chapterid", uint64(1))
+			dbChapter := &model.Chapter{
+				ID:      1,
+				BookID:  1,
+				Name:    "Chapter One",
+				Price:   100,
+				Content: "Nice chapter",
+			}
+
+			chapterRepository.
+				EXPECT().
+				FindByIdAndBookId(gomock.Any(), dbChapter.BookID, dbBook.ID).
+				Return(nil, errors.New("database error"))
+
+			// when
+			called := false
+			controller.LoadChapterMiddleware(w, r, func(r *http.Request) {
+				called = true
+			})
+
+			assert.Equal(t, false, called)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+		})
+
 		t.Run("Should return 200 if it succeeds", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
@@ -709,7 +813,7 @@ func TestChapterDefaultController(t *testing.T) {
 			}
 
 			r = r.WithContext(context.WithValue(r.Context(), books_controller.MiddleWareBook, dbBook))
-			r = r.WithContext(context.WithValue(r.Context(), "chapterid", "1"))
+			r = router.WithParam(r, "chapterid", uint64(1))
 			dbChapter := &model.Chapter{
 				ID:      1,
 				BookID:  1,
@@ -720,7 +824,7 @@ func TestChapterDefaultController(t *testing.T) {
 
 			chapterRepository.
 				EXPECT().
-				FindByIdAndBookId(dbChapter.BookID, dbBook.ID).
+				FindByIdAndBookId(gomock.Any(), dbChapter.BookID, dbBook.ID).
 				Return(dbChapter, nil)
 
 			// when
@@ -783,8 +887,8 @@ func TestChapterDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				ValidateChapterId(uint64(1), uint64(1), uint64(1)).
-				Return(nil, shared_types.InvalidArgument, errors.New("service error"))
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1), uint64(1)).
+				Return(nil, shared_types.NewError(shared_types.FailedPrecondition, "AUTHOR_IS_BUYER", "service error"))
 
 			// when
 			controller.ValidateChapterId(w, r)
@@ -808,8 +912,8 @@ func TestChapterDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				ValidateChapterId(uint64(1), uint64(1), uint64(1)).
-				Return(&result, shared_types.OK, nil)
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1), uint64(1)).
+				Return(&result, nil)
 
 			// when
 			controller.ValidateChapterId(w, r)
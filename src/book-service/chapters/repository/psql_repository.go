@@ -1,8 +1,10 @@
 package chapters_repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
@@ -15,8 +17,7 @@ type PsqlRepository struct {
 }
 
 func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
-	dsn := config.Dsn()
-	db, err := sql.Open("postgres", dsn)
+	db, err := database.Open("postgres", config)
 	db.SetMaxOpenConns(10)
 	if err != nil {
 		return nil, err
@@ -25,6 +26,21 @@ func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
 	return &PsqlRepository{db}, nil
 }
 
+// Close closes the connection pool of the repository.
+func (repo *PsqlRepository) Close() error {
+	return repo.db.Close()
+}
+
+// Ping checks that the database can be reached.
+func (repo *PsqlRepository) Ping(ctx context.Context) error {
+	return repo.db.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool for the metrics.
+func (repo *PsqlRepository) Stats() sql.DBStats {
+	return repo.db.Stats()
+}
+
 const createChaptersTable = `
 create table if not exists chapters (
 	id			int not null,
@@ -38,8 +54,8 @@ create table if not exists chapters (
 )
 `
 
-func (repo *PsqlRepository) Migrate() error {
-	_, err := repo.db.Exec(createChaptersTable)
+func (repo *PsqlRepository) Migrate(ctx context.Context) error {
+	_, err := repo.db.ExecContext(ctx, createChaptersTable)
 	return err
 }
 
@@ -51,16 +67,18 @@ const createChaptersHighestIdQuery = `
 select max(id) from chapters where bookId = $1
 `
 
-func (repo *PsqlRepository) Create(chapters []*model.Chapter) error {
+func (repo *PsqlRepository) Create(ctx context.Context, chapters []*model.Chapter) error {
 	placeholders := make([]string, len(chapters))
 	values := make([]interface{}, len(chapters)*5)
 
-	row := repo.db.QueryRow(createChaptersHighestIdQuery, chapters[0].BookID)
+	row := repo.db.QueryRowContext(ctx, createChaptersHighestIdQuery, chapters[0].BookID)
 
-	var id int64 = 0
-	if err := row.Scan(&id); err != nil {
-		fmt.Println(err.Error())
+	// max is NULL for the first chapter of a book
+	var highestId sql.NullInt64
+	if err := row.Scan(&highestId); err != nil {
+		slog.ErrorContext(ctx, "could not query the highest chapter id", "book_id", chapters[0].BookID, "error", err)
 	}
+	id := highestId.Int64
 
 	for i := 0; i < len(chapters); i++ {
 		id++
@@ -73,7 +91,7 @@ func (repo *PsqlRepository) Create(chapters []*model.Chapter) error {
 	}
 
 	query := fmt.Sprintf(createChaptersBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, values...)
+	_, err := repo.db.ExecContext(ctx, query, values...)
 	return err
 }
 
@@ -81,8 +99,8 @@ const updateChapterBatchQuery = `
 update chapters set name = $1, price = $2, content = $3, status = $4 where id = $5 and bookId = $6
 `
 
-func (repo *PsqlRepository) Update(id uint64, bookId uint64, updateChapter *model.ChapterPatch) error {
-	dbChapter, err := repo.FindByIdAndBookId(id, bookId)
+func (repo *PsqlRepository) Update(ctx context.Context, id uint64, bookId uint64, updateChapter *model.ChapterPatch) error {
+	dbChapter, err := repo.FindByIdAndBookId(ctx, id, bookId)
 	if err != nil {
 		return err
 	}
@@ -99,7 +117,7 @@ func (repo *PsqlRepository) Update(id uint64, bookId uint64, updateChapter *mode
 		dbChapter.Status = *updateChapter.Status
 	}
 
-	_, err = repo.db.Exec(updateChapterBatchQuery, dbChapter.Name, dbChapter.Price, dbChapter.Content, dbChapter.Status, id, bookId)
+	_, err = repo.db.ExecContext(ctx, updateChapterBatchQuery, dbChapter.Name, dbChapter.Price, dbChapter.Content, dbChapter.Status, id, bookId)
 	return err
 }
 
@@ -107,8 +125,8 @@ const findAllChaptersIdByBookIdQuery = `
 select id, bookId, name, price, status from chapters where bookId = $1 ORDER BY id ASC
 `
 
-func (repo *PsqlRepository) FindAllPreviewsByBookId(bookId uint64) ([]*model.ChapterPreview, error) {
-	rows, err := repo.db.Query(findAllChaptersIdByBookIdQuery, bookId)
+func (repo *PsqlRepository) FindAllPreviewsByBookId(ctx context.Context, bookId uint64) ([]*model.ChapterPreview, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllChaptersIdByBookIdQuery, bookId)
 	if err != nil {
 		return nil, err
 	}
@@ -129,8 +147,8 @@ const findChapterByIdAndBookIdQuery = `
 select id, bookId, name, price, content, status from chapters where id = $1 and bookId = $2
 `
 
-func (repo *PsqlRepository) FindByIdAndBookId(id uint64, bookId uint64) (*model.Chapter, error) {
-	row := repo.db.QueryRow(findChapterByIdAndBookIdQuery, id, bookId)
+func (repo *PsqlRepository) FindByIdAndBookId(ctx context.Context, id uint64, bookId uint64) (*model.Chapter, error) {
+	row := repo.db.QueryRowContext(ctx, findChapterByIdAndBookIdQuery, id, bookId)
 
 	var chapter model.Chapter
 	if err := row.Scan(&chapter.ID, &chapter.BookID, &chapter.Name, &chapter.Price, &chapter.Content, &chapter.Status); err != nil {
@@ -144,7 +162,7 @@ const deleteChaptersBatchQuery = `
 delete from chapters where (id, bookId) in (%s)
 `
 
-func (repo *PsqlRepository) Delete(chapters []*model.Chapter) error {
+func (repo *PsqlRepository) Delete(ctx context.Context, chapters []*model.Chapter) error {
 	placeholders := make([]string, len(chapters))
 	ids := make([]interface{}, len(chapters)*2)
 	for i := 0; i < len(chapters); i++ {
@@ -153,7 +171,7 @@ func (repo *PsqlRepository) Delete(chapters []*model.Chapter) error {
 		ids[i*2+1] = chapters[i].BookID
 	}
 	query := fmt.Sprintf(deleteChaptersBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, ids...)
+	_, err := repo.db.ExecContext(ctx, query, ids...)
 	return err
 }
 
@@ -161,8 +179,8 @@ const validateChapterIdQuery = `
 select c.id, c.bookId, c.name, c.price, c.content, c.status, b.authorId from chapters c inner join books b on c.bookId = b.id where c.id = $1 and c.bookId = $2
 `
 
-func (repo *PsqlRepository) ValidateChapterId(id uint64, bookId uint64) (*model.Chapter, *uint64, error) {
-	row := repo.db.QueryRow(validateChapterIdQuery, id, bookId)
+func (repo *PsqlRepository) ValidateChapterId(ctx context.Context, id uint64, bookId uint64) (*model.Chapter, *uint64, error) {
+	row := repo.db.QueryRowContext(ctx, validateChapterIdQuery, id, bookId)
 
 	var chapter model.Chapter
 	var receivingUserId uint64
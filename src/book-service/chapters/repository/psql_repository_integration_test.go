@@ -46,7 +46,7 @@ func TestIntegrationPsqlChapterRepository(t *testing.T) {
 			// given
 			createUserAndBookTable(t, repository.db)
 			// when
-			err := repository.Migrate()
+			err := repository.Migrate(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -77,7 +77,7 @@ func TestIntegrationPsqlChapterRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Create(chapters)
+			err := repository.Create(context.Background(), chapters)
 
 			// then
 			assert.NoError(t, err)
@@ -105,7 +105,7 @@ func TestIntegrationPsqlChapterRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Update(3, 1, newChapterData)
+			err := repository.Update(context.Background(), 3, 1, newChapterData)
 
 			// then
 			assert.NoError(t, err)
@@ -146,7 +146,7 @@ func TestIntegrationPsqlChapterRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Delete([]*model.Chapter{chapters[1]})
+			err := repository.Delete(context.Background(), []*model.Chapter{chapters[1]})
 
 			// then
 			assert.NoError(t, err)
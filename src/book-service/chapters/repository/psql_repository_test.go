@@ -1,6 +1,7 @@
 package chapters_repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -38,7 +39,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Create(chapters)
+			err := repository.Create(context.Background(), chapters)
 
 			// then
 			assert.Error(t, err)
@@ -74,7 +75,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Create(chapters)
+			err := repository.Create(context.Background(), chapters)
 
 			// then
 			assert.NoError(t, err)
@@ -108,7 +109,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Create(chapters)
+			err := repository.Create(context.Background(), chapters)
 
 			// then
 			assert.NoError(t, err)
@@ -126,7 +127,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			chapters, err := repository.FindByIdAndBookId(id, bookId)
+			chapters, err := repository.FindByIdAndBookId(context.Background(), id, bookId)
 
 			// then
 			assert.Error(t, err)
@@ -144,7 +145,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(1, 1, "doesnt matter", 0, "doesnt matter", 0))
 
 			// when
-			chapter, err := repository.FindByIdAndBookId(id, bookId)
+			chapter, err := repository.FindByIdAndBookId(context.Background(), id, bookId)
 
 			// then
 			assert.NoError(t, err)
@@ -160,7 +161,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			chapterPreviews, err := repository.FindAllPreviewsByBookId(uint64(2))
+			chapterPreviews, err := repository.FindAllPreviewsByBookId(context.Background(), uint64(2))
 
 			// then
 			assert.Error(t, err)
@@ -192,7 +193,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(2, 1, "Chapter Two", 0, 0))
 
 			// when
-			chapterPreviews, err := repository.FindAllPreviewsByBookId(uint64(1))
+			chapterPreviews, err := repository.FindAllPreviewsByBookId(context.Background(), uint64(1))
 
 			// then
 			assert.NoError(t, err)
@@ -226,7 +227,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Delete(chapters)
+			err := repository.Delete(context.Background(), chapters)
 
 			// then
 			assert.Error(t, err)
@@ -257,7 +258,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Delete(chapters)
+			err := repository.Delete(context.Background(), chapters)
 
 			// then
 			assert.NoError(t, err)
@@ -273,7 +274,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			chapter, receivingUserId, err := repository.ValidateChapterId(1, 1)
+			chapter, receivingUserId, err := repository.ValidateChapterId(context.Background(), 1, 1)
 
 			// then
 			assert.Error(t, err)
@@ -300,7 +301,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(1, 1, "Chapter One", 0, "doesnt matter", 0, 1))
 
 			// when
-			chapter, receivingUserId, err := repository.ValidateChapterId(1, 1)
+			chapter, receivingUserId, err := repository.ValidateChapterId(context.Background(), 1, 1)
 
 			// then
 			assert.NoError(t, err)
@@ -335,7 +336,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Update(1, 1, newChapterData)
+			err := repository.Update(context.Background(), 1, 1, newChapterData)
 
 			// then
 			assert.Error(t, err)
@@ -366,11 +367,22 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 1))
 
 			// when
-			err := repository.Update(1, 1, newChapterData)
+			err := repository.Update(context.Background(), 1, 1, newChapterData)
 
 			// then
 			assert.NoError(t, err)
 		})
 	})
 
+	t.Run("Close", func(t *testing.T) {
+		// given
+		dbmock.ExpectClose()
+
+		// when
+		err := repository.Close()
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, dbmock.ExpectationsWereMet())
+	})
 }
@@ -1,13 +1,17 @@
 package chapters_repository
 
-import "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
+import (
+	"context"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
+)
 
 type Repository interface {
-	Migrate() error
-	Create([]*model.Chapter) error
-	Update(id uint64, bookId uint64, updateChapter *model.ChapterPatch) error
-	FindAllPreviewsByBookId(bookId uint64) ([]*model.ChapterPreview, error)
-	FindByIdAndBookId(id uint64, bookId uint64) (*model.Chapter, error)
-	ValidateChapterId(id uint64, bookId uint64) (*model.Chapter, *uint64, error)
-	Delete([]*model.Chapter) error
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, chapters []*model.Chapter) error
+	Update(ctx context.Context, id uint64, bookId uint64, updateChapter *model.ChapterPatch) error
+	FindAllPreviewsByBookId(ctx context.Context, bookId uint64) ([]*model.ChapterPreview, error)
+	FindByIdAndBookId(ctx context.Context, id uint64, bookId uint64) (*model.Chapter, error)
+	ValidateChapterId(ctx context.Context, id uint64, bookId uint64) (*model.Chapter, *uint64, error)
+	Delete(ctx context.Context, chapters []*model.Chapter) error
 }
@@ -2,11 +2,10 @@ package grpc
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/service"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/book-service/proto"
-	"google.golang.org/grpc/status"
 )
 
 type server struct {
@@ -19,10 +18,10 @@ func NewServer(service service.Service) proto.BookServiceServer {
 }
 
 func (s *server) ValidateChapterId(ctx context.Context, req *proto.ValidateChapterIdRequest) (*proto.ValidateChapterIdResponse, error) {
-	result, statusCode, err := s.service.ValidateChapterId(req.UserId, req.ChapterId, req.BookId)
+	result, err := s.service.ValidateChapterId(ctx, req.UserId, req.ChapterId, req.BookId)
 	if err != nil {
-		log.Println("ERROR [ValidateChapterId - Execute ValidateChapterId]: ", err.Error())
-		return nil, status.Error(statusCode.ToGRPCStatusCode(), err.Error())
+		slog.ErrorContext(ctx, "could not validate the chapter", "error", err)
+		return nil, err
 	}
 
 	return &proto.ValidateChapterIdResponse{
@@ -1,9 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net"
 	"net/http"
 	"net/url"
 
@@ -15,13 +15,18 @@ import (
 	grpc_server "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/grpc"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/service"
 	transaction_service_client "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/transaction-service-client"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/database"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/book-service/proto"
 	tproto "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/transaction-service/proto"
 	uproto "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/user-service/proto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
-	"github.com/caarlos0/env/v10"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/lifecycle"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/tracing"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -30,98 +35,127 @@ import (
 
 type ApplicationConfig struct {
 	Database                  database.PsqlConfig `envPrefix:"POSTGRES_"`
-	Port                      uint16              `env:"PORT" envDefault:"8080"`
-	GrpcPort                  uint16              `env:"GRPC_PORT" envDefault:"8081"`
+	Port                      uint16              `env:"PORT" envDefault:"8080" validate:"min=1"`
+	MetricsPort               uint16              `env:"METRICS_PORT" envDefault:"9090" validate:"min=1"`
+	GrpcPort                  uint16              `env:"GRPC_PORT" envDefault:"8081" validate:"min=1"`
 	GrpcCommunication         bool                `env:"GRPC_COMMUNICATION" envDefault:"true"`
 	AuthIsActive              bool                `env:"AUTH_IS_ACTIVE" envDefault:"false"`
 	AuthServiceEndpoint       url.URL             `env:"AUTH_SERVICE_ENDPOINT,notEmpty"`
 	TransactionServiceBaseUrl url.URL             `env:"TRANSACTION_SERVICE_ENDPOINT,notEmpty"`
+	HTTP                      middleware.Config   `envPrefix:"HTTP_"`
+	Health                    health.Config       `envPrefix:"HEALTH_"`
+	Tracing                   tracing.Config      `envPrefix:"TRACING_"`
+	Log                       logger.Config       `envPrefix:"LOG_"`
+	Lifecycle                 lifecycle.Config
 }
 
 func main() {
 	godotenv.Load()
 
 	config := ApplicationConfig{}
-	if err := env.Parse(&config); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	if err := appconfig.Load("book-service", &config); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
 
+	logger.SetDefault(config.Log)
+
+	runner := lifecycle.NewRunner(config.Lifecycle)
+
+	tracerProvider, err := tracing.NewProvider(context.Background(), "book-service", config.Tracing)
+	if err != nil {
+		log.Fatalf("could not create the tracer provider: %s", err.Error())
+	}
+	runner.AddCloser("tracer provider", tracerProvider)
+
+	checker := health.NewChecker(config.Health)
+	runner.OnShutdown(checker.Shutdown)
+
 	bookRepository, err := books_repository.NewPsqlRepository(config.Database)
 	if err != nil {
 		log.Fatalf("could not instanciate bookRepo: %v", err)
 	}
+	runner.AddCloser("book repository", bookRepository)
+	if err := metrics.RegisterDBStats("books", bookRepository); err != nil {
+		log.Fatalf("could not register the database metrics: %s", err.Error())
+	}
 
 	chapterRepository, err := chapters_repository.NewPsqlRepository(config.Database)
 	if err != nil {
 		log.Fatalf("could not instanciate chapterRepo: %v", err)
 	}
+	runner.AddCloser("chapter repository", chapterRepository)
+	if err := metrics.RegisterDBStats("chapters", chapterRepository); err != nil {
+		log.Fatalf("could not register the database metrics: %s", err.Error())
+	}
 
 	var authRepository auth_middleware.Repository
 	var transactionServiceClient transaction_service_client.Repository
 
 	if config.GrpcCommunication {
-		userConn, err := grpc.Dial(config.AuthServiceEndpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		userConn, err := grpc.Dial(config.AuthServiceEndpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()), metrics.DialOption(), tracing.DialOption(), logger.DialOption())
 		if err != nil {
 			log.Fatalf("could not connect: %v", err)
 		}
-		defer userConn.Close()
+		runner.AddCloser("user-service connection", userConn)
 
-		transactionConn, err := grpc.Dial(config.TransactionServiceBaseUrl.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		transactionConn, err := grpc.Dial(config.TransactionServiceBaseUrl.Host, grpc.WithTransportCredentials(insecure.NewCredentials()), metrics.DialOption(), tracing.DialOption(), logger.DialOption())
 		if err != nil {
 			log.Fatalf("could not connect: %v", err)
 		}
-		defer transactionConn.Close()
+		runner.AddCloser("transaction-service connection", transactionConn)
 
 		userGrpcClient := uproto.NewUserServiceClient(userConn)
 		authRepository = auth_middleware.NewGRPCRepository(userGrpcClient)
 
 		transactionGrpcClient := tproto.NewTransactionServiceClient(transactionConn)
 		transactionServiceClient = transaction_service_client.NewGRPCRepository(transactionGrpcClient)
+
+		checker.Register("user-service", health.GRPCConn(userConn))
+		checker.RegisterOptional("transaction-service", health.GRPCConn(transactionConn))
 	} else {
-		authRepository = auth_middleware.NewHTTPRepository(&config.AuthServiceEndpoint, http.DefaultClient)
-		transactionServiceClient = transaction_service_client.NewHTTPRepository(&config.TransactionServiceBaseUrl, http.DefaultClient)
+		httpClient := &http.Client{Transport: tracing.Transport(http.DefaultTransport)}
+		authRepository = auth_middleware.NewHTTPRepository(&config.AuthServiceEndpoint, httpClient)
+		transactionServiceClient = transaction_service_client.NewHTTPRepository(&config.TransactionServiceBaseUrl, httpClient)
+
+		checker.Register("user-service", health.HTTP(http.DefaultClient, fmt.Sprintf("%s://%s/health/live", config.AuthServiceEndpoint.Scheme, config.AuthServiceEndpoint.Host)))
+		checker.RegisterOptional("transaction-service", health.HTTP(http.DefaultClient, fmt.Sprintf("%s://%s/health/live", config.TransactionServiceBaseUrl.Scheme, config.TransactionServiceBaseUrl.Host)))
 	}
 
 	service := service.NewDefaultService(chapterRepository)
 
 	authController := auth_middleware.NewDefaultController(authRepository, config.AuthIsActive)
-	healthController := health.NewDefaultController()
 
 	bookController := books_controller.NewDefaultController(bookRepository)
 	chapterController := chapters_controller.NewDefaultController(chapterRepository, service, transactionServiceClient)
 
-	handler := router.New(authController, bookController, chapterController, healthController)
+	checker.Register("books-database", bookRepository.Ping)
+	checker.Register("chapters-database", chapterRepository.Ping)
+	healthController := health.NewDefaultController(checker)
 
-	if err := bookRepository.Migrate(); err != nil {
+	handler := middleware.Default(router.New(authController, bookController, chapterController, healthController), config.HTTP)
+
+	if err := bookRepository.Migrate(context.Background()); err != nil {
 		log.Fatalf("could not migrate: %s", err.Error())
 	}
-	if err := chapterRepository.Migrate(); err != nil {
+	if err := chapterRepository.Migrate(context.Background()); err != nil {
 		log.Fatalf("could not migrate: %s", err.Error())
 	}
 
 	if config.GrpcCommunication {
-		grpcAddr := fmt.Sprintf("0.0.0.0:%d", config.GrpcPort)
-		listener, err := net.Listen("tcp", grpcAddr)
-		if err != nil {
-			log.Fatalf("could not listen: %v", err)
-		}
-
-		srv := grpc.NewServer()
+		srv := grpc.NewServer(append(metrics.ServerOptions(), tracing.ServerOption(), logger.ServerOption())...)
 		reflection.Register(srv)
 		grpcServer := grpc_server.NewServer(service)
 		proto.RegisterBookServiceServer(srv, grpcServer)
 
-		go func() {
-			log.Printf("GRPC-Server started on Port: %d\n", config.GrpcPort)
-			if err := srv.Serve(listener); err != nil {
-				log.Fatalf("could not serve: %v", err)
-			}
-		}()
+		health.RegisterGRPC(srv, checker)
+
+		runner.AddGRPCServer("GRPC-Server", fmt.Sprintf("0.0.0.0:%d", config.GrpcPort), srv)
 	}
 
-	log.Printf("REST-Server started on Port: %d\n", config.Port)
-	addr := fmt.Sprintf("0.0.0.0:%d", config.Port)
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		log.Fatalf("error while listen and serve: %s", err.Error())
+	runner.AddHTTPServer("REST-Server", fmt.Sprintf("0.0.0.0:%d", config.Port), handler)
+	runner.AddHTTPServer("Metrics-Server", fmt.Sprintf("0.0.0.0:%d", config.MetricsPort), metrics.Handler())
+
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatalf("error while running the servers: %s", err.Error())
 	}
 }
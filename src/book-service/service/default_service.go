@@ -1,13 +1,14 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/model"
 	chapters_repository "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/chapters/repository"
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/utils"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -29,38 +30,35 @@ type result struct {
 	ReceivingUserId *uint64
 }
 
-func (s *DefaultService) ValidateChapterId(userId uint64, chapterId uint64, bookId uint64) (*shared_types.ValidateChapterIdResponse, shared_types.Code, error) {
-	value, err, _ := s.g.Do(fmt.Sprintf("validate-%d-%d", chapterId, bookId), func() (interface{}, error) {
-		chapter, receivingUserId, err := s.repository.ValidateChapterId(chapterId, bookId)
+func (s *DefaultService) ValidateChapterId(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) (*shared_types.ValidateChapterIdResponse, error) {
+	res, err := utils.DoShared(ctx, s.g, fmt.Sprintf("validate-%d-%d", chapterId, bookId), func(ctx context.Context) (*result, error) {
+		chapter, receivingUserId, err := s.repository.ValidateChapterId(ctx, chapterId, bookId)
 		return &result{
 			Chapter:         chapter,
 			ReceivingUserId: receivingUserId,
 		}, err
 	})
 	if err != nil {
-		log.Println("ERROR [ValidateChapterId - Execute ValidateChapterId]: ", err.Error())
-		return nil, shared_types.NotFound, err
+		slog.ErrorContext(ctx, "could not validate the chapter", "error", err)
+		return nil, shared_types.WrapNotFound(err, "CHAPTER_NOT_FOUND", "chapter not found")
 	}
-	res := value.(*result)
 	chapter := res.Chapter
 	receivingUserId := res.ReceivingUserId
 
 	if *receivingUserId == userId {
-		log.Println("ERROR [ValidateChapterId - receivingUserId == request.UserId]: ", "Author and buyer are the same")
-		return nil, shared_types.InvalidArgument, errors.New("author and buyer are the same")
+		slog.WarnContext(ctx, "author and buyer are the same")
+		return nil, shared_types.NewError(shared_types.FailedPrecondition, "AUTHOR_IS_BUYER", "author and buyer are the same")
 	}
 
 	if chapter.Status != model.Published {
-		log.Println("ERROR [ValidateChapterId - chapter.Status != model.Published]: ", "Chapter is not published")
-		return nil, shared_types.InvalidArgument, errors.New("chapter is not published")
+		slog.WarnContext(ctx, "chapter is not published")
+		return nil, shared_types.NewError(shared_types.FailedPrecondition, "CHAPTER_NOT_PUBLISHED", "chapter is not published")
 	}
 
 	return &shared_types.ValidateChapterIdResponse{
-			ChapterId:       chapter.ID,
-			BookId:          chapter.BookID,
-			ReceivingUserId: *receivingUserId,
-			Amount:          chapter.Price,
-		},
-		shared_types.OK,
-		nil
+		ChapterId:       chapter.ID,
+		BookId:          chapter.BookID,
+		ReceivingUserId: *receivingUserId,
+		Amount:          chapter.Price,
+	}, nil
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 
 	chapters_mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/book-service/_mocks/chapters"
@@ -22,19 +24,35 @@ func TestDefaultService(t *testing.T) {
 			// given
 			repository.
 				EXPECT().
-				ValidateChapterId(uint64(1), uint64(1)).
-				Return(nil, nil, errors.New("not found"))
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1)).
+				Return(nil, nil, sql.ErrNoRows)
 
 			// when
-			response, statusCode, err := service.ValidateChapterId(1, 1, 1)
+			response, err := service.ValidateChapterId(context.Background(), 1, 1, 1)
 
 			// then
 			assert.Nil(t, response)
-			assert.Equal(t, shared_types.NotFound, statusCode)
+			assert.Equal(t, shared_types.NotFound, shared_types.CodeOf(err))
 			assert.Error(t, err)
 		})
 
-		t.Run("should return InvalidArgument if bookAuthor and Buyer are the same", func(t *testing.T) {
+		t.Run("should return Internal if the repository fails", func(t *testing.T) {
+			// given
+			repository.
+				EXPECT().
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1)).
+				Return(nil, nil, errors.New("connection refused"))
+
+			// when
+			response, err := service.ValidateChapterId(context.Background(), 1, 1, 1)
+
+			// then
+			assert.Nil(t, response)
+			assert.Equal(t, shared_types.Internal, shared_types.CodeOf(err))
+			assert.Error(t, err)
+		})
+
+		t.Run("should return FailedPrecondition if bookAuthor and Buyer are the same", func(t *testing.T) {
 			// given
 			shouldAuthor := uint64(1)
 			shoulChapter := model.Chapter{
@@ -46,19 +64,19 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				ValidateChapterId(uint64(1), uint64(1)).
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1)).
 				Return(&shoulChapter, &shouldAuthor, nil)
 
 			// when
-			response, statusCode, err := service.ValidateChapterId(1, 1, 1)
+			response, err := service.ValidateChapterId(context.Background(), 1, 1, 1)
 
 			// then
 			assert.Nil(t, response)
-			assert.Equal(t, shared_types.InvalidArgument, statusCode)
+			assert.Equal(t, shared_types.FailedPrecondition, shared_types.CodeOf(err))
 			assert.Error(t, err)
 		})
 
-		t.Run("should return InvalidArgument if the book is a draft", func(t *testing.T) {
+		t.Run("should return FailedPrecondition if the book is a draft", func(t *testing.T) {
 			// given
 			shouldAuthor := uint64(2)
 			shoulChapter := model.Chapter{
@@ -70,15 +88,15 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				ValidateChapterId(uint64(1), uint64(1)).
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1)).
 				Return(&shoulChapter, &shouldAuthor, nil)
 
 			// when
-			response, statusCode, err := service.ValidateChapterId(1, 1, 1)
+			response, err := service.ValidateChapterId(context.Background(), 1, 1, 1)
 
 			// then
 			assert.Nil(t, response)
-			assert.Equal(t, shared_types.InvalidArgument, statusCode)
+			assert.Equal(t, shared_types.FailedPrecondition, shared_types.CodeOf(err))
 			assert.Error(t, err)
 		})
 
@@ -100,15 +118,15 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				ValidateChapterId(uint64(1), uint64(1)).
+				ValidateChapterId(gomock.Any(), uint64(1), uint64(1)).
 				Return(&shoulChapter, &shouldAuthor, nil)
 
 			// when
-			response, statusCode, err := service.ValidateChapterId(1, 1, 1)
+			response, err := service.ValidateChapterId(context.Background(), 1, 1, 1)
 
 			// then
 			assert.Equal(t, shouldReponse, response)
-			assert.Equal(t, shared_types.OK, statusCode)
+			assert.Equal(t, shared_types.OK, shared_types.CodeOf(err))
 			assert.NoError(t, err)
 		})
 	})
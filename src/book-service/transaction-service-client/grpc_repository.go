@@ -5,8 +5,7 @@ import (
 	"errors"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/transaction-service/proto"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 )
 
 type GRPCRepository struct {
@@ -19,20 +18,16 @@ func NewGRPCRepository(client proto.TransactionServiceClient) *GRPCRepository {
 	}
 }
 
-func (repo *GRPCRepository) CheckChapterBought(userId uint64, chapterId uint64, bookId uint64) error {
+func (repo *GRPCRepository) CheckChapterBought(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) error {
 	req := &proto.CheckChapterBoughtRequest{
 		UserId:    userId,
 		ChapterId: chapterId,
 		BookId:    bookId,
 	}
 
-	res, err := repo.client.CheckChapterBought(context.Background(), req)
+	res, err := repo.client.CheckChapterBought(ctx, req)
 	if err != nil {
-		st, ok := status.FromError(err)
-		if ok && st.Code() == codes.NotFound {
-			return errors.New("you haven't bought this book")
-		}
-		return err
+		return shared_types.FromGRPCError(err)
 	}
 
 	if !res.Success {
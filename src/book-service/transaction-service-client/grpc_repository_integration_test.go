@@ -70,7 +70,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			err := repository.CheckChapterBought(1, 1000, 1)
+			err := repository.CheckChapterBought(context.Background(), 1, 1000, 1)
 
 			// then
 			assert.Error(t, err)
@@ -81,7 +81,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			err := repository.CheckChapterBought(1, 1, 1000)
+			err := repository.CheckChapterBought(context.Background(), 1, 1, 1000)
 
 			// then
 			assert.Error(t, err)
@@ -92,7 +92,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			err := repository.CheckChapterBought(2, 3, 1)
+			err := repository.CheckChapterBought(context.Background(), 2, 3, 1)
 
 			// then
 			assert.Error(t, err)
@@ -103,7 +103,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			err := repository.CheckChapterBought(2, 1, 1)
+			err := repository.CheckChapterBought(context.Background(), 2, 1, 1)
 
 			// then
 			assert.NoError(t, err)
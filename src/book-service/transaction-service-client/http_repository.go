@@ -2,6 +2,7 @@ package transaction_service_client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
@@ -20,7 +21,7 @@ func NewHTTPRepository(transactionServiceURL *url.URL, client client.Client) *HT
 	return &HTTPRepository{transactionServiceURL, client}
 }
 
-func (repo *HTTPRepository) CheckChapterBought(userId uint64, chapterId uint64, bookId uint64) error {
+func (repo *HTTPRepository) CheckChapterBought(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) error {
 	host := repo.transactionServiceURL.String()
 
 	body := &shared_types.CheckChapterBoughtRequest{UserID: userId, ChapterID: chapterId, BookID: bookId}
@@ -29,7 +30,7 @@ func (repo *HTTPRepository) CheckChapterBought(userId uint64, chapterId uint64,
 		return err
 	}
 
-	req, err := http.NewRequest("POST", host, bytes.NewBuffer(reqBody))
+	req, err := client.NewRequest(ctx, "POST", host, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return err
 	}
@@ -40,7 +41,7 @@ func (repo *HTTPRepository) CheckChapterBought(userId uint64, chapterId uint64,
 	}
 
 	if res.StatusCode == http.StatusNotFound {
-		return errors.New("you haven't bought this book")
+		return shared_types.NewError(shared_types.NotFound, "TRANSACTION_NOT_FOUND", "you haven't bought this chapter")
 	}
 
 	if res.StatusCode != http.StatusOK {
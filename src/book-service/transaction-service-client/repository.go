@@ -1,5 +1,7 @@
 package transaction_service_client
 
+import "context"
+
 type Repository interface {
-	CheckChapterBought(userId uint64, chapterId uint64, bookId uint64) error
+	CheckChapterBought(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) error
 }
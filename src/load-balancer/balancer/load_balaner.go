@@ -5,10 +5,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/load-balancer/balancer/strategy"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/load-balancer/balancer/target"
 )
 
+// mapping is the mapping label of the targets in the metrics, the load balancer sends every path to the same targets
+const mapping = "/"
+
 type LoadBalancer struct {
 	healthLock          *sync.Mutex
 	healthcheckInterval time.Duration
@@ -44,7 +48,9 @@ func (lb *LoadBalancer) HealthCheck() {
 	lb.healthLock.Lock()
 	lb.healthyTargets = make([]*target.Target, 0)
 	for _, target := range lb.targets {
-		if GetHealth(lb.client, target.Url) {
+		healthy := GetHealth(lb.client, target.Url)
+		metrics.SetUpstreamHealth(mapping, target.Url.Host, healthy)
+		if healthy {
 			target.Health = 0
 			lb.healthyTargets = append(lb.healthyTargets, target)
 		} else {
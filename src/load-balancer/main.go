@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,45 +10,54 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/load-balancer/balancer"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/load-balancer/balancer/strategy"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/load-balancer/orchestrator"
-	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 )
 
 type ApplicationConfig struct {
-	Port          int   `env:"PORT" envDefault:"8080"`
-	HealthTimeout int64 `env:"HEALTH_TIMEOUT" envDefault:"200"`
+	Port          int    `env:"PORT" envDefault:"8080" validate:"min=1,max=65535"`
+	MetricsPort   int    `env:"METRICS_PORT" envDefault:"9090" validate:"min=1,max=65535"`
+	HealthTimeout int64  `env:"HEALTH_TIMEOUT" envDefault:"200" validate:"min=1"`
+	Image         string `env:"IMAGE" envDefault:"akatranlp/web-service:latest" validate:"required"`
+	Replicas      int    `env:"REPLICAS" envDefault:"1" validate:"min=1"`
+	Network       string `env:"NETWORK" envDefault:"bridge" validate:"required"`
+	Strategy      string `env:"STRATEGY" envDefault:"round-robin"`
+}
+
+// Validate is called by the appconfig-package
+func (config ApplicationConfig) Validate() error {
+	switch config.Strategy {
+	case "round-robin", "ip-hash", "least-connections":
+		return nil
+	}
+	return fmt.Errorf("unknown strategy %q, use round-robin, ip-hash or least-connections", config.Strategy)
 }
 
 func main() {
 	godotenv.Load()
 
 	envConfig := ApplicationConfig{}
-	if err := env.Parse(&envConfig); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	if err := appconfig.Load("load-balancer", &envConfig); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
 
-	image := flag.String("image", "akatranlp/web-service:latest", "")
-	replicas := flag.Int("replicas", 1, "")
-	network := flag.String("network", "bridge", "")
-	strategyStr := flag.String("strategy", "round-robin", "")
-	flag.Parse()
-
 	orc := orchestrator.NewDefaultOrchestrator()
 	defer orc.Close()
 
-	containers := orc.StartContainers(*image, *replicas, *network)
+	containers := orc.StartContainers(envConfig.Image, envConfig.Replicas, envConfig.Network)
 	defer orc.StopContainers(containers)
-	endpoints := orc.GetContainerEndpoints(containers, *network, envConfig.Port)
+	endpoints := orc.GetContainerEndpoints(containers, envConfig.Network, envConfig.Port)
 
 	client := &http.Client{
 		Timeout: time.Duration(envConfig.HealthTimeout) * time.Millisecond,
 	}
 
 	var strategyImpl strategy.Strategy
-	switch *strategyStr {
+	switch envConfig.Strategy {
 	case "round-robin":
 		strategyImpl = strategy.NewRoundRobinStrategy(endpoints)
 	case "ip-hash":
@@ -71,11 +79,19 @@ func main() {
 		Handler: lb,
 	}
 
+	// the metrics are served on their own port, so they aren't reachable through the load-balancer
+	metricsServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", envConfig.MetricsPort),
+		Handler: metrics.Handler(),
+	}
+	go metricsServer.ListenAndServe()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		<-sigChan
+		metricsServer.Shutdown(context.Background())
 		server.Shutdown(context.Background())
 	}()
 
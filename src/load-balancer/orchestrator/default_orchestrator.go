@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/load-balancer/balancer/target"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -53,7 +55,9 @@ func (orc *DefaultOrchestrator) GetContainerEndpoints(containers []string, netwo
 			panic(err)
 		}
 
-		endpoints[i] = target.NewTarget(endpoint, httputil.NewSingleHostReverseProxy(endpoint))
+		proxy := httputil.NewSingleHostReverseProxy(endpoint)
+		proxy.Transport = metrics.RoundTripper(http.DefaultTransport)
+		endpoints[i] = target.NewTarget(endpoint, proxy)
 	}
 
 	return endpoints
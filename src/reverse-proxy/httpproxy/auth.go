@@ -0,0 +1,36 @@
+package httpproxy
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/auth"
+)
+
+// authenticate removes the claim headers sent by the client and verifies the token, if the mapping requires it.
+// A request with a missing or invalid token is answered with the problem details of 401 Unauthorized.
+func (mapping *RouteMapping) authenticate(w http.ResponseWriter, r *http.Request, id string) bool {
+	auth.StripHeaders(r.Header)
+	if mapping.authenticator == nil {
+		return true
+	}
+
+	err := mapping.authenticator.Authenticate(r)
+	if err == nil {
+		return true
+	}
+
+	challenge := `Bearer error="invalid_token"`
+	rejection := shared_types.NewError(shared_types.Unauthenticated, "TOKEN_INVALID", "there was an error while verifying your token")
+	if errors.Is(err, auth.ErrTokenMissing) {
+		challenge = "Bearer"
+		rejection = shared_types.NewError(shared_types.Unauthenticated, "TOKEN_MISSING", "there was no token provided")
+	}
+	slog.InfoContext(r.Context(), "rejected the token of the request", "request_id", id, "host", r.Host, "path", r.URL.Path, "error", err)
+	w.Header().Set("WWW-Authenticate", challenge)
+	problem.Write(w, r, rejection)
+	return false
+}
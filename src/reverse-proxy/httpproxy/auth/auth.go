@@ -0,0 +1,159 @@
+// Package auth verifies the RS256 access tokens of the user-service at the edge and forwards their claims as headers.
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// now is replaced by the tests to expire the tokens
+var now = time.Now
+
+const (
+	Required = "required"
+	Optional = "optional"
+)
+
+// The headers of the verified claims, the upstreams may trust them, because copies sent by clients are removed
+const (
+	UserIDHeader    = "X-User-Id"
+	UserEmailHeader = "X-User-Email"
+)
+
+var claimHeaders = []string{UserIDHeader, UserEmailHeader}
+
+// leeway tolerates the clock skew between the user-service and the proxy
+const leeway = 30 * time.Second
+
+var (
+	ErrTokenMissing = errors.New("there was no token provided")
+	ErrTokenInvalid = errors.New("the token is invalid")
+)
+
+// Config holds the public key of the access tokens, it is given directly or as path like in the user-service
+type Config struct {
+	PublicKey     string `env:"PUBLIC_KEY"`
+	PublicKeyPath string `env:"PUBLIC_KEY_PATH"`
+}
+
+func (config Config) Enabled() bool {
+	return config.PublicKey != "" || config.PublicKeyPath != ""
+}
+
+func (config Config) Validate() error {
+	if config.PublicKey != "" && config.PublicKeyPath != "" {
+		return errors.New("either PUBLIC_KEY or PUBLIC_KEY_PATH can be set")
+	}
+	return nil
+}
+
+// Claims are the claims of the access tokens, which are created by the user-service
+type Claims struct {
+	ID    uint64 `json:"id"`
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// Verifier checks the signature and the lifetime of the access tokens
+type Verifier struct {
+	publicKey *rsa.PublicKey
+}
+
+// NewVerifier reads the public key of the config
+func NewVerifier(config Config) (*Verifier, error) {
+	bytes := []byte(config.PublicKey)
+	if config.PublicKeyPath != "" {
+		var err error
+		bytes, err = os.ReadFile(config.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return &Verifier{publicKey}, nil
+}
+
+// Verify returns the claims of a token, which is signed with RS256 by the key and neither expired nor
+// used before its time. The token version isn't checked, so a revoked token is valid until it expires.
+func (verifier *Verifier) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return verifier.publicKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithLeeway(leeway),
+		jwt.WithTimeFunc(now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: the token doesn't expire", ErrTokenInvalid)
+	}
+	if claims.ID == 0 {
+		return nil, fmt.Errorf("%w: the token has no id claim", ErrTokenInvalid)
+	}
+	return claims, nil
+}
+
+// Authenticator verifies the bearer tokens of the requests of a mapping
+type Authenticator struct {
+	verifier *Verifier
+	required bool
+}
+
+// NewAuthenticator creates the authenticator of a mapping with the verifier. Required rejects requests
+// without a token, optional only the ones with an invalid token.
+func NewAuthenticator(mode string, verifier *Verifier) (*Authenticator, error) {
+	switch mode {
+	case Required, Optional:
+	default:
+		return nil, fmt.Errorf("unknown auth %q", mode)
+	}
+	if verifier == nil {
+		return nil, errors.New("auth needs AUTH_PUBLIC_KEY or AUTH_PUBLIC_KEY_PATH")
+	}
+	return &Authenticator{verifier, mode == Required}, nil
+}
+
+// StripHeaders removes the claim headers, so only the ones set by an authenticator reach the upstream
+func StripHeaders(header http.Header) {
+	for _, name := range claimHeaders {
+		header.Del(name)
+	}
+}
+
+// Authenticate verifies the bearer token of the request and sets the claim headers.
+// It returns ErrTokenMissing or ErrTokenInvalid, if the request must be rejected.
+func (authenticator *Authenticator) Authenticate(r *http.Request) error {
+	authorization := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(authorization, " ")
+	if authorization == "" || !found || !strings.EqualFold(scheme, "Bearer") {
+		if authenticator.required {
+			return ErrTokenMissing
+		}
+		return nil
+	}
+
+	claims, err := authenticator.verifier.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return err
+	}
+	r.Header.Set(UserIDHeader, strconv.FormatUint(claims.ID, 10))
+	if claims.Email != "" {
+		r.Header.Set(UserEmailHeader, claims.Email)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func setNow(t *testing.T) *time.Time {
+	current := time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+	return &current
+}
+
+func generateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// userClaims are the claims of the access tokens of the user-service, which expire after the lifetime
+func userClaims(issued time.Time, lifetime time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"id":            7,
+		"email":         "test@example.com",
+		"token_version": 0,
+		"iat":           issued.Unix(),
+		"nbf":           issued.Unix(),
+		"exp":           issued.Add(lifetime).Unix(),
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("should reject both a key and a path", func(t *testing.T) {
+		assert.Error(t, Config{PublicKey: "key", PublicKeyPath: "key.pem"}.Validate())
+		assert.NoError(t, Config{PublicKeyPath: "key.pem"}.Validate())
+	})
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, publicKey := generateKey(t)
+
+	t.Run("should read the key from a file", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "access-public.pem")
+		if err := os.WriteFile(path, []byte(publicKey), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// when
+		verifier, err := NewVerifier(Config{PublicKeyPath: path})
+
+		// then
+		assert.NoError(t, err)
+		assert.NotNil(t, verifier)
+	})
+
+	t.Run("should reject an invalid key", func(t *testing.T) {
+		// when
+		_, err := NewVerifier(Config{PublicKey: "not a key"})
+
+		// then
+		assert.ErrorContains(t, err, "invalid public key")
+	})
+}
+
+func TestVerifier(t *testing.T) {
+	privateKey, publicKey := generateKey(t)
+	otherKey, _ := generateKey(t)
+	verifier, err := NewVerifier(Config{PublicKey: publicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should return the claims of a valid token", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		token := signToken(t, jwt.SigningMethodRS256, privateKey, userClaims(*current, 15*time.Minute))
+
+		// when
+		claims, err := verifier.Verify(token)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(7), claims.ID)
+		assert.Equal(t, "test@example.com", claims.Email)
+	})
+
+	t.Run("should tolerate a small clock skew", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		token := signToken(t, jwt.SigningMethodRS256, privateKey, userClaims(current.Add(10*time.Second), time.Minute))
+
+		// when
+		_, err := verifier.Verify(token)
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name  string
+		token func(current time.Time) string
+	}{
+		{"should reject an expired token", func(current time.Time) string {
+			return signToken(t, jwt.SigningMethodRS256, privateKey, userClaims(current.Add(-time.Hour), 15*time.Minute))
+		}},
+		{"should reject a token of another key", func(current time.Time) string {
+			return signToken(t, jwt.SigningMethodRS256, otherKey, userClaims(current, 15*time.Minute))
+		}},
+		{"should reject another algorithm", func(current time.Time) string {
+			return signToken(t, jwt.SigningMethodHS256, []byte(publicKey), userClaims(current, 15*time.Minute))
+		}},
+		{"should reject a token without expiration", func(current time.Time) string {
+			claims := userClaims(current, 0)
+			delete(claims, "exp")
+			return signToken(t, jwt.SigningMethodRS256, privateKey, claims)
+		}},
+		{"should reject a token without id", func(current time.Time) string {
+			claims := userClaims(current, 15*time.Minute)
+			delete(claims, "id")
+			return signToken(t, jwt.SigningMethodRS256, privateKey, claims)
+		}},
+		{"should reject a malformed token", func(time.Time) string {
+			return "not.a.token"
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// given
+			current := setNow(t)
+
+			// when
+			_, err := verifier.Verify(test.token(*current))
+
+			// then
+			assert.ErrorIs(t, err, ErrTokenInvalid)
+		})
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	privateKey, publicKey := generateKey(t)
+	verifier, err := NewVerifier(Config{PublicKey: publicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should need a verifier", func(t *testing.T) {
+		// when
+		_, err := NewAuthenticator(Required, nil)
+
+		// then
+		assert.ErrorContains(t, err, "AUTH_PUBLIC_KEY")
+	})
+
+	t.Run("should reject an unknown mode", func(t *testing.T) {
+		// when
+		_, err := NewAuthenticator("sometimes", verifier)
+
+		// then
+		assert.ErrorContains(t, err, `unknown auth "sometimes"`)
+	})
+
+	t.Run("should set the headers of the claims", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		authenticator, _ := NewAuthenticator(Required, verifier)
+		r := httptest.NewRequest("GET", "/api/v1/books", nil)
+		r.Header.Set("Authorization", "Bearer "+signToken(t, jwt.SigningMethodRS256, privateKey, userClaims(*current, time.Minute)))
+
+		// when
+		err := authenticator.Authenticate(r)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "7", r.Header.Get(UserIDHeader))
+		assert.Equal(t, "test@example.com", r.Header.Get(UserEmailHeader))
+	})
+
+	t.Run("should require a bearer token", func(t *testing.T) {
+		// given
+		authenticator, _ := NewAuthenticator(Required, verifier)
+		r := httptest.NewRequest("GET", "/api/v1/books", nil)
+		r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+		// when
+		err := authenticator.Authenticate(r)
+
+		// then
+		assert.ErrorIs(t, err, ErrTokenMissing)
+	})
+
+	t.Run("should let requests without a token through, if it is optional", func(t *testing.T) {
+		// given
+		authenticator, _ := NewAuthenticator(Optional, verifier)
+		r := httptest.NewRequest("GET", "/api/v1/books", nil)
+
+		// when
+		err := authenticator.Authenticate(r)
+
+		// then
+		assert.NoError(t, err)
+		assert.Empty(t, r.Header.Get(UserIDHeader))
+	})
+
+	t.Run("should reject an invalid token, even if it is optional", func(t *testing.T) {
+		// given
+		authenticator, _ := NewAuthenticator(Optional, verifier)
+		r := httptest.NewRequest("GET", "/api/v1/books", nil)
+		r.Header.Set("Authorization", "Bearer invalid-token")
+
+		// when
+		err := authenticator.Authenticate(r)
+
+		// then
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+}
+
+func TestStripHeaders(t *testing.T) {
+	t.Run("should remove the claim headers", func(t *testing.T) {
+		// given
+		r := httptest.NewRequest("GET", "/api/v1/books", nil)
+		r.Header.Set(UserIDHeader, "1")
+		r.Header.Set(UserEmailHeader, "admin@example.com")
+		r.Header.Set("Authorization", "Bearer token")
+
+		// when
+		StripHeaders(r.Header)
+
+		// then
+		assert.Empty(t, r.Header.Get(UserIDHeader))
+		assert.Empty(t, r.Header.Get(UserEmailHeader))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+	})
+}
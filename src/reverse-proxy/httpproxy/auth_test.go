@@ -0,0 +1,177 @@
+package httpproxy
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/auth"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/ratelimit"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newVerifier creates a key for the verifier of the mappings and returns its private key
+func newVerifier(t *testing.T) (*auth.Verifier, *rsa.PrivateKey) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := auth.NewVerifier(auth.Config{PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verifier, privateKey
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+	verifier, privateKey := newVerifier(t)
+
+	newAuthProxy := func(t *testing.T, config MappingConfig) *HTTPUtilProxy {
+		proxy := NewHTTPUtilProxy(roundTripper)
+		config.Host, config.Path = "*", "/the/route"
+		mapping, err := NewRouteMapping(config, Dependencies{Verifier: verifier})
+		if err != nil {
+			t.Fatal(err)
+		}
+		proxy.Append(mapping)
+		return proxy
+	}
+
+	serve := func(proxy http.Handler, authorization string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+		r.Header.Set(auth.UserIDHeader, "1")
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		proxy.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("should forward the claims of a valid token", func(t *testing.T) {
+		// given
+		proxy := newAuthProxy(t, MappingConfig{Hosts: []string{"http://book:8080"}, Auth: auth.Required})
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"id":    7,
+			"email": "test@example.com",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+		}).SignedString(privateKey)
+
+		var forwarded http.Header
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			forwarded = r.Header
+			return respondWith(http.StatusOK), nil
+		})
+
+		// when
+		w := serve(proxy, "Bearer "+token)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "7", forwarded.Get(auth.UserIDHeader))
+		assert.Equal(t, "test@example.com", forwarded.Get(auth.UserEmailHeader))
+	})
+
+	t.Run("should answer a missing or invalid token with 401 UNAUTHORIZED", func(t *testing.T) {
+		// given
+		proxy := newAuthProxy(t, MappingConfig{Hosts: []string{"http://book:8080"}, Auth: auth.Required})
+
+		// when
+		missing := serve(proxy, "")
+		invalid := serve(proxy, "Bearer invalid-token")
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, missing.Code)
+		assert.Equal(t, "Bearer", missing.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, problem.ContentType, missing.Header().Get("Content-Type"))
+		assert.Contains(t, missing.Body.String(), `"reason":"TOKEN_MISSING"`)
+		assert.Equal(t, http.StatusUnauthorized, invalid.Code)
+		assert.Equal(t, `Bearer error="invalid_token"`, invalid.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, problem.ContentType, invalid.Header().Get("Content-Type"))
+		assert.Contains(t, invalid.Body.String(), `"reason":"TOKEN_INVALID"`)
+	})
+
+	t.Run("should strip the claim headers of the client on every mapping", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{Hosts: []string{"http://book:8080"}})
+
+		var forwarded http.Header
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			forwarded = r.Header
+			return respondWith(http.StatusOK), nil
+		})
+
+		// when
+		serve(proxy, "")
+
+		// then
+		assert.Empty(t, forwarded.Get(auth.UserIDHeader))
+	})
+
+	t.Run("should limit the rate of the verified user", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		mapping, err := NewRouteMapping(MappingConfig{
+			Host: "*", Path: "/the/route", Hosts: []string{"http://book:8080"}, Auth: auth.Required,
+			RateLimit: &ratelimit.Config{Requests: 1, Period: time.Minute, Key: ratelimit.KeyUser},
+		}, Dependencies{Verifier: verifier, RateLimitStore: ratelimit.NewMemoryStore()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		proxy.Append(mapping)
+		sign := func(id int) string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+				"id":  id,
+				"exp": time.Now().Add(time.Minute).Unix(),
+			}).SignedString(privateKey)
+			return "Bearer " + token
+		}
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusOK), nil).Times(2)
+
+		// when
+		first := serve(proxy, sign(7))
+		limited := serve(proxy, sign(7))
+		other := serve(proxy, sign(8))
+		invalid := serve(proxy, "Bearer invalid-token")
+
+		// then
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+		assert.Equal(t, http.StatusOK, other.Code)
+		assert.Equal(t, http.StatusUnauthorized, invalid.Code)
+	})
+
+	t.Run("should reject the user key of a rate limit without auth", func(t *testing.T) {
+		// when
+		_, err := NewRouteMapping(MappingConfig{
+			Path: "/a", Hosts: []string{"http://a:8080"},
+			RateLimit: &ratelimit.Config{Requests: 1, Key: ratelimit.KeyUser},
+		}, Dependencies{RateLimitStore: ratelimit.NewMemoryStore()})
+
+		// then
+		assert.ErrorContains(t, err, "the user key of rateLimit needs auth")
+	})
+
+	t.Run("should reject auth without a public key", func(t *testing.T) {
+		// when
+		_, err := NewRouteMapping(MappingConfig{Path: "/a", Hosts: []string{"http://a:8080"}, Auth: auth.Required}, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "auth needs AUTH_PUBLIC_KEY")
+	})
+}
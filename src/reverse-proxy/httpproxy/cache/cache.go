@@ -0,0 +1,613 @@
+// Package cache stores the responses of the upstreams like a shared HTTP cache (RFC 9111).
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+)
+
+// now is replaced by the tests to age the stored responses
+var now = time.Now
+
+// cacheStatus is the name of the proxy in the Cache-Status header (RFC 9211)
+const cacheStatus = "reverse-proxy"
+
+type Config struct {
+	// DefaultTTL is the freshness of responses without Cache-Control or Expires,
+	// by default they are only stored, if they can be revalidated
+	DefaultTTL time.Duration `yaml:"defaultTTL,omitempty" json:"defaultTTL,omitempty"`
+	// MaxBodySize is the largest body, which is stored, default 1MiB
+	MaxBodySize int64 `yaml:"maxBodySize,omitempty" json:"maxBodySize,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for the unset values
+func (config Config) WithDefaults() Config {
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = 1 << 20
+	}
+	return config
+}
+
+func (config Config) Validate() error {
+	if config.DefaultTTL < 0 || config.MaxBodySize < 0 {
+		return errors.New("the values of cache must not be negative")
+	}
+	return nil
+}
+
+var (
+	// errBodyLength ends a body, which is longer than announced
+	errBodyLength = errors.New("the body of the upstream is longer than its Content-Length")
+	errBodyClosed = errors.New("read on a closed body")
+)
+
+// RoundTripFunc sends a request to the upstreams
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// entry is a stored response
+type entry struct {
+	path   string
+	status int
+	header http.Header
+	body   []byte
+	// selecting are the values of the request headers named by Vary
+	selecting    http.Header
+	requestTime  time.Time
+	responseTime time.Time
+	// lifetime is the freshness lifetime, the response must be revalidated after it
+	lifetime   time.Duration
+	directives directives
+}
+
+// newEntry creates the entry of the response without its body, it must be created before the header
+// of the response is handed on
+func newEntry(r *http.Request, res *http.Response, lifetime time.Duration, d directives, requestTime time.Time, responseTime time.Time) *entry {
+	selecting := http.Header{}
+	for _, name := range varyNames(res.Header) {
+		selecting[name] = r.Header.Values(name)
+	}
+	return &entry{
+		path:         clientURLOf(r).path,
+		status:       res.StatusCode,
+		header:       res.Header.Clone(),
+		selecting:    selecting,
+		requestTime:  requestTime,
+		responseTime: responseTime,
+		lifetime:     lifetime,
+		directives:   d,
+	}
+}
+
+// withBody returns the entry with the body
+func (e *entry) withBody(body []byte) *entry {
+	complete := *e
+	complete.header = e.header.Clone()
+	complete.header.Set("Content-Length", strconv.Itoa(len(body)))
+	complete.body = body
+	return &complete
+}
+
+func varyNames(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+func (e *entry) size() int64 {
+	size := int64(len(e.path) + len(e.body))
+	for name, values := range e.header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	return size
+}
+
+// selects reports if the request has the same values for the headers named by Vary (RFC 9111, section 4.1)
+func (e *entry) selects(r *http.Request) bool {
+	for name, values := range e.selecting {
+		if normalize(r.Header.Values(name)) != normalize(values) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *entry) sameVariant(other *entry) bool {
+	if len(e.selecting) != len(other.selecting) {
+		return false
+	}
+	for name, values := range e.selecting {
+		if normalize(other.selecting.Values(name)) != normalize(values) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalize(values []string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (e *entry) age(current time.Time) time.Duration {
+	return initialAge(e.header, e.requestTime, e.responseTime) + current.Sub(e.responseTime)
+}
+
+// revalidated returns the entry with the headers of the 304 response of a revalidation (RFC 9111, section 4.3.4)
+func (e *entry) revalidated(res *http.Response, requestTime time.Time, responseTime time.Time, defaultTTL time.Duration) *entry {
+	updated := *e
+	updated.header = e.header.Clone()
+	for name, values := range res.Header {
+		if name == "Content-Length" {
+			continue
+		}
+		updated.header[name] = values
+	}
+	updated.directives = parseCacheControl(updated.header)
+	updated.lifetime = freshnessLifetime(updated.header, updated.directives, defaultTTL)
+	updated.requestTime, updated.responseTime = requestTime, responseTime
+	return &updated
+}
+
+// response creates a response of the entry for the request, it is 304 if the conditions of the request match
+func (e *entry) response(r *http.Request, status string) *http.Response {
+	header := e.header.Clone()
+	header.Set("Age", strconv.Itoa(int(e.age(now()).Seconds())))
+	header.Set("Cache-Status", status)
+	res := &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		ContentLength: int64(len(e.body)),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		Request:       r,
+	}
+	if r.Method == http.MethodHead {
+		res.Body = http.NoBody
+	}
+	return conditional(r, res)
+}
+
+// conditional replaces a response, which fulfills the conditions of the request, with 304 Not Modified
+func conditional(r *http.Request, res *http.Response) *http.Response {
+	if res.StatusCode != http.StatusOK || !notModified(r, res.Header) {
+		return res
+	}
+	res.Body.Close()
+	res.StatusCode = http.StatusNotModified
+	res.Status = "304 " + http.StatusText(http.StatusNotModified)
+	res.Header.Del("Content-Length")
+	res.ContentLength = 0
+	res.Body = http.NoBody
+	return res
+}
+
+// flight is a request to the upstreams, which is shared by the concurrent requests of the same URL
+type flight struct {
+	done  chan struct{}
+	entry *entry
+}
+
+// Cache serves the GET and HEAD requests of a mapping from the store, as long as the responses are fresh.
+// The cache is shared, so private responses and those of authorized requests aren't stored, unless
+// they allow it explicitly.
+type Cache struct {
+	config Config
+	store  *Store
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// NewCache creates the cache of a mapping, which keeps its responses in the store
+func NewCache(config Config, store *Store) *Cache {
+	return &Cache{config: config.WithDefaults(), store: store, flights: map[string]*flight{}}
+}
+
+// clientURL is the URL requested by the client, before the proxy rewrote it for the upstream
+type clientURL struct {
+	key  string
+	path string
+}
+
+type clientURLKey struct{}
+
+// WithClientURL remembers the URL of the request, so its response is stored and purged
+// by the path of the client and not by the rewritten path of the upstream
+func WithClientURL(r *http.Request) *http.Request {
+	url := clientURL{key: r.Host + r.URL.RequestURI(), path: r.URL.Path}
+	return r.WithContext(context.WithValue(r.Context(), clientURLKey{}, url))
+}
+
+func clientURLOf(r *http.Request) clientURL {
+	if url, ok := r.Context().Value(clientURLKey{}).(clientURL); ok {
+		return url
+	}
+	return clientURL{key: r.Host + r.URL.RequestURI(), path: r.URL.Path}
+}
+
+func key(r *http.Request) string {
+	return clientURLOf(r).key
+}
+
+// RoundTrip serves the request from the store or sends it with next and stores the response
+func (cache *Cache) RoundTrip(r *http.Request, next RoundTripFunc) (*http.Response, error) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		res, err := next(r)
+		// unsafe methods invalidate the stored responses of the URL (RFC 9111, section 4.4)
+		if err == nil && isUnsafe(r.Method) && res.StatusCode < 400 {
+			cache.store.remove(key(r))
+		}
+		return res, err
+	}
+
+	requestDirectives := parseCacheControl(r.Header)
+	if requestDirectives.has("no-store") || r.Header.Get("Upgrade") != "" {
+		return next(r)
+	}
+
+	k := key(r)
+	e := cache.store.get(k, r)
+	if e == nil {
+		if requestDirectives.has("only-if-cached") {
+			return gatewayTimeout(r), nil
+		}
+		return cache.fetch(r, k, nil, next)
+	}
+
+	age := e.age(now())
+	switch {
+	case cache.fresh(r, requestDirectives, e, age):
+		return e.response(r, cacheStatus+"; hit"), nil
+	case cache.staleAllowed(requestDirectives, e, age):
+		return e.response(r, cacheStatus+"; hit; fwd=stale"), nil
+	case cache.staleWhileRevalidate(r, requestDirectives, e, age):
+		cache.revalidateInBackground(r, k, e, next)
+		return e.response(r, cacheStatus+"; hit; fwd=stale; fwd-status=background"), nil
+	case requestDirectives.has("only-if-cached"):
+		return gatewayTimeout(r), nil
+	}
+	return cache.fetch(r, k, e, next)
+}
+
+func isUnsafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	return true
+}
+
+func gatewayTimeout(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "504 " + http.StatusText(http.StatusGatewayTimeout),
+		StatusCode: http.StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Cache-Status": {cacheStatus + "; fwd=miss; detail=only-if-cached"}},
+		Body:       http.NoBody,
+		Request:    r,
+	}
+}
+
+// fresh reports if the entry may be served without revalidation (RFC 9111, section 4.2)
+func (cache *Cache) fresh(r *http.Request, requestDirectives directives, e *entry, age time.Duration) bool {
+	if e.directives.has("no-cache") || requestNoCache(r.Header, requestDirectives) || age >= e.lifetime {
+		return false
+	}
+	if maxAge, ok := requestDirectives.seconds("max-age"); ok && age > maxAge {
+		return false
+	}
+	if minFresh, ok := requestDirectives.seconds("min-fresh"); ok && e.lifetime-age < minFresh {
+		return false
+	}
+	return true
+}
+
+// mustRevalidate reports if the response forbids to serve it stale (RFC 9111, sections 5.2.2.2 and 5.2.2.8)
+func mustRevalidate(e *entry) bool {
+	return e.directives.has("must-revalidate") || e.directives.has("proxy-revalidate") ||
+		e.directives.has("s-maxage") || e.directives.has("no-cache")
+}
+
+// staleAllowed reports if the client accepts the stale entry with max-stale
+func (cache *Cache) staleAllowed(requestDirectives directives, e *entry, age time.Duration) bool {
+	if !requestDirectives.has("max-stale") || mustRevalidate(e) || requestDirectives.has("no-cache") {
+		return false
+	}
+	if maxStale, ok := requestDirectives.seconds("max-stale"); ok {
+		return age-e.lifetime <= maxStale
+	}
+	// max-stale without a value accepts any staleness
+	return true
+}
+
+// staleWhileRevalidate reports if the stale entry may be served, while it is revalidated (RFC 5861)
+func (cache *Cache) staleWhileRevalidate(r *http.Request, requestDirectives directives, e *entry, age time.Duration) bool {
+	window, ok := e.directives.seconds("stale-while-revalidate")
+	if !ok || e.directives.has("no-cache") || requestNoCache(r.Header, requestDirectives) {
+		return false
+	}
+	return age-e.lifetime <= window
+}
+
+// revalidateInBackground revalidates the entry, unless a request for the URL is already sent
+func (cache *Cache) revalidateInBackground(r *http.Request, k string, e *entry, next RoundTripFunc) {
+	cache.mu.Lock()
+	if _, ok := cache.flights[k]; ok {
+		cache.mu.Unlock()
+		return
+	}
+	f := &flight{done: make(chan struct{})}
+	cache.flights[k] = f
+	cache.mu.Unlock()
+
+	background := r.Clone(context.WithoutCancel(r.Context()))
+	go func() {
+		res, err := cache.send(background, e, next, f)
+		if err == nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+	}()
+}
+
+// fetch sends the request or waits for the concurrent request of the same URL and uses its response,
+// if it was stored and matches the request. Requests, which can't use a stored response, neither wait
+// nor make the others wait for them.
+func (cache *Cache) fetch(r *http.Request, k string, stale *entry, next RoundTripFunc) (*http.Response, error) {
+	if !coalescable(r) {
+		return cache.send(r, stale, next, nil)
+	}
+
+	cache.mu.Lock()
+	if f, ok := cache.flights[k]; ok {
+		cache.mu.Unlock()
+		select {
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		case <-f.done:
+		}
+		if f.entry != nil && f.entry.selects(r) {
+			metrics.ObserveSharedCall(true)
+			return f.entry.response(r, cacheStatus+"; hit; detail=coalesced"), nil
+		}
+		return cache.send(r, stale, next, nil)
+	}
+	f := &flight{done: make(chan struct{})}
+	cache.flights[k] = f
+	cache.mu.Unlock()
+
+	metrics.ObserveSharedCall(false)
+	return cache.send(r, stale, next, f)
+}
+
+// coalescable reports if the request may wait for the response of a concurrent request. Responses to
+// HEAD requests aren't stored and those to requests with Authorization rarely are, so they are sent
+// on their own, like the requests, which must not be answered from the cache.
+func coalescable(r *http.Request) bool {
+	return r.Method == http.MethodGet && r.Header.Get("Authorization") == "" &&
+		!requestNoCache(r.Header, parseCacheControl(r.Header))
+}
+
+// land releases the requests waiting for the flight with the stored entry or nil, f may be nil
+func (cache *Cache) land(k string, f *flight, stored *entry) {
+	if f == nil {
+		return
+	}
+	f.entry = stored
+	cache.mu.Lock()
+	delete(cache.flights, k)
+	cache.mu.Unlock()
+	close(f.done)
+}
+
+// send forwards the request, conditional with the validators of the stale entry, and stores the response.
+// The conditions of the client are evaluated by the proxy, so a full response can be stored.
+// If the request is the flight of its URL, the flight lands as soon as the response was stored or
+// turned out not to be storable.
+func (cache *Cache) send(r *http.Request, stale *entry, next RoundTripFunc, f *flight) (*http.Response, error) {
+	k := key(r)
+	stored := func(e *entry) { cache.land(k, f, e) }
+
+	out := r.Clone(r.Context())
+	out.Header.Del("If-None-Match")
+	out.Header.Del("If-Modified-Since")
+	if stale != nil {
+		if etag := stale.header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := stale.header.Get("Last-Modified"); lastModified != "" {
+			out.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	requestTime := now()
+	res, err := next(out)
+	if err != nil {
+		stored(nil)
+		return nil, err
+	}
+	responseTime := now()
+
+	if stale != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		updated := stale.revalidated(res, requestTime, responseTime, cache.config.DefaultTTL)
+		cache.store.put(k, updated)
+		stored(updated)
+		return updated.response(r, cacheStatus+"; fwd=stale; fwd-status=304"), nil
+	}
+
+	d := parseCacheControl(res.Header)
+	lifetime, storable := cache.storable(r, res, d)
+	if !storable {
+		stored(nil)
+		res.Header.Set("Cache-Status", cacheStatus+"; fwd=miss")
+		return conditional(r, res), nil
+	}
+
+	// the body may be stored in the background, after the client changed the header of the response
+	pending := newEntry(r, res, lifetime, d, requestTime, responseTime)
+	store := func(body []byte) *entry {
+		e := pending.withBody(body)
+		cache.store.put(k, e)
+		return e
+	}
+
+	if res.StatusCode == http.StatusOK && notModified(r, res.Header) {
+		// the client doesn't get the body, so it is read at once
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			stored(nil)
+			return nil, err
+		}
+		e := store(body)
+		stored(e)
+		return e.response(r, cacheStatus+"; fwd=miss; stored"), nil
+	}
+
+	// the body is sent to the client, while it is read, and stored, when the upstream sent it completely
+	res.Body = newBufferedBody(res.Body, res.ContentLength, func(body []byte, complete bool) {
+		if !complete {
+			stored(nil)
+			return
+		}
+		stored(store(body))
+	})
+	res.Header.Set("Cache-Status", cacheStatus+"; fwd=miss; stored")
+	return res, nil
+}
+
+// storable reports if the response may be stored by a shared cache (RFC 9111, section 3) and returns
+// its freshness lifetime. Responses, which set cookies, have trailers or are streamed, aren't stored either.
+func (cache *Cache) storable(r *http.Request, res *http.Response, d directives) (time.Duration, bool) {
+	if r.Method != http.MethodGet || !heuristicallyCacheable[res.StatusCode] {
+		return 0, false
+	}
+	if d.has("no-store") || d.has("private") || res.Header.Get("Set-Cookie") != "" || len(res.Trailer) > 0 {
+		return 0, false
+	}
+	// streams and bodies of an unknown length are never buffered, so they reach the client at once
+	if res.ContentLength < 0 || res.ContentLength > cache.config.MaxBodySize || isEventStream(res.Header) {
+		return 0, false
+	}
+	for _, name := range varyNames(res.Header) {
+		if name == "*" {
+			return 0, false
+		}
+	}
+	if r.Header.Get("Authorization") != "" && !d.has("public") && !d.has("s-maxage") && !d.has("must-revalidate") {
+		return 0, false
+	}
+
+	// a response, which is neither fresh nor can be revalidated, is useless
+	lifetime := freshnessLifetime(res.Header, d, cache.config.DefaultTTL)
+	canRevalidate := res.Header.Get("ETag") != "" || res.Header.Get("Last-Modified") != ""
+	return lifetime, lifetime > 0 || canRevalidate
+}
+
+func isEventStream(header http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
+
+// bufferedBody reads the body of the upstream in the background, while the client reads the copy at its
+// own pace, so a slow client neither holds the connection to the upstream nor the requests waiting for
+// the response. done is called with the copy, when the upstream body ended. It is only complete, if it
+// has the announced length, which is no larger than MaxBodySize.
+type bufferedBody struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	copied []byte
+	read   int
+	// err ends the body, after the client read the copy
+	err    error
+	closed bool
+}
+
+func newBufferedBody(body io.ReadCloser, length int64, done func(body []byte, complete bool)) *bufferedBody {
+	buffered := &bufferedBody{copied: make([]byte, 0, length)}
+	buffered.cond = sync.NewCond(&buffered.mu)
+	go buffered.fill(body, length, done)
+	return buffered
+}
+
+func (buffered *bufferedBody) fill(body io.ReadCloser, length int64, done func(body []byte, complete bool)) {
+	defer body.Close()
+	chunk := make([]byte, 32<<10)
+	for {
+		n, err := body.Read(chunk)
+
+		buffered.mu.Lock()
+		buffered.copied = append(buffered.copied, chunk[:n]...)
+		switch {
+		case int64(len(buffered.copied)) > length:
+			err = errBodyLength
+		case err == io.EOF && int64(len(buffered.copied)) < length:
+			err = io.ErrUnexpectedEOF
+		}
+		buffered.err = err
+		copied := buffered.copied
+		buffered.cond.Broadcast()
+		buffered.mu.Unlock()
+
+		if err != nil {
+			done(copied, err == io.EOF)
+			return
+		}
+	}
+}
+
+func (buffered *bufferedBody) Read(p []byte) (int, error) {
+	buffered.mu.Lock()
+	defer buffered.mu.Unlock()
+	for !buffered.closed && buffered.read == len(buffered.copied) && buffered.err == nil {
+		buffered.cond.Wait()
+	}
+	switch {
+	case buffered.closed:
+		return 0, errBodyClosed
+	case buffered.read < len(buffered.copied):
+		n := copy(p, buffered.copied[buffered.read:])
+		buffered.read += n
+		return n, nil
+	}
+	return 0, buffered.err
+}
+
+// Close stops the client from reading, the upstream body is still read and stored
+func (buffered *bufferedBody) Close() error {
+	buffered.mu.Lock()
+	defer buffered.mu.Unlock()
+	buffered.closed = true
+	buffered.cond.Broadcast()
+	return nil
+}
@@ -0,0 +1,540 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setNow moves the clock of the package to the returned time
+func setNow(t *testing.T) *time.Time {
+	current := time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+	return &current
+}
+
+// newTestCache creates a cache with its own store
+func newTestCache(config Config) *Cache {
+	return NewCache(config, NewStore(StoreConfig{MaxEntries: 100, MaxBytes: 1 << 20}))
+}
+
+// upstream counts the requests and answers them with the header and body
+type upstream struct {
+	calls    atomic.Int32
+	requests chan *http.Request
+	respond  func(r *http.Request) (http.Header, int, string)
+}
+
+func newUpstream(header http.Header, body string) *upstream {
+	return &upstream{
+		requests: make(chan *http.Request, 10),
+		respond: func(*http.Request) (http.Header, int, string) {
+			return header.Clone(), http.StatusOK, body
+		},
+	}
+}
+
+func (u *upstream) send(r *http.Request) (*http.Response, error) {
+	u.calls.Add(1)
+	u.requests <- r
+	header, status, body := u.respond(r)
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body)), ContentLength: int64(len(body))}, nil
+}
+
+func get(t *testing.T, cache *Cache, u *upstream, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	r := httptest.NewRequest("GET", "http://example.com/api/v1/books?page=1", nil)
+	for name, values := range header {
+		r.Header[name] = values
+	}
+	res, err := cache.RoundTrip(r, u.send)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	return res, string(body)
+}
+
+func TestCache(t *testing.T) {
+	t.Run("should serve fresh responses from the store", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}}, "books")
+
+		// when
+		_, first := get(t, cache, u, nil)
+		*current = current.Add(10 * time.Second)
+		res, second := get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, int32(1), u.calls.Load())
+		assert.Equal(t, "books", first)
+		assert.Equal(t, "books", second)
+		assert.Equal(t, "10", res.Header.Get("Age"))
+		assert.Equal(t, "reverse-proxy; hit", res.Header.Get("Cache-Status"))
+	})
+
+	t.Run("should not store responses, which a shared cache mustn't store", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			config  Config
+			request http.Header
+			header  http.Header
+		}{
+			{"no-store", Config{}, nil, http.Header{"Cache-Control": {"no-store, max-age=60"}}},
+			{"private", Config{}, nil, http.Header{"Cache-Control": {"private, max-age=60"}}},
+			{"cookies", Config{}, nil, http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"session=1"}}},
+			{"vary on everything", Config{}, nil, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}}},
+			{"authorized requests", Config{}, http.Header{"Authorization": {"Bearer token"}}, http.Header{"Cache-Control": {"max-age=60"}}},
+			{"no freshness", Config{}, nil, http.Header{}},
+			{"requests with no-store", Config{}, http.Header{"Cache-Control": {"no-store"}}, http.Header{"Cache-Control": {"max-age=60"}}},
+			{"event streams", Config{DefaultTTL: time.Minute}, nil, http.Header{"Content-Type": {"text/event-stream; charset=utf-8"}}},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				// given
+				setNow(t)
+				cache := newTestCache(test.config)
+				u := newUpstream(test.header, "books")
+
+				// when
+				get(t, cache, u, test.request)
+				get(t, cache, u, test.request)
+
+				// then
+				assert.Equal(t, int32(2), u.calls.Load())
+			})
+		}
+	})
+
+	t.Run("should store authorized requests, if the response is public", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"public, max-age=60"}}, "books")
+
+		// when
+		get(t, cache, u, http.Header{"Authorization": {"Bearer token"}})
+		get(t, cache, u, http.Header{"Authorization": {"Bearer other"}})
+
+		// then
+		assert.Equal(t, int32(1), u.calls.Load())
+	})
+
+	t.Run("should use the default ttl without freshness", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		cache := newTestCache(Config{DefaultTTL: time.Minute})
+		u := newUpstream(http.Header{}, "books")
+
+		// when
+		get(t, cache, u, nil)
+		*current = current.Add(30 * time.Second)
+		get(t, cache, u, nil)
+		*current = current.Add(time.Minute)
+		get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should revalidate stale responses with their etag", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(nil, "")
+		u.respond = func(r *http.Request) (http.Header, int, string) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				return http.Header{"Cache-Control": {"max-age=120"}, "Etag": {`"v1"`}}, http.StatusNotModified, ""
+			}
+			return http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}}, http.StatusOK, "books"
+		}
+		get(t, cache, u, nil)
+		<-u.requests
+
+		// when
+		*current = current.Add(90 * time.Second)
+		res, body := get(t, cache, u, nil)
+		revalidation := <-u.requests
+		*current = current.Add(90 * time.Second)
+		get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, `"v1"`, revalidation.Header.Get("If-None-Match"))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "books", body)
+		assert.Equal(t, "max-age=120", res.Header.Get("Cache-Control"))
+		assert.Equal(t, "reverse-proxy; fwd=stale; fwd-status=304", res.Header.Get("Cache-Status"))
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should answer the conditions of the client", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}}, "books")
+
+		// when
+		missed, _ := get(t, cache, u, http.Header{"If-None-Match": {`"v1"`}})
+		sent := <-u.requests
+		hit, body := get(t, cache, u, http.Header{"If-None-Match": {`W/"v1"`}})
+
+		// then
+		assert.Empty(t, sent.Header.Get("If-None-Match"))
+		assert.Equal(t, http.StatusNotModified, missed.StatusCode)
+		assert.Equal(t, http.StatusNotModified, hit.StatusCode)
+		assert.Empty(t, body)
+		assert.Equal(t, `"v1"`, hit.Header.Get("Etag"))
+	})
+
+	t.Run("should keep a response per variant", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(nil, "")
+		u.respond = func(r *http.Request) (http.Header, int, string) {
+			return http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"Accept-Language"}}, http.StatusOK, r.Header.Get("Accept-Language")
+		}
+
+		// when
+		_, german := get(t, cache, u, http.Header{"Accept-Language": {"de"}})
+		_, english := get(t, cache, u, http.Header{"Accept-Language": {"en"}})
+		_, cached := get(t, cache, u, http.Header{"Accept-Language": {"de"}})
+
+		// then
+		assert.Equal(t, "de", german)
+		assert.Equal(t, "en", english)
+		assert.Equal(t, "de", cached)
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should serve stale responses, while they are revalidated", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		cache := newTestCache(Config{})
+		var version atomic.Int32
+		u := newUpstream(nil, "")
+		u.respond = func(r *http.Request) (http.Header, int, string) {
+			return http.Header{"Cache-Control": {"max-age=60, stale-while-revalidate=30"}}, http.StatusOK, "v" + string('0'+rune(version.Add(1)))
+		}
+		get(t, cache, u, nil)
+
+		// when
+		*current = current.Add(70 * time.Second)
+		res, stale := get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, "v1", stale)
+		assert.Contains(t, res.Header.Get("Cache-Status"), "fwd=stale")
+		assert.Eventually(t, func() bool {
+			_, body := get(t, cache, u, nil)
+			return body == "v2"
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should not serve stale responses after the window", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60, stale-while-revalidate=30"}}, "books")
+		get(t, cache, u, nil)
+
+		// when
+		*current = current.Add(100 * time.Second)
+		res, _ := get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, "reverse-proxy; fwd=miss; stored", res.Header.Get("Cache-Status"))
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should coalesce concurrent misses", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		release := make(chan struct{})
+		u := newUpstream(nil, "")
+		u.respond = func(*http.Request) (http.Header, int, string) {
+			<-release
+			return http.Header{"Cache-Control": {"max-age=60"}}, http.StatusOK, "books"
+		}
+
+		// when
+		var wg sync.WaitGroup
+		bodies := make([]string, 5)
+		for i := range bodies {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, bodies[i] = get(t, cache, u, nil)
+			}(i)
+		}
+		<-u.requests
+		// the other requests wait for the first one
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		// then
+		assert.Equal(t, int32(1), u.calls.Load())
+		assert.Equal(t, []string{"books", "books", "books", "books", "books"}, bodies)
+	})
+
+	t.Run("should release the waiting requests, before the first client read the body", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		release := make(chan struct{})
+		u := newUpstream(nil, "")
+		u.respond = func(*http.Request) (http.Header, int, string) {
+			<-release
+			return http.Header{"Cache-Control": {"max-age=60"}}, http.StatusOK, "books"
+		}
+		first := make(chan *http.Response)
+		go func() {
+			res, _ := cache.RoundTrip(httptest.NewRequest("GET", "http://example.com/api/v1/books?page=1", nil), u.send)
+			first <- res
+		}()
+		<-u.requests
+
+		// when
+		waiting := make(chan string)
+		go func() {
+			_, body := get(t, cache, u, nil)
+			waiting <- body
+		}()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		res := <-first
+		body := <-waiting
+		res.Body.Close()
+
+		// then
+		assert.Equal(t, "books", body)
+		assert.Equal(t, int32(1), u.calls.Load())
+	})
+
+	t.Run("should not coalesce requests, whose responses can't be shared", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		release := make(chan struct{})
+		u := newUpstream(nil, "")
+		u.respond = func(*http.Request) (http.Header, int, string) {
+			<-release
+			return http.Header{"Cache-Control": {"max-age=60"}}, http.StatusOK, "books"
+		}
+		first := make(chan string)
+		go func() {
+			_, body := get(t, cache, u, nil)
+			first <- body
+		}()
+		<-u.requests
+
+		// when
+		authorized := make(chan string)
+		go func() {
+			_, body := get(t, cache, u, http.Header{"Authorization": {"Bearer token"}})
+			authorized <- body
+		}()
+		<-u.requests
+		close(release)
+
+		// then
+		assert.Equal(t, "books", <-first)
+		assert.Equal(t, "books", <-authorized)
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should validate for requests with no-cache", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}}, "books")
+		get(t, cache, u, nil)
+
+		// when
+		get(t, cache, u, http.Header{"Cache-Control": {"no-cache"}})
+		get(t, cache, u, http.Header{"Pragma": {"no-cache"}})
+
+		// then
+		assert.Equal(t, int32(3), u.calls.Load())
+	})
+
+	t.Run("should answer only-if-cached misses with 504 GATEWAY TIMEOUT", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}}, "books")
+
+		// when
+		res, _ := get(t, cache, u, http.Header{"Cache-Control": {"only-if-cached"}})
+
+		// then
+		assert.Equal(t, http.StatusGatewayTimeout, res.StatusCode)
+		assert.Equal(t, int32(0), u.calls.Load())
+	})
+
+	t.Run("should invalidate the response after an unsafe request", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}}, "books")
+		get(t, cache, u, nil)
+
+		// when
+		r := httptest.NewRequest("POST", "http://example.com/api/v1/books?page=1", nil)
+		res, err := cache.RoundTrip(r, u.send)
+		assert.NoError(t, err)
+		res.Body.Close()
+		get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, int32(3), u.calls.Load())
+	})
+
+	t.Run("should forward large bodies completely without storing them", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{MaxBodySize: 4})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}}, "a long list of books")
+
+		// when
+		_, first := get(t, cache, u, nil)
+		_, second := get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, "a long list of books", first)
+		assert.Equal(t, "a long list of books", second)
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should serve HEAD requests from stored GET responses", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{})
+		u := newUpstream(http.Header{"Cache-Control": {"max-age=60"}}, "books")
+		get(t, cache, u, nil)
+
+		// when
+		r := httptest.NewRequest("HEAD", "http://example.com/api/v1/books?page=1", nil)
+		res, err := cache.RoundTrip(r, u.send)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "5", res.Header.Get("Content-Length"))
+		assert.Equal(t, http.NoBody, res.Body)
+		assert.Equal(t, int32(1), u.calls.Load())
+	})
+	t.Run("should not store bodies of an unknown length", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{DefaultTTL: time.Minute})
+		u := newUpstream(http.Header{}, "books")
+		send := func(r *http.Request) (*http.Response, error) {
+			res, err := u.send(r)
+			res.ContentLength = -1
+			return res, err
+		}
+
+		// when
+		for i := 0; i < 2; i++ {
+			res, err := cache.RoundTrip(httptest.NewRequest("GET", "http://example.com/api/v1/books", nil), send)
+			assert.NoError(t, err)
+			io.ReadAll(res.Body)
+			res.Body.Close()
+		}
+
+		// then
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+
+	t.Run("should stream the body to the client, while it is stored", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{DefaultTTL: time.Minute})
+		reader, writer := io.Pipe()
+		u := newUpstream(http.Header{}, "")
+		send := func(r *http.Request) (*http.Response, error) {
+			u.calls.Add(1)
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: reader, ContentLength: 10}, nil
+		}
+		go writer.Write([]byte("first"))
+
+		// when
+		res, err := cache.RoundTrip(httptest.NewRequest("GET", "http://example.com/api/v1/books?page=1", nil), send)
+		assert.NoError(t, err)
+		first := make([]byte, 5)
+		_, err = io.ReadFull(res.Body, first)
+		assert.NoError(t, err)
+		go func() {
+			writer.Write([]byte("-part"))
+			writer.Close()
+		}()
+		rest, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		_, cached := get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, "first", string(first))
+		assert.Equal(t, "-part", string(rest))
+		assert.Equal(t, "first-part", cached)
+		assert.Equal(t, int32(1), u.calls.Load())
+	})
+
+	t.Run("should store the body, while the client reads it slowly", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{DefaultTTL: time.Minute})
+		u := newUpstream(http.Header{}, "books")
+
+		// when
+		res, err := cache.RoundTrip(httptest.NewRequest("GET", "http://example.com/api/v1/books?page=1", nil), u.send)
+		assert.NoError(t, err)
+		first := make([]byte, 2)
+		io.ReadFull(res.Body, first)
+		_, cached := get(t, cache, u, nil)
+		res.Body.Close()
+
+		// then
+		assert.Equal(t, "bo", string(first))
+		assert.Equal(t, "books", cached)
+		assert.Equal(t, int32(1), u.calls.Load())
+	})
+
+	t.Run("should not store a body, which the upstream didn't send completely", func(t *testing.T) {
+		// given
+		setNow(t)
+		cache := newTestCache(Config{DefaultTTL: time.Minute})
+		u := newUpstream(http.Header{}, "books")
+		send := func(r *http.Request) (*http.Response, error) {
+			res, err := u.send(r)
+			res.ContentLength = 10
+			return res, err
+		}
+
+		// when
+		res, err := cache.RoundTrip(httptest.NewRequest("GET", "http://example.com/api/v1/books?page=1", nil), send)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		get(t, cache, u, nil)
+
+		// then
+		assert.Equal(t, "books", string(body))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, int32(2), u.calls.Load())
+	})
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// directives are the Cache-Control directives of a request or a response, the names are lowercase
+type directives map[string]string
+
+func parseCacheControl(header http.Header) directives {
+	d := directives{}
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+			d[strings.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+	return d
+}
+
+func (d directives) has(name string) bool {
+	_, ok := d[name]
+	return ok
+}
+
+// seconds is the delta-seconds argument of the directive, invalid arguments are treated like missing directives
+func (d directives) seconds(name string) (time.Duration, bool) {
+	argument, ok := d[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(argument, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// requestNoCache reports if the client wants a validated response (RFC 9111, section 5.2.1.4),
+// Pragma is only used without Cache-Control (section 5.4)
+func requestNoCache(header http.Header, d directives) bool {
+	if d.has("no-cache") {
+		return true
+	}
+	return len(header.Values("Cache-Control")) == 0 && strings.EqualFold(header.Get("Pragma"), "no-cache")
+}
+
+// heuristicallyCacheable are the status codes, which may be stored without explicit freshness
+// (RFC 9110, section 15.1). Other status codes aren't stored at all.
+var heuristicallyCacheable = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// freshnessLifetime is the time, a response is fresh after it was generated (RFC 9111, section 4.2.1).
+// The shared max age is preferred over the max age and Expires, defaultTTL is used without them.
+func freshnessLifetime(header http.Header, d directives, defaultTTL time.Duration) time.Duration {
+	if lifetime, ok := d.seconds("s-maxage"); ok {
+		return lifetime
+	}
+	if lifetime, ok := d.seconds("max-age"); ok {
+		return lifetime
+	}
+	if expires := header.Get("Expires"); expires != "" {
+		// an invalid date like 0 means, that the response is already expired
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		date, err := http.ParseTime(header.Get("Date"))
+		if err != nil {
+			return 0
+		}
+		return max(expiresAt.Sub(date), 0)
+	}
+	return defaultTTL
+}
+
+// initialAge is the age of a response, when it was received (RFC 9111, section 4.2.3)
+func initialAge(header http.Header, requestTime time.Time, responseTime time.Time) time.Duration {
+	var apparentAge time.Duration
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		apparentAge = max(responseTime.Sub(date), 0)
+	}
+	var ageValue time.Duration
+	if seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		ageValue = time.Duration(seconds) * time.Second
+	}
+	return max(apparentAge, ageValue+responseTime.Sub(requestTime))
+}
+
+// etagMatches compares the entity tags of If-None-Match weakly (RFC 9110, section 13.1.2)
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified reports if the response fulfills the conditions of the request, so 304 can be sent instead
+func notModified(r *http.Request, header http.Header) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, header.Get("ETag"))
+	}
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	return err == nil && !lastModified.After(ifModifiedSince)
+}
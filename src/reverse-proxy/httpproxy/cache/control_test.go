@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	t.Run("should parse the directives of all headers", func(t *testing.T) {
+		// given
+		header := http.Header{"Cache-Control": {`Public, max-age=60`, `stale-while-revalidate="30", bogus=x`}}
+
+		// when
+		d := parseCacheControl(header)
+
+		// then
+		assert.True(t, d.has("public"))
+		maxAge, ok := d.seconds("max-age")
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, maxAge)
+		swr, _ := d.seconds("stale-while-revalidate")
+		assert.Equal(t, 30*time.Second, swr)
+		_, ok = d.seconds("bogus")
+		assert.False(t, ok)
+	})
+
+	t.Run("should only use Pragma without Cache-Control", func(t *testing.T) {
+		assert.True(t, requestNoCache(http.Header{"Pragma": {"no-cache"}}, directives{}))
+		header := http.Header{"Pragma": {"no-cache"}, "Cache-Control": {"max-age=5"}}
+		assert.False(t, requestNoCache(header, parseCacheControl(header)))
+	})
+}
+
+func TestFreshnessLifetime(t *testing.T) {
+	date := "Fri, 01 Dec 2023 12:00:00 GMT"
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"should prefer s-maxage", http.Header{"Cache-Control": {"max-age=10, s-maxage=20"}}, 20 * time.Second},
+		{"should use max-age", http.Header{"Cache-Control": {"max-age=10"}, "Expires": {"Fri, 01 Dec 2023 13:00:00 GMT"}}, 10 * time.Second},
+		{"should use Expires", http.Header{"Date": {date}, "Expires": {"Fri, 01 Dec 2023 12:05:00 GMT"}}, 5 * time.Minute},
+		{"should treat an invalid Expires as expired", http.Header{"Date": {date}, "Expires": {"0"}}, 0},
+		{"should use the default ttl", http.Header{}, time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, freshnessLifetime(test.header, parseCacheControl(test.header), time.Hour))
+		})
+	}
+}
+
+func TestInitialAge(t *testing.T) {
+	t.Run("should use the larger of the apparent age and the Age header with the delay", func(t *testing.T) {
+		// given
+		requestTime := time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)
+		responseTime := requestTime.Add(time.Second)
+		header := http.Header{"Date": {"Fri, 01 Dec 2023 11:59:50 GMT"}, "Age": {"5"}}
+
+		// when
+		age := initialAge(header, requestTime, responseTime)
+
+		// then
+		assert.Equal(t, 11*time.Second, age)
+	})
+}
+
+func TestNotModified(t *testing.T) {
+	header := http.Header{"Etag": {`W/"v1"`}, "Last-Modified": {"Fri, 01 Dec 2023 12:00:00 GMT"}}
+	tests := []struct {
+		name      string
+		condition http.Header
+		want      bool
+	}{
+		{"should match the etag weakly", http.Header{"If-None-Match": {`"v0", "v1"`}}, true},
+		{"should match any etag", http.Header{"If-None-Match": {"*"}}, true},
+		{"should not match another etag", http.Header{"If-None-Match": {`"v2"`}}, false},
+		{"should ignore If-Modified-Since with If-None-Match", http.Header{"If-None-Match": {`"v2"`}, "If-Modified-Since": {"Fri, 01 Dec 2023 13:00:00 GMT"}}, false},
+		{"should match an unmodified date", http.Header{"If-Modified-Since": {"Fri, 01 Dec 2023 12:00:00 GMT"}}, true},
+		{"should not match a modified date", http.Header{"If-Modified-Since": {"Fri, 01 Dec 2023 11:00:00 GMT"}}, false},
+		{"should not match without conditions", http.Header{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// given
+			r := httptest.NewRequest("GET", "/books", nil)
+			r.Header = test.condition
+
+			// then
+			assert.Equal(t, test.want, notModified(r, header))
+		})
+	}
+}
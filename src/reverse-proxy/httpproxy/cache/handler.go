@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type purgeResult struct {
+	Purged int `json:"purged"`
+}
+
+type storeStats struct {
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
+
+type purgeHandler struct {
+	store *Store
+}
+
+// NewPurgeHandler shows the size of the store with GET and removes the responses, whose path starts
+// with the prefix-parameter, with DELETE
+func NewPurgeHandler(store *Store) http.Handler {
+	return &purgeHandler{store}
+}
+
+func (handler *purgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		entries, bytes := handler.store.Stats()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(storeStats{Entries: entries, Bytes: bytes})
+	case http.MethodDelete:
+		prefix := r.URL.Query().Get("prefix")
+		if !strings.HasPrefix(prefix, "/") {
+			http.Error(w, "the prefix-parameter must be a path", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(purgeResult{Purged: handler.store.Purge(prefix)})
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodDelete)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurgeHandler(t *testing.T) {
+	t.Run("should purge the entries of the prefix", func(t *testing.T) {
+		// given
+		store := NewStore(StoreConfig{MaxEntries: 10, MaxBytes: 1000})
+		store.put("books", testEntry("/api/v1/books", "", nil))
+		store.put("chapters", testEntry("/api/v1/chapters", "", nil))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/admin/cache?prefix=/api/v1/books", nil)
+
+		// when
+		NewPurgeHandler(store).ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"purged": 1}`, w.Body.String())
+	})
+
+	t.Run("should show the size of the store", func(t *testing.T) {
+		// given
+		store := NewStore(StoreConfig{MaxEntries: 10, MaxBytes: 1000})
+		store.put("books", testEntry("/books", "body", nil))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/admin/cache", nil)
+
+		// when
+		NewPurgeHandler(store).ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"entries": 1, "bytes": 10}`, w.Body.String())
+	})
+
+	t.Run("should reject a purge without a prefix", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/admin/cache", nil)
+
+		// when
+		NewPurgeHandler(NewStore(StoreConfig{MaxEntries: 1, MaxBytes: 1})).ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should only allow GET and DELETE", func(t *testing.T) {
+		// given
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/admin/cache", nil)
+
+		// when
+		NewPurgeHandler(NewStore(StoreConfig{MaxEntries: 1, MaxBytes: 1})).ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, DELETE", w.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type StoreConfig struct {
+	// MaxEntries limits the stored responses, every variant of a URL is an entry
+	MaxEntries int   `env:"MAX_ENTRIES" envDefault:"10000" validate:"min=1"`
+	MaxBytes   int64 `env:"MAX_BYTES" envDefault:"67108864" validate:"min=1"`
+}
+
+// item holds the variants of a URL, which were selected by the Vary header
+type item struct {
+	key      string
+	variants []*entry
+	size     int64
+}
+
+// Store keeps the responses of all caches in memory and removes the least recently used ones,
+// when it is full. The responses of a URL are removed together.
+type Store struct {
+	config StoreConfig
+
+	mu      sync.Mutex
+	lru     *list.List
+	items   map[string]*list.Element
+	entries int
+	size    int64
+}
+
+func NewStore(config StoreConfig) *Store {
+	return &Store{config: config, lru: list.New(), items: map[string]*list.Element{}}
+}
+
+// get returns the variant of the URL, which was selected by the headers of the request
+func (store *Store) get(key string, r *http.Request) *entry {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	element, ok := store.items[key]
+	if !ok {
+		return nil
+	}
+	store.lru.MoveToFront(element)
+	for _, e := range element.Value.(*item).variants {
+		if e.selects(r) {
+			return e
+		}
+	}
+	return nil
+}
+
+// put stores the entry in place of the variant with the same selecting headers
+func (store *Store) put(key string, e *entry) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if e.size() > store.config.MaxBytes {
+		return
+	}
+
+	element, ok := store.items[key]
+	if !ok {
+		element = store.lru.PushFront(&item{key: key})
+		store.items[key] = element
+	}
+	store.lru.MoveToFront(element)
+
+	it := element.Value.(*item)
+	variants := make([]*entry, 0, len(it.variants)+1)
+	for _, variant := range it.variants {
+		if variant.sameVariant(e) {
+			store.entries--
+			store.size -= variant.size()
+			it.size -= variant.size()
+			continue
+		}
+		variants = append(variants, variant)
+	}
+	it.variants = append(variants, e)
+	it.size += e.size()
+	store.entries++
+	store.size += e.size()
+
+	for store.entries > store.config.MaxEntries || store.size > store.config.MaxBytes {
+		store.removeElement(store.lru.Back())
+	}
+}
+
+// remove removes all variants of the URL
+func (store *Store) remove(key string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if element, ok := store.items[key]; ok {
+		store.removeElement(element)
+	}
+}
+
+func (store *Store) removeElement(element *list.Element) {
+	it := element.Value.(*item)
+	store.lru.Remove(element)
+	delete(store.items, it.key)
+	store.entries -= len(it.variants)
+	store.size -= it.size
+}
+
+// Purge removes the responses of all URLs, whose path starts with the prefix, and returns their number
+func (store *Store) Purge(prefix string) int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	purged := 0
+	for element := store.lru.Front(); element != nil; {
+		next := element.Next()
+		it := element.Value.(*item)
+		if len(it.variants) > 0 && strings.HasPrefix(it.variants[0].path, prefix) {
+			purged += len(it.variants)
+			store.removeElement(element)
+		}
+		element = next
+	}
+	return purged
+}
+
+// Stats are the number and the size of the stored responses
+func (store *Store) Stats() (int, int64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.entries, store.size
+}
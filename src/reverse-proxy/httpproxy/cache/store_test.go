@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEntry(path string, body string, selecting http.Header) *entry {
+	if selecting == nil {
+		selecting = http.Header{}
+	}
+	return &entry{path: path, status: http.StatusOK, header: http.Header{}, body: []byte(body), selecting: selecting}
+}
+
+func TestStore(t *testing.T) {
+	t.Run("should keep a variant per selecting header", func(t *testing.T) {
+		// given
+		store := NewStore(StoreConfig{MaxEntries: 10, MaxBytes: 1000})
+		german := httptest.NewRequest("GET", "/books", nil)
+		german.Header.Set("Accept-Language", "de")
+		english := httptest.NewRequest("GET", "/books", nil)
+		english.Header.Set("Accept-Language", "en")
+
+		// when
+		store.put("books", testEntry("/books", "Bücher", http.Header{"Accept-Language": {"de"}}))
+		store.put("books", testEntry("/books", "books", http.Header{"Accept-Language": {"en"}}))
+		store.put("books", testEntry("/books", "Buecher", http.Header{"Accept-Language": {"de"}}))
+
+		// then
+		assert.Equal(t, "Buecher", string(store.get("books", german).body))
+		assert.Equal(t, "books", string(store.get("books", english).body))
+		entries, _ := store.Stats()
+		assert.Equal(t, 2, entries)
+	})
+
+	t.Run("should remove the least recently used entries", func(t *testing.T) {
+		// given
+		store := NewStore(StoreConfig{MaxEntries: 2, MaxBytes: 1000})
+		r := httptest.NewRequest("GET", "/", nil)
+		store.put("a", testEntry("/a", "a", nil))
+		store.put("b", testEntry("/b", "b", nil))
+
+		// when
+		store.get("a", r)
+		store.put("c", testEntry("/c", "c", nil))
+
+		// then
+		assert.NotNil(t, store.get("a", r))
+		assert.Nil(t, store.get("b", r))
+		assert.NotNil(t, store.get("c", r))
+	})
+
+	t.Run("should limit the size", func(t *testing.T) {
+		// given
+		store := NewStore(StoreConfig{MaxEntries: 10, MaxBytes: 20})
+		r := httptest.NewRequest("GET", "/", nil)
+
+		// when
+		store.put("a", testEntry("/a", "0123456789", nil))
+		store.put("b", testEntry("/b", "0123456789", nil))
+		store.put("c", testEntry("/c", "this body is too large to store", nil))
+
+		// then
+		assert.Nil(t, store.get("a", r))
+		assert.NotNil(t, store.get("b", r))
+		assert.Nil(t, store.get("c", r))
+		_, size := store.Stats()
+		assert.Equal(t, int64(12), size)
+	})
+
+	t.Run("should purge the entries by path prefix", func(t *testing.T) {
+		// given
+		store := NewStore(StoreConfig{MaxEntries: 10, MaxBytes: 1000})
+		store.put("books", testEntry("/api/v1/books", "", nil))
+		store.put("book", testEntry("/api/v1/books/1", "", nil))
+		store.put("chapters", testEntry("/api/v1/chapters", "", nil))
+
+		// when
+		purged := store.Purge("/api/v1/books")
+
+		// then
+		assert.Equal(t, 2, purged)
+		entries, _ := store.Stats()
+		assert.Equal(t, 1, entries)
+	})
+}
@@ -0,0 +1,172 @@
+// Package circuitbreaker stops sending requests to a route, which keeps failing, so its upstreams can recover.
+package circuitbreaker
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// now is replaced by the tests to move through the open duration
+var now = time.Now
+
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half-open"
+)
+
+type Config struct {
+	// FailureThreshold is the number of failed requests in a row, which opens the circuit
+	FailureThreshold int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	// OpenDuration is the time until the open circuit lets probes through
+	OpenDuration time.Duration `yaml:"openDuration,omitempty" json:"openDuration,omitempty"`
+	// HalfOpenRequests are the probes let through, while the circuit is half-open. It closes,
+	// when all of them succeed, and opens again, when one of them fails.
+	HalfOpenRequests int `yaml:"halfOpenRequests,omitempty" json:"halfOpenRequests,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for the unset values
+func (config Config) WithDefaults() Config {
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenDuration == 0 {
+		config.OpenDuration = 30 * time.Second
+	}
+	if config.HalfOpenRequests == 0 {
+		config.HalfOpenRequests = 1
+	}
+	return config
+}
+
+func (config Config) Validate() error {
+	if config.FailureThreshold < 0 || config.OpenDuration < 0 || config.HalfOpenRequests < 0 {
+		return errors.New("the values of circuitBreaker must not be negative")
+	}
+	return nil
+}
+
+type Breaker struct {
+	config Config
+	route  string
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	failures   int
+	openedAt   time.Time
+	probes     int
+	successes  int
+}
+
+// NewBreaker creates the closed circuit of a route, the route is only used for logging
+func NewBreaker(config Config, route string) *Breaker {
+	return &Breaker{config: config.WithDefaults(), route: route, state: Closed}
+}
+
+// Allow reports if a request may be sent. The result of every allowed request must be reported
+// with the returned generation.
+func (b *Breaker) Allow() (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open {
+		if now().Before(b.openedAt.Add(b.config.OpenDuration)) {
+			return 0, false
+		}
+		b.setState(HalfOpen)
+		b.probes = 0
+		b.successes = 0
+		slog.Info("circuit is half-open", "route", b.route)
+	}
+
+	if b.state == HalfOpen {
+		if b.probes >= b.config.HalfOpenRequests {
+			return 0, false
+		}
+		b.probes++
+	}
+	return b.generation, true
+}
+
+// Report records the result of a request allowed in the generation. The results of requests allowed
+// before the state changed are ignored, e.g. a slow request from before the circuit opened must not
+// count as the result of a probe.
+func (b *Breaker) Report(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	switch b.state {
+	case Closed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.open()
+		}
+	case HalfOpen:
+		if !success {
+			b.open()
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenRequests {
+			b.setState(Closed)
+			b.failures = 0
+			slog.Info("closed the circuit", "route", b.route)
+		}
+	}
+}
+
+// Cancel is called instead of Report for a request allowed in the generation, whose result says nothing
+// about the route, e.g. because the client canceled it. Its probe is given back.
+func (b *Breaker) Cancel(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation && b.state == HalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// open opens the circuit, the mutex must be held
+func (b *Breaker) open() {
+	b.setState(Open)
+	b.openedAt = now()
+	slog.Warn("opened the circuit", "route", b.route, "duration", b.config.OpenDuration)
+}
+
+// setState starts a new generation with the state, the mutex must be held
+func (b *Breaker) setState(state State) {
+	b.state = state
+	b.generation++
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// RetryAfter returns the time until the open circuit lets probes through. While the probes of the
+// half-open circuit are pending, it returns the open duration, since a failing probe opens it again.
+func (b *Breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Open:
+		return max(b.openedAt.Add(b.config.OpenDuration).Sub(now()), 0)
+	case HalfOpen:
+		return b.config.OpenDuration
+	default:
+		return 0
+	}
+}
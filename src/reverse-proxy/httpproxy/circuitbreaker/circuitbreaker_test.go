@@ -0,0 +1,173 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setNow moves the clock of the package to the returned time
+func setNow(t *testing.T) *time.Time {
+	current := time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+	return &current
+}
+
+// fail reports failed requests to the breaker
+func fail(b *Breaker, times int) {
+	for i := 0; i < times; i++ {
+		generation, _ := b.Allow()
+		b.Report(generation, false)
+	}
+}
+
+// allowed returns if a request may be sent
+func allowed(b *Breaker) bool {
+	_, ok := b.Allow()
+	return ok
+}
+
+func TestBreaker(t *testing.T) {
+	t.Run("should open after consecutive failures", func(t *testing.T) {
+		// given
+		setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 3}, "/route")
+
+		// when
+		fail(b, 2)
+		generation, _ := b.Allow()
+		b.Report(generation, true)
+		fail(b, 2)
+		closed := b.State()
+		fail(b, 1)
+
+		// then
+		assert.Equal(t, Closed, closed)
+		assert.Equal(t, Open, b.State())
+		assert.False(t, allowed(b))
+		assert.Equal(t, 30*time.Second, b.RetryAfter())
+	})
+
+	t.Run("should let the probes through after the open duration", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 1, OpenDuration: time.Minute, HalfOpenRequests: 2}, "/route")
+		fail(b, 1)
+
+		// when
+		*current = current.Add(30 * time.Second)
+		beforeDuration := allowed(b)
+		*current = current.Add(30 * time.Second)
+		first := allowed(b)
+		second := allowed(b)
+		third := allowed(b)
+
+		// then
+		assert.False(t, beforeDuration)
+		assert.True(t, first)
+		assert.True(t, second)
+		assert.False(t, third)
+		assert.Equal(t, HalfOpen, b.State())
+		assert.Equal(t, time.Minute, b.RetryAfter())
+	})
+
+	t.Run("should close, when all probes succeed", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 1, OpenDuration: time.Minute, HalfOpenRequests: 2}, "/route")
+		fail(b, 1)
+		*current = current.Add(time.Minute)
+		first, _ := b.Allow()
+		second, _ := b.Allow()
+
+		// when
+		b.Report(first, true)
+		halfOpen := b.State()
+		b.Report(second, true)
+
+		// then
+		assert.Equal(t, HalfOpen, halfOpen)
+		assert.Equal(t, Closed, b.State())
+		assert.True(t, allowed(b))
+	})
+
+	t.Run("should open again, when a probe fails", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 1, OpenDuration: time.Minute}, "/route")
+		fail(b, 1)
+		*current = current.Add(time.Minute)
+		generation, _ := b.Allow()
+
+		// when
+		b.Report(generation, false)
+
+		// then
+		assert.Equal(t, Open, b.State())
+		assert.Equal(t, time.Minute, b.RetryAfter())
+	})
+
+	t.Run("should give the probe of a canceled request back", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 1, OpenDuration: time.Minute}, "/route")
+		fail(b, 1)
+		*current = current.Add(time.Minute)
+		generation, _ := b.Allow()
+
+		// when
+		b.Cancel(generation)
+
+		// then
+		assert.True(t, allowed(b))
+	})
+}
+
+func TestBreakerGeneration(t *testing.T) {
+	t.Run("should ignore the results of requests allowed before the circuit opened", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 1, OpenDuration: time.Minute}, "/route")
+		slow, _ := b.Allow()
+		fail(b, 1)
+		*current = current.Add(time.Minute)
+		probe, _ := b.Allow()
+
+		// when
+		b.Report(slow, true)
+		b.Cancel(slow)
+		halfOpen := b.State()
+		exhausted := allowed(b)
+		b.Report(probe, true)
+
+		// then
+		assert.Equal(t, HalfOpen, halfOpen)
+		assert.False(t, exhausted)
+		assert.Equal(t, Closed, b.State())
+	})
+
+	t.Run("should ignore the results of probes from an earlier half-open circuit", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		b := NewBreaker(Config{FailureThreshold: 1, OpenDuration: time.Minute, HalfOpenRequests: 2}, "/route")
+		fail(b, 1)
+		*current = current.Add(time.Minute)
+		stale, _ := b.Allow()
+		fail(b, 1)
+		*current = current.Add(time.Minute)
+		b.Allow()
+
+		// when
+		b.Report(stale, false)
+
+		// then
+		assert.Equal(t, HalfOpen, b.State())
+	})
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.EqualError(t, Config{OpenDuration: -time.Second}.Validate(), "the values of circuitBreaker must not be negative")
+}
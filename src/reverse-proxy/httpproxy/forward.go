@@ -0,0 +1,327 @@
+package httpproxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/retry"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+// TimeoutConfig limits every attempt to send a request to an upstream
+type TimeoutConfig struct {
+	// Connect is the time until the connection to the upstream is established
+	Connect time.Duration `yaml:"connect,omitempty" json:"connect,omitempty"`
+	// Read is the time from the established connection until the headers of the response arrived,
+	// the body may be streamed for longer
+	Read time.Duration `yaml:"read,omitempty" json:"read,omitempty"`
+}
+
+func (config TimeoutConfig) Validate() error {
+	if config.Connect < 0 || config.Read < 0 {
+		return errors.New("the values of timeouts must not be negative")
+	}
+	return nil
+}
+
+type timeoutError string
+
+func (err timeoutError) Error() string { return string(err) }
+func (err timeoutError) Timeout() bool { return true }
+
+var (
+	errNoUpstream     = errors.New("no upstream available")
+	errCircuitOpen    = errors.New("circuit is open")
+	errConnectTimeout = timeoutError("timeout while connecting to the upstream")
+	errReadTimeout    = timeoutError("timeout while waiting for the response of the upstream")
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (send roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return send(r)
+}
+
+// forward serves the request from the cache of the mapping or sends it to an upstream with its timeouts,
+// retries and circuit breaker. send is replaced by the own transport of the mapping, if it has one.
+// The URL of out is pointed at the upstream of every attempt, so its path must be the one of the
+// incoming request. The body of the response must be closed.
+func (mapping *RouteMapping) forward(out *http.Request, send roundTripFunc) (*http.Response, error) {
+	if mapping.instrumentedTransport != nil {
+		send = mapping.instrumentedTransport.RoundTrip
+	}
+	if mapping.cache == nil {
+		return mapping.forwardToUpstream(out, send)
+	}
+	return mapping.cache.RoundTrip(out, func(out *http.Request) (*http.Response, error) {
+		return mapping.forwardToUpstream(out, send)
+	})
+}
+
+func (mapping *RouteMapping) forwardToUpstream(out *http.Request, send roundTripFunc) (*http.Response, error) {
+	if mapping.breaker == nil {
+		return mapping.sendWithRetries(out, send)
+	}
+
+	generation, allowed := mapping.breaker.Allow()
+	if !allowed {
+		return nil, errCircuitOpen
+	}
+	res, err := mapping.sendWithRetries(out, send)
+	if out.Context().Err() != nil {
+		mapping.breaker.Cancel(generation)
+	} else {
+		mapping.breaker.Report(generation, err == nil && res.StatusCode < http.StatusInternalServerError)
+	}
+	return res, err
+}
+
+func (mapping *RouteMapping) sendWithRetries(out *http.Request, send roundTripFunc) (*http.Response, error) {
+	path, rawPath := out.URL.Path, out.URL.RawPath
+	id := out.Header.Get(logger.RequestIDHeader)
+
+	attempts := 1
+	if mapping.retryPolicy != nil {
+		mapping.retryPolicy.Deposit()
+		if retry.Retryable(out) {
+			attempts += mapping.retryPolicy.Attempts()
+		}
+	}
+
+	tried := map[*upstream.Upstream]bool{}
+	selected := mapping.next(out, tried)
+	if selected == nil {
+		return nil, errNoUpstream
+	}
+
+	for attempt := 1; ; attempt++ {
+		tried[selected] = true
+		out.URL.Path, out.URL.RawPath = path, rawPath
+		logForward(out, id, selected.Url)
+		setUpstream(out, selected)
+
+		res, err := mapping.attempt(out, send, selected)
+		if attempt >= attempts || !shouldRetry(out, res, err) {
+			return res, err
+		}
+
+		next := mapping.next(out, tried)
+		if next == nil || !mapping.retryPolicy.Withdraw() {
+			return res, err
+		}
+
+		var reason string
+		if err == nil {
+			reason = res.Status
+			res.Body.Close()
+		} else {
+			reason = err.Error()
+		}
+		slog.WarnContext(out.Context(), "retrying request", "request_id", id, "upstream", selected.Url.Host, "attempt", attempt, "reason", reason)
+
+		if err := sleep(out.Context(), mapping.retryPolicy.Backoff(attempt)); err != nil {
+			return nil, err
+		}
+		if out.GetBody != nil {
+			if out.Body, err = out.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		selected = next
+	}
+}
+
+// next selects the upstream of the next attempt and prefers one, which wasn't tried yet
+func (mapping *RouteMapping) next(r *http.Request, tried map[*upstream.Upstream]bool) *upstream.Upstream {
+	selected := mapping.strategy.Next(r)
+	if selected == nil || !tried[selected] {
+		return selected
+	}
+	for _, u := range mapping.upstreams {
+		if !tried[u] && u.Available() {
+			return u
+		}
+	}
+	return selected
+}
+
+// setUpstream points the URL of the request at the upstream, the path of the upstream is prepended
+func setUpstream(out *http.Request, selected *upstream.Upstream) {
+	host := selected.Url
+	if out.URL.RawPath != "" {
+		out.URL.RawPath = strings.TrimSuffix(host.EscapedPath(), "/") + out.URL.RawPath
+	}
+	out.URL.Path = strings.TrimSuffix(host.Path, "/") + out.URL.Path
+	out.URL.Scheme = host.Scheme
+	out.URL.Host = host.Host
+}
+
+// attempt sends the request to the upstream once and reports the result to it
+func (mapping *RouteMapping) attempt(out *http.Request, send roundTripFunc, selected *upstream.Upstream) (*http.Response, error) {
+	release := selected.Acquire()
+
+	req, timer := out, (*attemptTimer)(nil)
+	if mapping.config.Timeouts != nil {
+		req, timer = startTimeouts(out, *mapping.config.Timeouts)
+	}
+
+	res, err := send(req)
+	if timer != nil {
+		timer.stop()
+		if err != nil {
+			if cause := context.Cause(req.Context()); isTimeout(cause) {
+				err = cause
+			}
+		}
+	}
+
+	if err != nil {
+		reportRequest(out, selected, 0, err)
+		timer.cancel()
+		release()
+		return nil, err
+	}
+	reportRequest(out, selected, res.StatusCode, nil)
+
+	body := &releasingBody{ReadCloser: res.Body, release: func() {
+		timer.cancel()
+		release()
+	}}
+	if writer, ok := res.Body.(io.Writer); ok {
+		// the connection of a protocol switch, like a WebSocket, is written to as well
+		res.Body = &releasingReadWriteBody{body, writer}
+	} else {
+		res.Body = body
+	}
+	return res, nil
+}
+
+func shouldRetry(out *http.Request, res *http.Response, err error) bool {
+	if out.Context().Err() != nil {
+		return false
+	}
+	return err != nil || retry.RetryableStatus(res.StatusCode)
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isTimeout(err error) bool {
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// releasingBody releases the upstream and the timeouts of the attempt, when the response is done
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (body *releasingBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.once.Do(body.release)
+	return err
+}
+
+type releasingReadWriteBody struct {
+	*releasingBody
+	writer io.Writer
+}
+
+func (body *releasingReadWriteBody) Write(p []byte) (int, error) {
+	return body.writer.Write(p)
+}
+
+// attemptTimer cancels the context of an attempt, when the connect or read timeout expires
+type attemptTimer struct {
+	ctxCancel context.CancelCauseFunc
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// startTimeouts returns the request with a context, which is canceled by the timeouts. The connect timeout
+// starts right away, the read timeout once the connection is established.
+func startTimeouts(r *http.Request, config TimeoutConfig) (*http.Request, *attemptTimer) {
+	ctx, cancel := context.WithCancelCause(r.Context())
+	timer := &attemptTimer{ctxCancel: cancel}
+	timer.reset(config.Connect, errConnectTimeout)
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			timer.reset(config.Read, errReadTimeout)
+		},
+	})
+	return r.WithContext(ctx), timer
+}
+
+func (t *attemptTimer) reset(duration time.Duration, cause error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	if duration > 0 {
+		t.timer = time.AfterFunc(duration, func() { t.ctxCancel(cause) })
+	}
+}
+
+// stop stops the timeouts, when the headers of the response arrived
+func (t *attemptTimer) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// cancel frees the context of the attempt, it may be called on a nil timer
+func (t *attemptTimer) cancel() {
+	if t != nil {
+		t.stop()
+		t.ctxCancel(context.Canceled)
+	}
+}
+
+// writeError answers a request, which couldn't be forwarded, without revealing the error to the client
+func writeError(w http.ResponseWriter, r *http.Request, id string, mapping *RouteMapping, err error) {
+	statusCode := http.StatusBadGateway
+	switch {
+	case errors.Is(err, errCircuitOpen):
+		statusCode = http.StatusServiceUnavailable
+		retryAfter := mapping.breaker.RetryAfter().Seconds()
+		w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(retryAfter)), 1)))
+	case errors.Is(err, errNoUpstream):
+		statusCode = http.StatusServiceUnavailable
+	case isTimeout(err):
+		statusCode = http.StatusGatewayTimeout
+	}
+
+	slog.ErrorContext(r.Context(), "could not forward the request", "request_id", id, "host", r.Host, "status", statusCode, "error", err)
+	w.WriteHeader(statusCode)
+}
@@ -0,0 +1,308 @@
+package httpproxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/cache"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/circuitbreaker"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/ratelimit"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/retry"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestProxy(t *testing.T, roundTripper http.RoundTripper, config MappingConfig) *HTTPUtilProxy {
+	proxy := NewHTTPUtilProxy(roundTripper)
+	config.Host, config.Path = "*", "/the/route"
+	mapping, err := NewRouteMapping(config, Dependencies{
+		RateLimitStore: ratelimit.NewMemoryStore(),
+		CacheStore:     cache.NewStore(cache.StoreConfig{MaxEntries: 100, MaxBytes: 1 << 20}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy.Append(mapping)
+	return proxy
+}
+
+func respondWith(statusCode int) *http.Response {
+	return &http.Response{StatusCode: statusCode, Status: http.StatusText(statusCode), Header: http.Header{}, Body: http.NoBody}
+}
+
+// attempts returns a pointer to the number of retries
+func attempts(n int) *int {
+	return &n
+}
+
+func TestForward(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should retry idempotent requests on another upstream", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{"http://new-host:3000", "http://second-host:8000/append"},
+			Retry: &retry.Config{Attempts: attempts(1), Backoff: time.Millisecond},
+		})
+
+		var hosts []string
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			hosts = append(hosts, r.URL.Host+r.URL.Path)
+			if r.URL.Host == "new-host:3000" {
+				return nil, errors.New("connection refused")
+			}
+			return respondWith(http.StatusOK), nil
+		}).Times(2)
+
+		w := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"new-host:3000/the/route", "second-host:8000/append/the/route"}, hosts)
+	})
+
+	t.Run("should return the last response after all attempts", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{"http://new-host:3000", "http://second-host:8000"},
+			Retry: &retry.Config{Attempts: attempts(2), Backoff: time.Millisecond},
+		})
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusServiceUnavailable), nil).Times(3)
+
+		w := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		for _, u := range proxy.Mappings()[0].upstreams {
+			assert.Equal(t, int64(0), u.InFlight())
+		}
+	})
+
+	t.Run("should not retry other methods or other responses", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{"http://new-host:3000", "http://second-host:8000"},
+			Retry: &retry.Config{},
+		})
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(nil, errors.New("connection refused"))
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusInternalServerError), nil)
+
+		post := httptest.NewRecorder()
+		get := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(post, httptest.NewRequest("POST", "/the/route", strings.NewReader("body")))
+		proxy.ServeHTTP(get, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadGateway, post.Code)
+		assert.Equal(t, http.StatusInternalServerError, get.Code)
+	})
+
+	t.Run("should not retry, when the attempts are 0", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{"http://new-host:3000", "http://second-host:8000"},
+			Retry: &retry.Config{Attempts: attempts(0), Backoff: time.Millisecond},
+		})
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusBadGateway), nil)
+
+		w := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("should stop retrying, when the budget is spent", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{"http://new-host:3000", "http://second-host:8000"},
+			Retry: &retry.Config{Attempts: attempts(1), Backoff: time.Millisecond},
+		})
+		for i := 0; i < 10; i++ {
+			proxy.Mappings()[0].retryPolicy.Withdraw()
+		}
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusBadGateway), nil)
+
+		w := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("should answer with 504 GATEWAY TIMEOUT after the connect timeout", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts:    []string{"http://new-host:3000"},
+			Timeouts: &TimeoutConfig{Connect: 10 * time.Millisecond},
+		})
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		})
+
+		w := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+
+	t.Run("should answer with 504 GATEWAY TIMEOUT after the read timeout", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer server.Close()
+
+		proxy := newTestProxy(t, http.DefaultTransport, MappingConfig{
+			Hosts:    []string{server.URL},
+			Timeouts: &TimeoutConfig{Connect: time.Second, Read: 20 * time.Millisecond},
+		})
+
+		w := httptest.NewRecorder()
+
+		// when
+		start := time.Now()
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should stream the body after the headers arrived within the read timeout", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte("slow body"))
+		}))
+		defer server.Close()
+
+		proxy := newTestProxy(t, http.DefaultTransport, MappingConfig{
+			Hosts:    []string{server.URL},
+			Timeouts: &TimeoutConfig{Read: 20 * time.Millisecond},
+		})
+
+		w := httptest.NewRecorder()
+
+		// when
+		proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "slow body", w.Body.String())
+	})
+
+	t.Run("should reject the requests of an open circuit", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts:          []string{"http://new-host:3000"},
+			CircuitBreaker: &circuitbreaker.Config{FailureThreshold: 2, OpenDuration: time.Minute},
+		})
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusInternalServerError), nil).Times(2)
+
+		codes := make([]int, 3)
+		var rejected *httptest.ResponseRecorder
+
+		// when
+		for i := range codes {
+			rejected = httptest.NewRecorder()
+			proxy.ServeHTTP(rejected, httptest.NewRequest("GET", "/the/route", nil))
+			codes[i] = rejected.Code
+		}
+
+		// then
+		assert.Equal(t, []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusServiceUnavailable}, codes)
+		assert.Equal(t, "60", rejected.Header().Get("Retry-After"))
+		assert.Equal(t, circuitbreaker.Open, proxy.Mappings()[0].breaker.State())
+	})
+	t.Run("should serve fresh responses from the cache of the mapping", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{"http://new-host:3000"},
+			Cache: &cache.Config{},
+		})
+		response := respondWith(http.StatusOK)
+		response.Header.Set("Cache-Control", "max-age=60")
+		response.Body = io.NopCloser(strings.NewReader("cached"))
+		response.ContentLength = int64(len("cached"))
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(response, nil).Times(1)
+
+		recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+
+		// when
+		for _, w := range recorders {
+			proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route?cached", nil))
+		}
+
+		// then
+		for _, w := range recorders {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "cached", w.Body.String())
+		}
+		assert.Equal(t, "reverse-proxy; hit", recorders[1].Header().Get("Cache-Status"))
+	})
+	t.Run("should purge the cached responses by the path of the client", func(t *testing.T) {
+		// given
+		store := cache.NewStore(cache.StoreConfig{MaxEntries: 100, MaxBytes: 1 << 20})
+		mapping, _ := NewRouteMapping(MappingConfig{
+			Host:        "*",
+			Path:        "/api/*",
+			Hosts:       []string{"http://new-host:3000"},
+			StripPrefix: "/api",
+			Cache:       &cache.Config{DefaultTTL: time.Minute},
+		}, Dependencies{CacheStore: store})
+		proxy := NewHTTPProxy(&http.Client{Transport: roundTripper})
+		proxy.Append(mapping)
+
+		var paths []string
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			paths = append(paths, r.URL.Path)
+			return respondWith(http.StatusOK), nil
+		}).Times(2)
+
+		// when
+		proxy.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/books", nil))
+		purged := store.Purge("/api/books")
+		proxy.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/books", nil))
+
+		// then
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, []string{"/books", "/books"}, paths)
+	})
+
+	t.Run("should reject a cache without a store", func(t *testing.T) {
+		// when
+		_, err := NewRouteMapping(MappingConfig{Path: "/a", Hosts: []string{"http://a:8080"}, Cache: &cache.Config{}}, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "cache needs a store")
+	})
+}
@@ -0,0 +1,126 @@
+// Package healthcheck checks the upstreams of a mapping actively in the background.
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+// Config of the active health checks of a mapping. A check succeeds, if a GET request to the
+// path of the upstream is answered with 200 OK within the timeout.
+type Config struct {
+	Path               string        `yaml:"path,omitempty" json:"path,omitempty"`
+	Interval           time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Timeout            time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	HealthyThreshold   int           `yaml:"healthyThreshold,omitempty" json:"healthyThreshold,omitempty"`
+	UnhealthyThreshold int           `yaml:"unhealthyThreshold,omitempty" json:"unhealthyThreshold,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for the unset values
+func (config Config) WithDefaults() Config {
+	if config.Path == "" {
+		config.Path = "/health/ready"
+	}
+	if config.Interval == 0 {
+		config.Interval = 10 * time.Second
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 2 * time.Second
+	}
+	if config.HealthyThreshold == 0 {
+		config.HealthyThreshold = 2
+	}
+	if config.UnhealthyThreshold == 0 {
+		config.UnhealthyThreshold = 3
+	}
+	return config
+}
+
+func (config Config) Validate() error {
+	if config.Interval < 0 || config.Timeout < 0 || config.HealthyThreshold < 0 || config.UnhealthyThreshold < 0 {
+		return errors.New("the values of healthCheck must not be negative")
+	}
+	return nil
+}
+
+type Checker struct {
+	config    Config
+	client    client.Client
+	upstreams []*upstream.Upstream
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+func NewChecker(config Config, client client.Client, upstreams []*upstream.Upstream) *Checker {
+	return &Checker{config: config.WithDefaults(), client: client, upstreams: upstreams}
+}
+
+// Start checks the upstreams right away and then every interval until Stop is called
+func (c *Checker) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go func() {
+		ticker := time.NewTicker(c.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			c.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *Checker) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// Check checks all upstreams concurrently once and reports the results to them
+func (c *Checker) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, u := range c.upstreams {
+		wg.Add(1)
+		go func(u *upstream.Upstream) {
+			defer wg.Done()
+			c.check(ctx, u)
+		}(u)
+	}
+	wg.Wait()
+}
+
+func (c *Checker) check(ctx context.Context, u *upstream.Upstream) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	err := health.HTTP(c.client, u.Url.JoinPath(c.config.Path).String())(ctx)
+	if ctx.Err() == context.Canceled {
+		// the checker was stopped, which says nothing about the upstream
+		return
+	}
+	if err != nil {
+		slog.Debug("health check failed", "upstream", u.Url.Host, "error", err)
+	}
+	u.ReportCheck(err == nil, c.config.HealthyThreshold, c.config.UnhealthyThreshold)
+}
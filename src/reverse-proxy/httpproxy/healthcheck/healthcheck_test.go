@@ -0,0 +1,92 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T, healthy *atomic.Bool, checks *atomic.Int32) *upstream.Upstream {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		if r.URL.Path != "/api/health/ready" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	URL, _ := url.Parse(server.URL + "/api")
+	return upstream.NewUpstream(URL, 1)
+}
+
+func TestChecker(t *testing.T) {
+	t.Run("Check", func(t *testing.T) {
+		t.Run("should mark the upstreams by the thresholds", func(t *testing.T) {
+			// given
+			var healthy atomic.Bool
+			var checks atomic.Int32
+			u := newTestServer(t, &healthy, &checks)
+			checker := NewChecker(Config{HealthyThreshold: 1, UnhealthyThreshold: 2}, http.DefaultClient, []*upstream.Upstream{u})
+
+			// when
+			checker.Check(context.Background())
+			beforeThreshold := u.Available()
+			checker.Check(context.Background())
+			unhealthy := u.Available()
+			healthy.Store(true)
+			checker.Check(context.Background())
+
+			// then
+			assert.True(t, beforeThreshold)
+			assert.False(t, unhealthy)
+			assert.True(t, u.Available())
+			assert.Equal(t, int32(3), checks.Load())
+		})
+
+		t.Run("should fail the check after the timeout", func(t *testing.T) {
+			// given
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			}))
+			defer server.Close()
+			URL, _ := url.Parse(server.URL)
+			u := upstream.NewUpstream(URL, 1)
+			checker := NewChecker(Config{Timeout: 10 * time.Millisecond, UnhealthyThreshold: 1}, http.DefaultClient, []*upstream.Upstream{u})
+
+			// when
+			checker.Check(context.Background())
+
+			// then
+			assert.False(t, u.Available())
+		})
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		t.Run("should check every interval until it is stopped", func(t *testing.T) {
+			// given
+			var healthy atomic.Bool
+			var checks atomic.Int32
+			u := newTestServer(t, &healthy, &checks)
+			checker := NewChecker(Config{Interval: 10 * time.Millisecond}, http.DefaultClient, []*upstream.Upstream{u})
+
+			// when
+			checker.Start()
+			assert.Eventually(t, func() bool { return checks.Load() >= 3 }, time.Second, 5*time.Millisecond)
+			checker.Stop()
+			time.Sleep(20 * time.Millisecond)
+			stopped := checks.Load()
+			time.Sleep(50 * time.Millisecond)
+
+			// then
+			assert.Equal(t, stopped, checks.Load())
+			assert.False(t, u.Available())
+		})
+	})
+}
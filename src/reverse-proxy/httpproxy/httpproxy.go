@@ -1,17 +1,17 @@
 package httpproxy
 
 import (
-	"fmt"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
-	"io"
-	"log"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/cache"
+	"log/slog"
+	"net"
 	"net/http"
 	"strings"
 )
 
 type HTTPProxy struct {
-	client   client.Client
-	Mappings []*RouteMapping
+	client client.Client
+	routes
 }
 
 func NewHTTPProxy(client client.Client) *HTTPProxy {
@@ -19,7 +19,7 @@ func NewHTTPProxy(client client.Client) *HTTPProxy {
 }
 
 func (p *HTTPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	for _, mapping := range p.Mappings {
+	for _, mapping := range p.Mappings() {
 		hostMatches := mapping.host.FindAllStringSubmatch(r.Host, -1)
 
 		if len(hostMatches) < 1 {
@@ -32,37 +32,36 @@ func (p *HTTPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		host := mapping.hosts[mapping.hostIndex]
-		log.Printf("Got a connection from %s to %s: Redirect to %s\n", r.RemoteAddr, r.Host, host.Host)
-
-		r.Header.Set("X-Forwarded-For", strings.Split(r.RemoteAddr, ":")[0])
+		id := requestID(r)
+		// the token is verified first, so the rate limit can use its user
+		if !mapping.authenticate(w, r, id) || !mapping.allow(w, r, id) {
+			return
+		}
+		if mapping.cache != nil {
+			r = cache.WithClientURL(r)
+		}
+		prepareHopByHopHeaders(r)
+		setForwardedFor(r)
 		r.Header.Set("X-Forwarded-Host", r.Host)
-		r.URL.Host = host.Host
-		r.URL.Scheme = host.Scheme
-		r.URL.Path = host.Path + r.URL.Path
 		r.RequestURI = ""
+		mapping.rewriteRequest(r)
 
-		originServerResponse, err := p.client.Do(r)
+		originServerResponse, err := mapping.forward(r, p.client.Do)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			_, _ = fmt.Fprint(w, err)
-			mapping.hostIndex = (mapping.hostIndex + 1) % len(mapping.hosts)
-			log.Println(err.Error())
+			writeError(w, r, id, mapping, err)
 			return
 		}
+		mapping.rewriteResponse(originServerResponse)
 
-		for headerKey, headerValue := range originServerResponse.Header {
-			for _, value := range headerValue {
-				w.Header().Add(headerKey, value)
-			}
+		if originServerResponse.StatusCode == http.StatusSwitchingProtocols {
+			serveSwitchedProtocols(w, r, id, mapping, originServerResponse)
+			return
 		}
 
-		w.WriteHeader(originServerResponse.StatusCode)
-
-		io.Copy(w, originServerResponse.Body)
-
-		// Round-robin
-		mapping.hostIndex = (mapping.hostIndex + 1) % len(mapping.hosts)
+		defer originServerResponse.Body.Close()
+		if err := copyResponse(w, originServerResponse); err != nil {
+			slog.WarnContext(r.Context(), "could not copy the response", "request_id", id, "error", err)
+		}
 		return
 	}
 
@@ -70,6 +69,14 @@ func (p *HTTPProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func (p *HTTPProxy) Append(mapping *RouteMapping) {
-	p.Mappings = append(p.Mappings, mapping)
+// setForwardedFor appends the client IP to the X-Forwarded-For chain of the previous proxies like httputil.ReverseProxy
+func setForwardedFor(r *http.Request) {
+	clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return
+	}
+	if prior := r.Header.Values("X-Forwarded-For"); len(prior) > 0 {
+		clientIP = strings.Join(prior, ", ") + ", " + clientIP
+	}
+	r.Header.Set("X-Forwarded-For", clientIP)
 }
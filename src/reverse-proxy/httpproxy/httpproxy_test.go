@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"io"
@@ -84,7 +85,8 @@ func TestHTTPProxy(t *testing.T) {
 		proxy.ServeHTTP(w, r)
 
 		// then
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.NotContains(t, w.Body.String(), "got an Error")
 	})
 
 	t.Run("should call the client because it matched the path", func(t *testing.T) {
@@ -115,6 +117,26 @@ func TestHTTPProxy(t *testing.T) {
 		assert.Equal(t, "example.com", r.Header.Get("X-Forwarded-Host"))
 	})
 
+	t.Run("should append the client IP to the X-Forwarded-For chain", func(t *testing.T) {
+		// given
+		proxy := NewHTTPProxy(client)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000"})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+		r.RemoteAddr = "[2001:db8::1]:4711"
+		r.Header.Add("X-Forwarded-For", "198.51.100.1, 198.51.100.2")
+		r.Header.Add("X-Forwarded-For", "198.51.100.3")
+
+		// when
+		client.EXPECT().Do(r).Return(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil)
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"198.51.100.1, 198.51.100.2, 198.51.100.3, 2001:db8::1"}, r.Header.Values("X-Forwarded-For"))
+	})
+
 	t.Run("should copy all header values from the server", func(t *testing.T) {
 		// given
 		proxy := NewHTTPProxy(client)
@@ -234,4 +256,51 @@ func TestHTTPProxy(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, "/append/the/route", r.URL.Path)
 	})
+
+	t.Run("should eject an upstream after consecutive errors", func(t *testing.T) {
+		// given
+		proxy := NewHTTPProxy(client)
+		mapping, _ := NewRouteMapping(MappingConfig{
+			Host:             "*",
+			Path:             "/the/route",
+			Hosts:            []string{"http://new-host:3000", "http://second-host:8000"},
+			OutlierDetection: &upstream.OutlierDetection{ConsecutiveFailures: 1},
+		}, Dependencies{})
+		proxy.Append(mapping)
+
+		client.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Host == "new-host:3000" {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+		}).Times(3)
+
+		// when
+		codes := make([]int, 3)
+		for i := range codes {
+			w := httptest.NewRecorder()
+			proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+			codes[i] = w.Code
+		}
+
+		// then
+		assert.Equal(t, []int{http.StatusBadGateway, http.StatusOK, http.StatusOK}, codes)
+		assert.True(t, mapping.upstreams[0].State().Ejected)
+	})
+
+	t.Run("should return 503 SERVICE UNAVAILABLE if no upstream is available", func(t *testing.T) {
+		// given
+		proxy := NewHTTPProxy(client)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000"})
+		proxy.Mappings()[0].upstreams[0].ReportCheck(false, 1, 1)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
 }
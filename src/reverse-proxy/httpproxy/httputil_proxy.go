@@ -1,14 +1,15 @@
 package httpproxy
 
 import (
-	"log"
 	"net/http"
 	"net/http/httputil"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/cache"
 )
 
 type HTTPUtilProxy struct {
 	roundTripper http.RoundTripper
-	Mappings     []*RouteMapping
+	routes
 }
 
 func NewHTTPUtilProxy(roundTripper http.RoundTripper) *HTTPUtilProxy {
@@ -16,7 +17,7 @@ func NewHTTPUtilProxy(roundTripper http.RoundTripper) *HTTPUtilProxy {
 }
 
 func (p *HTTPUtilProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	for _, mapping := range p.Mappings {
+	for _, mapping := range p.Mappings() {
 		hostMatches := mapping.host.FindAllStringSubmatch(r.Host, -1)
 
 		if len(hostMatches) < 1 {
@@ -29,19 +30,34 @@ func (p *HTTPUtilProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		host := mapping.hosts[mapping.hostIndex]
-		log.Printf("Got a connection from %s to %s: Redirect to %s\n", r.RemoteAddr, r.Host, host.Host)
-
+		id := requestID(r)
+		// the token is verified first, so the rate limit can use its user
+		if !mapping.authenticate(w, r, id) || !mapping.allow(w, r, id) {
+			return
+		}
+		if mapping.cache != nil {
+			r = cache.WithClientURL(r)
+		}
 		reverseProxy := httputil.ReverseProxy{
+			// the URL is set by forward for every attempt
 			Rewrite: func(r *httputil.ProxyRequest) {
-				r.SetURL(host)
 				r.Out.Host = r.In.Host
+				// SetXForwarded only appends to the chain of the previous proxies, if it is copied to the outgoing request
+				r.Out.Header["X-Forwarded-For"] = r.In.Header["X-Forwarded-For"]
 				r.SetXForwarded()
+				mapping.rewriteRequest(r.Out)
+			},
+			Transport: roundTripFunc(func(out *http.Request) (*http.Response, error) {
+				return mapping.forward(out, p.roundTripper.RoundTrip)
+			}),
+			ModifyResponse: func(res *http.Response) error {
+				mapping.rewriteResponse(res)
+				return nil
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				writeError(w, r, id, mapping, err)
 			},
-			Transport: p.roundTripper,
 		}
-
-		mapping.hostIndex = (mapping.hostIndex + 1) % len(mapping.hosts)
 		reverseProxy.ServeHTTP(w, r)
 		return
 	}
@@ -49,7 +65,3 @@ func (p *HTTPUtilProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotFound)
 	return
 }
-
-func (p *HTTPUtilProxy) Append(mapping *RouteMapping) {
-	p.Mappings = append(p.Mappings, mapping)
-}
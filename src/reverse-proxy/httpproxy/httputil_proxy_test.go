@@ -3,7 +3,9 @@ package httpproxy
 import (
 	"bytes"
 	"errors"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
 	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"io"
@@ -117,6 +119,27 @@ func TestHTTPUtilProxy(t *testing.T) {
 		assert.Equal(t, response.Status, w.Result().Status)
 	})
 
+	t.Run("should append the client IP to the X-Forwarded-For chain", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000"})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+		r.RemoteAddr = "[2001:db8::1]:4711"
+		r.Header.Add("X-Forwarded-For", "198.51.100.1, 198.51.100.2")
+		r.Header.Add("X-Forwarded-For", "198.51.100.3")
+
+		// when
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil).Do(func(r *http.Request) {
+			assert.Equal(t, []string{"198.51.100.1, 198.51.100.2, 198.51.100.3, 2001:db8::1"}, r.Header.Values("X-Forwarded-For"))
+		})
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("should copy all header values from the server", func(t *testing.T) {
 		// given
 		proxy := NewHTTPUtilProxy(roundTripper)
@@ -237,4 +260,81 @@ func TestHTTPUtilProxy(t *testing.T) {
 		// then
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("should forward the request id or generate one", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000"})
+
+		response := &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}
+
+		var ids []string
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(response, nil).Do(func(r *http.Request) {
+			ids = append(ids, r.Header.Get(logger.RequestIDHeader))
+		}).Times(2)
+
+		withID := httptest.NewRequest("GET", "/the/route", nil)
+		withID.Header.Set(logger.RequestIDHeader, "my-id")
+
+		// when
+		proxy.ServeHTTP(httptest.NewRecorder(), withID)
+		proxy.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/the/route", nil))
+
+		// then
+		assert.Equal(t, "my-id", ids[0])
+		assert.Len(t, ids[1], 32)
+	})
+
+	t.Run("should eject an upstream after consecutive 5xx responses", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		mapping, _ := NewRouteMapping(MappingConfig{
+			Host:             "*",
+			Path:             "/the/route",
+			Hosts:            []string{"http://new-host:3000", "http://second-host:8000"},
+			OutlierDetection: &upstream.OutlierDetection{ConsecutiveFailures: 1},
+		}, Dependencies{})
+		proxy.Append(mapping)
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			statusCode := http.StatusOK
+			if r.URL.Host == "new-host:3000" {
+				statusCode = http.StatusServiceUnavailable
+			}
+			return &http.Response{StatusCode: statusCode, Header: http.Header{}, Body: http.NoBody}, nil
+		}).Times(3)
+
+		// when
+		codes := make([]int, 3)
+		for i := range codes {
+			w := httptest.NewRecorder()
+			proxy.ServeHTTP(w, httptest.NewRequest("GET", "/the/route", nil))
+			codes[i] = w.Code
+		}
+
+		// then
+		assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK}, codes)
+		assert.True(t, mapping.upstreams[0].State().Ejected)
+	})
+
+	t.Run("should return 503 SERVICE UNAVAILABLE if no upstream is available", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000"})
+		proxy.Mappings()[0].upstreams[0].ReportCheck(false, 1, 1)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
 }
@@ -2,27 +2,192 @@ package httpproxy
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"sync/atomic"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/auth"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/cache"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/circuitbreaker"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/healthcheck"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/ratelimit"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/retry"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/strategy"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
 )
 
+// MappingConfig is a mapping in the config file of the proxy
+type MappingConfig struct {
+	Host  string   `yaml:"host" json:"host"`
+	Path  string   `yaml:"path" json:"path"`
+	Hosts []string `yaml:"hosts" json:"hosts"`
+	// Weights of the hosts for weighted-round-robin and consistent-hash, every host has weight 1 by default
+	Weights []int `yaml:"weights,omitempty" json:"weights,omitempty"`
+	// Strategy is round-robin (default), weighted-round-robin, random, least-inflight or consistent-hash
+	Strategy string `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	// HashHeader or HashCookie is the key of consistent-hash, without them the client IP is used
+	HashHeader string `yaml:"hashHeader,omitempty" json:"hashHeader,omitempty"`
+	HashCookie string `yaml:"hashCookie,omitempty" json:"hashCookie,omitempty"`
+	// HealthCheck checks the hosts actively, OutlierDetection ejects them after failed requests.
+	// Both are disabled, if they aren't set.
+	HealthCheck      *healthcheck.Config        `yaml:"healthCheck,omitempty" json:"healthCheck,omitempty"`
+	OutlierDetection *upstream.OutlierDetection `yaml:"outlierDetection,omitempty" json:"outlierDetection,omitempty"`
+	// Timeouts limit every attempt, Retry sends failed requests to another host and CircuitBreaker
+	// rejects the requests of a failing route for a while. They are disabled, if they aren't set.
+	Timeouts       *TimeoutConfig         `yaml:"timeouts,omitempty" json:"timeouts,omitempty"`
+	Retry          *retry.Config          `yaml:"retry,omitempty" json:"retry,omitempty"`
+	CircuitBreaker *circuitbreaker.Config `yaml:"circuitBreaker,omitempty" json:"circuitBreaker,omitempty"`
+	// StripPrefix is removed from the path and PrefixReplacement is put in its place,
+	// Rewrite replaces the path with a pattern instead
+	StripPrefix       string         `yaml:"stripPrefix,omitempty" json:"stripPrefix,omitempty"`
+	PrefixReplacement string         `yaml:"prefixReplacement,omitempty" json:"prefixReplacement,omitempty"`
+	Rewrite           *RewriteConfig `yaml:"rewrite,omitempty" json:"rewrite,omitempty"`
+	RequestHeaders    *HeaderRules   `yaml:"requestHeaders,omitempty" json:"requestHeaders,omitempty"`
+	ResponseHeaders   *HeaderRules   `yaml:"responseHeaders,omitempty" json:"responseHeaders,omitempty"`
+	// UpstreamHost is sent as Host header instead of the host of the request
+	UpstreamHost string `yaml:"upstreamHost,omitempty" json:"upstreamHost,omitempty"`
+	// TLS configures the connections to https-hosts, H2C sends HTTP/2 without TLS to http-hosts
+	TLS *UpstreamTLSConfig `yaml:"tls,omitempty" json:"tls,omitempty"`
+	H2C bool               `yaml:"h2c,omitempty" json:"h2c,omitempty"`
+	// RateLimit rejects the requests over the limit with 429, it is disabled, if it isn't set
+	RateLimit *ratelimit.Config `yaml:"rateLimit,omitempty" json:"rateLimit,omitempty"`
+	// Cache stores the responses of GET requests, it is disabled, if it isn't set
+	Cache *cache.Config `yaml:"cache,omitempty" json:"cache,omitempty"`
+	// Auth verifies the bearer tokens and forwards their claims, it is required, optional or disabled, if it isn't set
+	Auth string `yaml:"auth,omitempty" json:"auth,omitempty"`
+}
+
+// Dependencies are shared by the mappings, a mapping using a missing one is rejected
+type Dependencies struct {
+	// RateLimitStore keeps the buckets of the rate limits, it is kept on reloads
+	RateLimitStore ratelimit.Store
+	// CacheStore keeps the responses of the caches, it is kept on reloads
+	CacheStore *cache.Store
+	// Verifier checks the tokens of the mappings with auth, it is created again on reloads
+	Verifier *auth.Verifier
+}
+
 type RouteMapping struct {
-	hostIndex int
+	config    MappingConfig
 	host      *regexp.Regexp
 	path      *regexp.Regexp
-	hosts     []*url.URL
+	upstreams []*upstream.Upstream
+	strategy  strategy.Strategy
+	checker   *healthcheck.Checker
+	rewrite   *regexp.Regexp
+	// transport is nil, if the mapping uses the transport of the proxy
+	transport             http.RoundTripper
+	instrumentedTransport http.RoundTripper
+	// retryPolicy, breaker, limiter, cache and authenticator are nil, if they aren't configured
+	retryPolicy   *retry.Policy
+	breaker       *circuitbreaker.Breaker
+	limiter       *ratelimit.Limiter
+	cache         *cache.Cache
+	authenticator *auth.Authenticator
+}
+
+// Config returns the configuration, from which the mapping was created
+func (mapping *RouteMapping) Config() MappingConfig {
+	return mapping.config
+}
+
+// StartHealthChecks starts the active health checks of the mapping, if they are configured
+func (mapping *RouteMapping) StartHealthChecks() {
+	if mapping.checker != nil {
+		mapping.checker.Start()
+	}
+}
+
+func (mapping *RouteMapping) StopHealthChecks() {
+	if mapping.checker != nil {
+		mapping.checker.Stop()
+	}
 }
 
 type Proxy interface {
 	ServeHTTP(http.ResponseWriter, *http.Request)
 	Append(*RouteMapping)
+	Replace([]*RouteMapping)
+	Mappings() []*RouteMapping
+}
+
+// routes holds the mappings of a proxy. They are only replaced as a whole,
+// so a request is served by the mappings, which were active when it arrived.
+type routes struct {
+	mappings atomic.Pointer[[]*RouteMapping]
+}
+
+func (r *routes) Mappings() []*RouteMapping {
+	mappings := r.mappings.Load()
+	if mappings == nil {
+		return nil
+	}
+	return *mappings
+}
+
+// Append adds a mapping while the proxy is set up, it must not be called concurrently
+func (r *routes) Append(mapping *RouteMapping) {
+	mappings := append(slices.Clone(r.Mappings()), mapping)
+	r.mappings.Store(&mappings)
+}
+
+// Replace swaps all mappings at once, the old ones aren't modified
+func (r *routes) Replace(mappings []*RouteMapping) {
+	mappings = slices.Clone(mappings)
+	r.mappings.Store(&mappings)
+}
+
+// requestID makes sure the request has an id, which is forwarded to the upstream, so its logs can be correlated
+func requestID(r *http.Request) string {
+	id := r.Header.Get(logger.RequestIDHeader)
+	if id == "" || len(id) > 128 {
+		id = logger.NewRequestID()
+		r.Header.Set(logger.RequestIDHeader, id)
+	}
+	return id
+}
+
+func logForward(r *http.Request, id string, upstream *url.URL) {
+	slog.InfoContext(r.Context(), "forwarding request",
+		"request_id", id,
+		"remote_addr", r.RemoteAddr,
+		"host", r.Host,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"upstream", upstream.Host,
+	)
+}
+
+// reportRequest records the result of a proxied request for the outlier detection of the upstream.
+// Requests canceled by the client say nothing about the upstream.
+func reportRequest(r *http.Request, selected *upstream.Upstream, statusCode int, err error) {
+	if r.Context().Err() != nil {
+		return
+	}
+	selected.ReportRequest(err == nil && statusCode < http.StatusInternalServerError)
 }
 
 func AddToProxy(p Proxy, host string, path string, hosts []string) error {
+	mapping, err := NewRouteMapping(MappingConfig{Host: host, Path: path, Hosts: hosts}, Dependencies{})
+	if err != nil {
+		return err
+	}
+
+	p.Append(mapping)
+	return nil
+}
+
+func NewRouteMapping(config MappingConfig, dependencies Dependencies) (*RouteMapping, error) {
+	host, path := config.Host, config.Path
+
 	wildcardMatcher := regexp.MustCompile("(\\*)")
 	wildcardHostMatches := wildcardMatcher.FindAllStringSubmatch(host, -1)
-	wildcardPathMatches := wildcardMatcher.FindAllStringSubmatch(host, -1)
+	wildcardPathMatches := wildcardMatcher.FindAllStringSubmatch(path, -1)
 
 	if len(wildcardHostMatches) > 0 {
 		host = wildcardMatcher.ReplaceAllLiteralString(host, "([^\\.]*)")
@@ -32,23 +197,188 @@ func AddToProxy(p Proxy, host string, path string, hosts []string) error {
 		path = wildcardMatcher.ReplaceAllLiteralString(path, "([^/]*)")
 	}
 
-	hostPattern := regexp.MustCompile(host)
-	pathPattern := regexp.MustCompile(path)
+	hostPattern, err := regexp.Compile(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host pattern: %w", err)
+	}
+	pathPattern, err := regexp.Compile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern: %w", err)
+	}
+
+	if len(config.Hosts) < 1 {
+		return nil, errors.New("there was no host provided")
+	}
 
-	if len(hosts) < 1 {
-		return errors.New("there was no host provided")
+	rewrite, err := newRewrite(config, pathPattern)
+	if err != nil {
+		return nil, err
 	}
 
-	var urls []*url.URL
-	for _, hostAddr := range hosts {
+	if len(config.Weights) > 0 && len(config.Weights) != len(config.Hosts) {
+		return nil, errors.New("there must be one weight per host")
+	}
+
+	if config.HealthCheck != nil {
+		if err := config.HealthCheck.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.OutlierDetection != nil {
+		if err := config.OutlierDetection.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.Timeouts != nil {
+		if err := config.Timeouts.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.Retry != nil {
+		if err := config.Retry.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.CircuitBreaker != nil {
+		if err := config.CircuitBreaker.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.RateLimit != nil {
+		if err := config.RateLimit.Validate(); err != nil {
+			return nil, err
+		}
+		if dependencies.RateLimitStore == nil {
+			return nil, errors.New("rateLimit needs a store")
+		}
+		if config.RateLimit.Key == ratelimit.KeyUser && config.Auth == "" {
+			return nil, errors.New("the user key of rateLimit needs auth")
+		}
+	}
+	if config.Cache != nil {
+		if err := config.Cache.Validate(); err != nil {
+			return nil, err
+		}
+		if dependencies.CacheStore == nil {
+			return nil, errors.New("cache needs a store")
+		}
+	}
+
+	var upstreams []*upstream.Upstream
+	for i, hostAddr := range config.Hosts {
 		host, err := url.Parse(hostAddr)
 		if err != nil {
-			return errors.New("invalid origin server URL")
+			return nil, errors.New("invalid origin server URL")
+		}
+
+		weight := 1
+		if len(config.Weights) > 0 {
+			weight = config.Weights[i]
+		}
+		if weight < 1 {
+			return nil, errors.New("the weights must be at least 1")
 		}
-		urls = append(urls, host)
+		u := upstream.NewUpstream(host, weight)
+		u.Mapping = config.Host + config.Path
+		u.OutlierDetection = config.OutlierDetection
+		upstreams = append(upstreams, u)
 	}
+	upstream.NewPool(upstreams)
 
-	p.Append(&RouteMapping{0, hostPattern, pathPattern, urls})
+	var authenticator *auth.Authenticator
+	if config.Auth != "" {
+		authenticator, err = auth.NewAuthenticator(config.Auth, dependencies.Verifier)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return nil
+	selection, err := newStrategy(config, upstreams)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var checker *healthcheck.Checker
+	if config.HealthCheck != nil {
+		checkClient := http.DefaultClient
+		if transport != nil {
+			checkClient = &http.Client{Transport: transport}
+		}
+		checker = healthcheck.NewChecker(*config.HealthCheck, checkClient, upstreams)
+	}
+
+	mapping := &RouteMapping{
+		config:                config,
+		host:                  hostPattern,
+		path:                  pathPattern,
+		upstreams:             upstreams,
+		strategy:              selection,
+		checker:               checker,
+		rewrite:               rewrite,
+		transport:             transport,
+		instrumentedTransport: instrumented(transport),
+		authenticator:         authenticator,
+	}
+	if config.Retry != nil {
+		mapping.retryPolicy = retry.NewPolicy(*config.Retry)
+	}
+	if config.CircuitBreaker != nil {
+		mapping.breaker = circuitbreaker.NewBreaker(*config.CircuitBreaker, config.Host+config.Path)
+	}
+	if config.RateLimit != nil {
+		mapping.limiter = ratelimit.NewLimiter(*config.RateLimit, config.Host+config.Path, dependencies.RateLimitStore)
+	}
+	if config.Cache != nil {
+		mapping.cache = cache.NewCache(*config.Cache, dependencies.CacheStore)
+	}
+	return mapping, nil
+}
+
+func newStrategy(config MappingConfig, upstreams []*upstream.Upstream) (strategy.Strategy, error) {
+	switch config.Strategy {
+	case strategy.RoundRobin, "":
+		return strategy.NewRoundRobinStrategy(upstreams), nil
+	case strategy.WeightedRoundRobin:
+		return strategy.NewWeightedRoundRobinStrategy(upstreams), nil
+	case strategy.Random:
+		return strategy.NewRandomStrategy(upstreams), nil
+	case strategy.LeastInFlight:
+		return strategy.NewLeastInFlightStrategy(upstreams), nil
+	case strategy.ConsistentHash:
+		if config.HashHeader != "" && config.HashCookie != "" {
+			return nil, errors.New("consistent-hash can either use hashHeader or hashCookie")
+		}
+		key := strategy.HeaderKey(config.HashHeader)
+		if config.HashCookie != "" {
+			key = strategy.CookieKey(config.HashCookie)
+		}
+		return strategy.NewConsistentHashStrategy(upstreams, key), nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", config.Strategy)
+	}
+}
+
+// NewRouteMappings creates all mappings or returns the problems of all invalid ones,
+// so a reload either replaces the mappings completely or not at all
+func NewRouteMappings(configs []MappingConfig, dependencies Dependencies) ([]*RouteMapping, error) {
+	mappings := make([]*RouteMapping, 0, len(configs))
+	var errs []error
+	for i, config := range configs {
+		mapping, err := NewRouteMapping(config, dependencies)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mapping %d (%s%s): %w", i, config.Host, config.Path, err))
+			continue
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return mappings, nil
 }
@@ -0,0 +1,269 @@
+package httpproxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/circuitbreaker"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/healthcheck"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/retry"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/strategy"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewRouteMappings(t *testing.T) {
+	t.Run("should create all mappings", func(t *testing.T) {
+		// given
+		configs := []MappingConfig{
+			{Host: "*", Path: "/api/v1/books*", Hosts: []string{"http://book:8080"}},
+			{Host: "*", Path: "/*", Hosts: []string{"http://web:8080"}},
+		}
+
+		// when
+		mappings, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, mappings, 2)
+		assert.Equal(t, configs[0], mappings[0].Config())
+		assert.Equal(t, configs[1], mappings[1].Config())
+	})
+
+	t.Run("should report every invalid mapping", func(t *testing.T) {
+		// given
+		configs := []MappingConfig{
+			{Host: "*", Path: "/api/v1/books*", Hosts: []string{}},
+			{Host: "*", Path: "/ok", Hosts: []string{"http://web:8080"}},
+			{Host: "*", Path: "/api/v1/(", Hosts: []string{"http://web:8080"}},
+		}
+
+		// when
+		mappings, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.Nil(t, mappings)
+		assert.ErrorContains(t, err, "mapping 0 (*/api/v1/books*): there was no host provided")
+		assert.ErrorContains(t, err, "mapping 2 (*/api/v1/(): invalid path pattern")
+	})
+
+	t.Run("should create the strategy of the mapping", func(t *testing.T) {
+		// given
+		configs := []MappingConfig{
+			{Path: "/a", Hosts: []string{"http://a:8080"}},
+			{Path: "/b", Hosts: []string{"http://a:8080", "http://b:8080"}, Weights: []int{2, 1}, Strategy: strategy.WeightedRoundRobin},
+			{Path: "/c", Hosts: []string{"http://a:8080"}, Strategy: strategy.Random},
+			{Path: "/d", Hosts: []string{"http://a:8080"}, Strategy: strategy.LeastInFlight},
+			{Path: "/e", Hosts: []string{"http://a:8080"}, Strategy: strategy.ConsistentHash, HashCookie: "session"},
+		}
+
+		// when
+		mappings, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.NoError(t, err)
+		assert.IsType(t, &strategy.RoundRobinStrategy{}, mappings[0].strategy)
+		assert.IsType(t, &strategy.WeightedRoundRobinStrategy{}, mappings[1].strategy)
+		assert.Equal(t, 2, mappings[1].upstreams[0].Weight)
+		assert.IsType(t, &strategy.RandomStrategy{}, mappings[2].strategy)
+		assert.IsType(t, &strategy.LeastInFlightStrategy{}, mappings[3].strategy)
+		assert.IsType(t, &strategy.ConsistentHashStrategy{}, mappings[4].strategy)
+	})
+
+	t.Run("should reject invalid strategies and weights", func(t *testing.T) {
+		// given
+		configs := []MappingConfig{
+			{Path: "/a", Hosts: []string{"http://a:8080"}, Strategy: "fastest"},
+			{Path: "/b", Hosts: []string{"http://a:8080", "http://b:8080"}, Weights: []int{1}},
+			{Path: "/c", Hosts: []string{"http://a:8080"}, Weights: []int{0}},
+			{Path: "/d", Hosts: []string{"http://a:8080"}, Strategy: strategy.ConsistentHash, HashHeader: "X-User-Id", HashCookie: "session"},
+		}
+
+		// when
+		_, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, `mapping 0 (/a): unknown strategy "fastest"`)
+		assert.ErrorContains(t, err, "mapping 1 (/b): there must be one weight per host")
+		assert.ErrorContains(t, err, "mapping 2 (/c): the weights must be at least 1")
+		assert.ErrorContains(t, err, "mapping 3 (/d): consistent-hash can either use hashHeader or hashCookie")
+	})
+
+	t.Run("should read the policies from YAML", func(t *testing.T) {
+		// given
+		var configs []MappingConfig
+		yaml.Unmarshal([]byte(`
+- path: /a
+  hosts: [http://a:8080]
+  healthCheck:
+    path: /health/live
+    interval: 5s
+  outlierDetection:
+    consecutiveFailures: 3
+    baseEjectionTime: 1m
+  timeouts:
+    connect: 500ms
+  retry:
+    attempts: 3
+  circuitBreaker:
+    openDuration: 10s
+`), &configs)
+
+		// when
+		mappings, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, &healthcheck.Config{Path: "/health/live", Interval: 5 * time.Second}, mappings[0].config.HealthCheck)
+		assert.Equal(t, &upstream.OutlierDetection{ConsecutiveFailures: 3, BaseEjectionTime: time.Minute}, mappings[0].upstreams[0].OutlierDetection)
+		assert.NotNil(t, mappings[0].checker)
+		assert.Equal(t, &TimeoutConfig{Connect: 500 * time.Millisecond}, mappings[0].config.Timeouts)
+		assert.Equal(t, 3, mappings[0].retryPolicy.Attempts())
+		assert.Equal(t, circuitbreaker.Closed, mappings[0].breaker.State())
+	})
+
+	t.Run("should reject invalid policies", func(t *testing.T) {
+		// given
+		configs := []MappingConfig{
+			{Path: "/a", Hosts: []string{"http://a:8080"}, HealthCheck: &healthcheck.Config{Interval: -time.Second}},
+			{Path: "/b", Hosts: []string{"http://a:8080"}, OutlierDetection: &upstream.OutlierDetection{ConsecutiveFailures: -1}},
+			{Path: "/c", Hosts: []string{"http://a:8080"}, Timeouts: &TimeoutConfig{Read: -time.Second}},
+			{Path: "/d", Hosts: []string{"http://a:8080"}, Retry: &retry.Config{Budget: 2}},
+			{Path: "/e", Hosts: []string{"http://a:8080"}, CircuitBreaker: &circuitbreaker.Config{FailureThreshold: -1}},
+			{Path: "/f", Hosts: []string{"http://a:8080"}, OutlierDetection: &upstream.OutlierDetection{MaxEjectionPercent: 150}},
+		}
+
+		// when
+		_, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "mapping 0 (/a): the values of healthCheck must not be negative")
+		assert.ErrorContains(t, err, "mapping 1 (/b): the values of outlierDetection must not be negative")
+		assert.ErrorContains(t, err, "mapping 2 (/c): the values of timeouts must not be negative")
+		assert.ErrorContains(t, err, "mapping 3 (/d): the budget of retry must be between 0 and 1")
+		assert.ErrorContains(t, err, "mapping 4 (/e): the values of circuitBreaker must not be negative")
+		assert.ErrorContains(t, err, "mapping 5 (/f): the maxEjectionPercent of outlierDetection must be between 0 and 100")
+	})
+}
+
+func TestUpstreamsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should show the health of the upstreams", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000", "http://second-host:8000"})
+		proxy.Mappings()[0].upstreams[1].ReportCheck(false, 1, 1)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/admin/upstreams", nil)
+
+		// when
+		NewUpstreamsHandler(proxy).ServeHTTP(w, r)
+
+		// then
+		var states []MappingState
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&states))
+		assert.Equal(t, []MappingState{{
+			Host: "*",
+			Path: "/the/route",
+			Upstreams: []upstream.State{
+				{Url: "http://new-host:3000", Weight: 1, Available: true, Healthy: true},
+				{Url: "http://second-host:8000", Weight: 1},
+			},
+		}}, states)
+	})
+
+	t.Run("should only allow GET", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/admin/upstreams", nil)
+
+		// when
+		NewUpstreamsHandler(proxy).ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestReplace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should serve with the new mappings after replacing them", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		AddToProxy(proxy, "*", "/old", []string{"http://old-host:3000"})
+		oldMappings := proxy.Mappings()
+
+		mappings, _ := NewRouteMappings([]MappingConfig{{Host: "*", Path: "/new", Hosts: []string{"http://new-host:3000"}}}, Dependencies{})
+
+		response := &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(response, nil).Do(func(r *http.Request) {
+			assert.Equal(t, "new-host:3000", r.URL.Host)
+		})
+
+		// when
+		proxy.Replace(mappings)
+
+		oldW := httptest.NewRecorder()
+		proxy.ServeHTTP(oldW, httptest.NewRequest("GET", "/old", nil))
+
+		newW := httptest.NewRecorder()
+		proxy.ServeHTTP(newW, httptest.NewRequest("GET", "/new", nil))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, oldW.Code)
+		assert.Equal(t, http.StatusOK, newW.Code)
+		assert.Equal(t, "/old", oldMappings[0].Config().Path)
+	})
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should select the upstreams of concurrent requests without races", func(t *testing.T) {
+		// given
+		proxy := NewHTTPUtilProxy(roundTripper)
+		AddToProxy(proxy, "*", "/the/route", []string{"http://new-host:3000", "http://second-host:8000"})
+
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+		}).Times(20)
+
+		// when
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				proxy.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/the/route", nil))
+			}()
+		}
+		wg.Wait()
+
+		// then
+		for _, u := range proxy.Mappings()[0].upstreams {
+			assert.Equal(t, int64(0), u.InFlight())
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package httpproxy
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+)
+
+// allow applies the rate limit of the mapping and answers a limited request with 429 Too Many Requests
+func (mapping *RouteMapping) allow(w http.ResponseWriter, r *http.Request, id string) bool {
+	if mapping.limiter == nil {
+		return true
+	}
+
+	result := mapping.limiter.Allow(r)
+	result.SetHeaders(w.Header())
+	if result.Allowed {
+		return true
+	}
+
+	route := mapping.config.Host + mapping.config.Path
+	slog.InfoContext(r.Context(), "rate limited the request", "request_id", id, "route", route, "remote_addr", r.RemoteAddr)
+	metrics.ObserveRateLimited(route)
+	w.WriteHeader(http.StatusTooManyRequests)
+	return false
+}
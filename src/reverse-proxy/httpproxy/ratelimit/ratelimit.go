@@ -0,0 +1,176 @@
+// Package ratelimit limits the requests of a route with token buckets per client, per header value, per user or for all clients.
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/auth"
+)
+
+// now is replaced by the tests to refill the buckets
+var now = time.Now
+
+const (
+	KeyClientIP = "ip"
+	KeyHeader   = "header"
+	KeyUser     = "user"
+	KeyGlobal   = "global"
+)
+
+type Config struct {
+	// Requests is the number of requests, which may be sent per period
+	Requests int `yaml:"requests" json:"requests"`
+	// Period is the time, in which the bucket is refilled completely. Long periods like 24h are quotas.
+	Period time.Duration `yaml:"period,omitempty" json:"period,omitempty"`
+	// Burst is the size of the bucket, the requests, which may be sent at once
+	Burst int `yaml:"burst,omitempty" json:"burst,omitempty"`
+	// Key selects the bucket of a request, by the client IP, the client IP and the value of the Header,
+	// the user of the verified token or one for all requests
+	Key    string `yaml:"key,omitempty" json:"key,omitempty"`
+	Header string `yaml:"header,omitempty" json:"header,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for the unset values
+func (config Config) WithDefaults() Config {
+	if config.Period == 0 {
+		config.Period = time.Second
+	}
+	if config.Burst == 0 {
+		config.Burst = config.Requests
+	}
+	if config.Key == "" {
+		config.Key = KeyClientIP
+	}
+	return config
+}
+
+func (config Config) Validate() error {
+	if config.Requests < 0 || config.Period < 0 || config.Burst < 0 {
+		return errors.New("the values of rateLimit must not be negative")
+	}
+	if config.Requests == 0 {
+		return errors.New("rateLimit needs requests")
+	}
+	switch config.Key {
+	case KeyClientIP, KeyUser, KeyGlobal, "":
+	case KeyHeader:
+		if config.Header == "" {
+			return errors.New("the header key of rateLimit needs a header")
+		}
+	default:
+		return fmt.Errorf("unknown rateLimit key %q", config.Key)
+	}
+	return nil
+}
+
+// Limit is the bucket of a key, a token is added every interval up to the burst
+type Limit struct {
+	Burst    int
+	Interval time.Duration
+}
+
+// Result is the state of the bucket of a request after taking a token
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket, it is 0, if the store failed
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+	// Policy describes the limit like the RateLimit-Policy header
+	Policy string
+}
+
+// SetHeaders sets the RateLimit headers and Retry-After, if the request was limited
+func (result Result) SetHeaders(header http.Header) {
+	if result.Limit == 0 {
+		return
+	}
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	header.Set("RateLimit-Policy", result.Policy)
+	if !result.Allowed {
+		header.Set("Retry-After", strconv.Itoa(max(seconds(result.RetryAfter), 1)))
+	}
+}
+
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+type Limiter struct {
+	config Config
+	route  string
+	limit  Limit
+	store  Store
+}
+
+// NewLimiter creates the limiter of a route with the buckets of the store. The buckets are kept
+// per route, so a limiter of a reloaded route continues with the buckets of the old one.
+func NewLimiter(config Config, route string, store Store) *Limiter {
+	config = config.WithDefaults()
+	interval := max(config.Period/time.Duration(config.Requests), time.Microsecond)
+	return &Limiter{
+		config: config,
+		route:  route,
+		limit:  Limit{Burst: config.Burst, Interval: interval},
+		store:  store,
+	}
+}
+
+// Allow takes a token from the bucket of the request. If the store fails, the request is allowed.
+func (limiter *Limiter) Allow(r *http.Request) Result {
+	allowed, tokens, err := limiter.store.Take(r.Context(), limiter.route+"|"+limiter.key(r), limiter.limit)
+	if err != nil {
+		slog.WarnContext(r.Context(), "could not take a token, the request is allowed", "route", limiter.route, "error", err)
+		return Result{Allowed: true}
+	}
+
+	interval := float64(limiter.limit.Interval)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limiter.limit.Burst,
+		Remaining: int(tokens),
+		Reset:     time.Duration((float64(limiter.limit.Burst) - tokens) * interval),
+		Policy:    fmt.Sprintf("%d;w=%d;burst=%d", limiter.config.Requests, seconds(limiter.config.Period), limiter.config.Burst),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+	return result
+}
+
+// key selects the bucket of the request. The user is the id of the token, which was verified by the auth
+// of the mapping. The header only separates the clients of an IP, since a client chooses its value, and
+// it is hashed, so tokens aren't kept in the store. Requests without a user or the header are limited
+// by their client IP.
+func (limiter *Limiter) key(r *http.Request) string {
+	switch limiter.config.Key {
+	case KeyGlobal:
+		return KeyGlobal
+	case KeyUser:
+		if id := r.Header.Get(auth.UserIDHeader); id != "" {
+			return KeyUser + ":" + id
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if value := r.Header.Get(limiter.config.Header); limiter.config.Key == KeyHeader && value != "" {
+		hash := sha256.Sum256([]byte(value))
+		return KeyHeader + ":" + ip + ":" + hex.EncodeToString(hash[:16])
+	}
+	return KeyClientIP + ":" + ip
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setNow moves the clock of the package to the returned time
+func setNow(t *testing.T) *time.Time {
+	current := time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+	return &current
+}
+
+// newLimiter creates a limiter with its own memory store
+func newLimiter(config Config) *Limiter {
+	return NewLimiter(config, "/route", NewMemoryStore())
+}
+
+func request(remoteAddr string, header http.Header) *http.Request {
+	r := httptest.NewRequest("GET", "/route", nil)
+	r.RemoteAddr = remoteAddr
+	for name, values := range header {
+		r.Header[name] = values
+	}
+	return r
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (bool, float64, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("should apply the defaults", func(t *testing.T) {
+		// when
+		config := Config{Requests: 10}.WithDefaults()
+
+		// then
+		assert.Equal(t, Config{Requests: 10, Period: time.Second, Burst: 10, Key: KeyClientIP}, config)
+	})
+
+	t.Run("should reject invalid configs", func(t *testing.T) {
+		assert.ErrorContains(t, Config{}.Validate(), "rateLimit needs requests")
+		assert.ErrorContains(t, Config{Requests: 1, Burst: -1}.Validate(), "must not be negative")
+		assert.ErrorContains(t, Config{Requests: 1, Key: "cookie"}.Validate(), `unknown rateLimit key "cookie"`)
+		assert.ErrorContains(t, Config{Requests: 1, Key: KeyHeader}.Validate(), "needs a header")
+		assert.NoError(t, Config{Requests: 1, Key: KeyHeader, Header: "Authorization"}.Validate())
+	})
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("should limit the requests to the burst", func(t *testing.T) {
+		// given
+		setNow(t)
+		limiter := newLimiter(Config{Requests: 1, Period: 10 * time.Second, Burst: 2})
+		r := request("10.0.0.1:1234", nil)
+
+		// when
+		first, second, third := limiter.Allow(r), limiter.Allow(r), limiter.Allow(r)
+
+		// then
+		assert.True(t, first.Allowed)
+		assert.Equal(t, 1, first.Remaining)
+		assert.True(t, second.Allowed)
+		assert.Equal(t, 0, second.Remaining)
+		assert.Equal(t, 20*time.Second, second.Reset)
+		assert.False(t, third.Allowed)
+		assert.Equal(t, 10*time.Second, third.RetryAfter)
+	})
+
+	t.Run("should refill the bucket over the period", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		limiter := newLimiter(Config{Requests: 2, Period: time.Second})
+		r := request("10.0.0.1:1234", nil)
+		limiter.Allow(r)
+		limiter.Allow(r)
+
+		// when
+		limited := limiter.Allow(r)
+		*current = current.Add(500 * time.Millisecond)
+		refilled := limiter.Allow(r)
+
+		// then
+		assert.False(t, limited.Allowed)
+		assert.Equal(t, 500*time.Millisecond, limited.RetryAfter)
+		assert.True(t, refilled.Allowed)
+	})
+
+	t.Run("should keep a bucket per key", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			config    Config
+			other     *http.Request
+			separated bool
+		}{
+			{"client ip", Config{Requests: 1}, request("10.0.0.2:1234", nil), true},
+			{"same client ip", Config{Requests: 1}, request("10.0.0.1:5678", nil), false},
+			{"header", Config{Requests: 1, Key: KeyHeader, Header: "Authorization"}, request("10.0.0.1:1234", http.Header{"Authorization": {"Bearer other"}}), true},
+			{"header of another ip", Config{Requests: 1, Key: KeyHeader, Header: "Authorization"}, request("10.0.0.2:1234", http.Header{"Authorization": {"Bearer token"}}), true},
+			{"user", Config{Requests: 1, Key: KeyUser}, request("10.0.0.1:1234", http.Header{"X-User-Id": {"8"}}), true},
+			{"same user", Config{Requests: 1, Key: KeyUser}, request("10.0.0.2:1234", http.Header{"X-User-Id": {"7"}}), false},
+			{"global", Config{Requests: 1, Key: KeyGlobal}, request("10.0.0.2:1234", nil), false},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				// given
+				setNow(t)
+				limiter := newLimiter(test.config)
+				limiter.Allow(request("10.0.0.1:1234", http.Header{"Authorization": {"Bearer token"}, "X-User-Id": {"7"}}))
+
+				// when
+				result := limiter.Allow(test.other)
+
+				// then
+				assert.Equal(t, test.separated, result.Allowed)
+			})
+		}
+	})
+
+	t.Run("should not keep the header values in the keys", func(t *testing.T) {
+		// given
+		limiter := newLimiter(Config{Requests: 1, Key: KeyHeader, Header: "Authorization"})
+
+		// when
+		key := limiter.key(request("10.0.0.1:1234", http.Header{"Authorization": {"Bearer secret"}}))
+		fallback := limiter.key(request("10.0.0.1:1234", nil))
+
+		// then
+		assert.NotContains(t, key, "secret")
+		assert.Contains(t, key, "header:10.0.0.1:")
+		assert.Equal(t, "ip:10.0.0.1", fallback)
+	})
+
+	t.Run("should allow the requests, if the store fails", func(t *testing.T) {
+		// given
+		limiter := newLimiter(Config{Requests: 1})
+		limiter.store = failingStore{}
+
+		// when
+		result := limiter.Allow(request("10.0.0.1:1234", nil))
+
+		// then
+		assert.True(t, result.Allowed)
+	})
+}
+
+func TestResult(t *testing.T) {
+	t.Run("should set the RateLimit headers", func(t *testing.T) {
+		// given
+		header := http.Header{}
+		result := Result{Allowed: false, Limit: 5, Remaining: 0, Reset: 2500 * time.Millisecond, RetryAfter: 200 * time.Millisecond, Policy: "5;w=1;burst=5"}
+
+		// when
+		result.SetHeaders(header)
+
+		// then
+		assert.Equal(t, "5", header.Get("RateLimit-Limit"))
+		assert.Equal(t, "0", header.Get("RateLimit-Remaining"))
+		assert.Equal(t, "3", header.Get("RateLimit-Reset"))
+		assert.Equal(t, "5;w=1;burst=5", header.Get("RateLimit-Policy"))
+		assert.Equal(t, "1", header.Get("Retry-After"))
+	})
+
+	t.Run("should not set headers without a limit", func(t *testing.T) {
+		// given
+		header := http.Header{}
+
+		// when
+		Result{Allowed: true}.SetHeaders(header)
+
+		// then
+		assert.Empty(t, header)
+	})
+}
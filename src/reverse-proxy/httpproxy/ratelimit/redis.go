@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from the bucket atomically with the clock of the server,
+// so the replicas don't depend on their own clocks. The tokens are returned as a string,
+// because numbers returned by a script are truncated to integers, and the time in microseconds
+// is formatted, because tostring would round it.
+const takeScript = `
+if redis.replicate_commands then redis.replicate_commands() end
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) / interval)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', string.format('%.0f', now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * interval / 1000) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+// Evaler runs a Lua script on a Redis-compatible server
+type Evaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// redisClient adapts a go-redis client to an Evaler
+type redisClient struct {
+	client *redis.Client
+}
+
+func (client redisClient) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	return client.client.Eval(ctx, script, keys, args...).Result()
+}
+
+// RedisStore keeps the buckets in a Redis-compatible server, which is shared by the replicas.
+// A bucket expires, when it is full again.
+type RedisStore struct {
+	client Evaler
+}
+
+func NewRedisStore(client Evaler) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (store *RedisStore) Take(ctx context.Context, key string, limit Limit) (bool, float64, error) {
+	result, err := store.client.Eval(ctx, takeScript, []string{"ratelimit:" + key}, limit.Burst, limit.Interval.Microseconds())
+	if err != nil {
+		return false, 0, err
+	}
+
+	values, ok := result.([]any)
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected result of the rate limit script: %v", result)
+	}
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected result of the rate limit script: %v", result)
+	}
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("unexpected result of the rate limit script: %w", err)
+	}
+	return allowed == 1, tokens, nil
+}
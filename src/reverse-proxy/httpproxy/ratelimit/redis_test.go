@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type evalFunc func(ctx context.Context, script string, keys []string, args ...any) (any, error)
+
+func (fn evalFunc) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	return fn(ctx, script, keys, args...)
+}
+
+func TestRedisStore(t *testing.T) {
+	limit := Limit{Burst: 5, Interval: 200 * time.Millisecond}
+
+	t.Run("should run the script with the bucket", func(t *testing.T) {
+		// given
+		store := NewRedisStore(evalFunc(func(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+			assert.Equal(t, takeScript, script)
+			assert.Equal(t, []string{"ratelimit:/route|ip:10.0.0.1"}, keys)
+			assert.Equal(t, []any{5, int64(200000)}, args)
+			return []any{int64(1), "3.5"}, nil
+		}))
+
+		// when
+		allowed, tokens, err := store.Take(context.Background(), "/route|ip:10.0.0.1", limit)
+
+		// then
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, 3.5, tokens)
+	})
+
+	t.Run("should return the errors of the server", func(t *testing.T) {
+		// given
+		store := NewRedisStore(evalFunc(func(context.Context, string, []string, ...any) (any, error) {
+			return nil, errors.New("connection refused")
+		}))
+
+		// when
+		_, _, err := store.Take(context.Background(), "key", limit)
+
+		// then
+		assert.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("should reject unexpected results", func(t *testing.T) {
+		results := []any{"OK", []any{int64(1)}, []any{"1", "2"}, []any{int64(0), "tokens"}}
+
+		for _, result := range results {
+			// given
+			store := NewRedisStore(evalFunc(func(context.Context, string, []string, ...any) (any, error) {
+				return result, nil
+			}))
+
+			// when
+			_, _, err := store.Take(context.Background(), "key", limit)
+
+			// then
+			assert.ErrorContains(t, err, "unexpected result of the rate limit script")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sweepInterval is how often the memory store removes the full buckets
+const sweepInterval = time.Minute
+
+// Store keeps the token buckets. A shared store lets the replicas of the proxy limit the requests together.
+type Store interface {
+	// Take removes a token from the bucket of the key, if it has one, and returns the tokens, which are left
+	Take(ctx context.Context, key string, limit Limit) (bool, float64, error)
+}
+
+// StoreConfig selects the store of the rate limits, without a RedisAddr the buckets are kept in memory
+type StoreConfig struct {
+	RedisAddr     string `env:"REDIS_ADDR"`
+	RedisPassword string `env:"REDIS_PASSWORD" secret:"true"`
+	RedisDB       int    `env:"REDIS_DB" envDefault:"0" validate:"min=0"`
+	// RedisTimeout limits connecting to and every command of the Redis server, it is short,
+	// since the requests wait for it
+	RedisTimeout time.Duration `env:"REDIS_TIMEOUT" envDefault:"100ms"`
+}
+
+// NewStore creates the store of the config. The Redis server is only connected on the first request.
+func NewStore(config StoreConfig) Store {
+	if config.RedisAddr == "" {
+		return NewMemoryStore()
+	}
+	return NewRedisStore(redisClient{redis.NewClient(&redis.Options{
+		Addr:         config.RedisAddr,
+		Password:     config.RedisPassword,
+		DB:           config.RedisDB,
+		DialTimeout:  config.RedisTimeout,
+		ReadTimeout:  config.RedisTimeout,
+		WriteTimeout: config.RedisTimeout,
+	})})
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time
+}
+
+// MemoryStore keeps the buckets of a single proxy. A bucket is removed, when it is full again,
+// because a new bucket starts full as well.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, swept: now()}
+}
+
+func (store *MemoryStore) Take(_ context.Context, key string, limit Limit) (bool, float64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	current := now()
+	store.sweep(current)
+
+	burst := float64(limit.Burst)
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst}
+		store.buckets[key] = b
+	} else {
+		b.tokens = min(burst, b.tokens+float64(current.Sub(b.updated))/float64(limit.Interval))
+	}
+	b.updated = current
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.fullAt = current.Add(time.Duration((burst - b.tokens) * float64(limit.Interval)))
+	return allowed, b.tokens, nil
+}
+
+func (store *MemoryStore) sweep(current time.Time) {
+	if current.Sub(store.swept) < sweepInterval {
+		return
+	}
+	store.swept = current
+	for key, b := range store.buckets {
+		if !current.Before(b.fullAt) {
+			delete(store.buckets, key)
+		}
+	}
+}
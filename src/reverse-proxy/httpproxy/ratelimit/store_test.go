@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	limit := Limit{Burst: 2, Interval: time.Second}
+
+	t.Run("should start with a full bucket", func(t *testing.T) {
+		// given
+		setNow(t)
+		store := NewMemoryStore()
+
+		// when
+		allowed, tokens, err := store.Take(context.Background(), "a", limit)
+
+		// then
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, 1.0, tokens)
+	})
+
+	t.Run("should not refill over the burst", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		store := NewMemoryStore()
+		store.Take(context.Background(), "a", limit)
+
+		// when
+		*current = current.Add(time.Hour)
+		_, tokens, _ := store.Take(context.Background(), "a", limit)
+
+		// then
+		assert.Equal(t, 1.0, tokens)
+	})
+
+	t.Run("should remove the full buckets", func(t *testing.T) {
+		// given
+		current := setNow(t)
+		store := NewMemoryStore()
+		store.Take(context.Background(), "a", limit)
+		store.Take(context.Background(), "b", Limit{Burst: 2, Interval: time.Hour})
+
+		// when
+		*current = current.Add(sweepInterval)
+		store.Take(context.Background(), "c", limit)
+
+		// then
+		assert.NotContains(t, store.buckets, "a")
+		assert.Contains(t, store.buckets, "b")
+		assert.Contains(t, store.buckets, "c")
+	})
+}
+
+func TestNewStore(t *testing.T) {
+	t.Run("should keep the buckets in memory without a redis address", func(t *testing.T) {
+		assert.IsType(t, &MemoryStore{}, NewStore(StoreConfig{}))
+	})
+
+	t.Run("should share the buckets with redis", func(t *testing.T) {
+		// when
+		store := NewStore(StoreConfig{RedisAddr: "redis:6379", RedisTimeout: 50 * time.Millisecond})
+
+		// then
+		assert.IsType(t, &RedisStore{}, store)
+		options := store.(*RedisStore).client.(redisClient).client.Options()
+		assert.Equal(t, 50*time.Millisecond, options.DialTimeout)
+		assert.Equal(t, 50*time.Millisecond, options.ReadTimeout)
+	})
+}
@@ -0,0 +1,63 @@
+package httpproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should answer the requests over the limit with 429 TOO MANY REQUESTS", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts:     []string{"http://upstream:8080"},
+			RateLimit: &ratelimit.Config{Requests: 1, Period: time.Minute},
+		})
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(respondWith(http.StatusOK), nil).Times(1)
+
+		serve := func(remoteAddr string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/the/route", nil)
+			r.RemoteAddr = remoteAddr
+			proxy.ServeHTTP(w, r)
+			return w
+		}
+
+		// when
+		allowed := serve("192.0.2.1:1234")
+		limited := serve("192.0.2.1:1234")
+
+		// then
+		assert.Equal(t, http.StatusOK, allowed.Code)
+		assert.Equal(t, "1", allowed.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", allowed.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+		assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+		assert.Equal(t, "1;w=60;burst=1", limited.Header().Get("RateLimit-Policy"))
+	})
+
+	t.Run("should reject an invalid rate limit", func(t *testing.T) {
+		// when
+		_, err := NewRouteMapping(MappingConfig{Path: "/a", Hosts: []string{"http://a:8080"}, RateLimit: &ratelimit.Config{}}, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "rateLimit needs requests")
+	})
+
+	t.Run("should reject a rate limit without a store", func(t *testing.T) {
+		// when
+		_, err := NewRouteMapping(MappingConfig{Path: "/a", Hosts: []string{"http://a:8080"}, RateLimit: &ratelimit.Config{Requests: 1}}, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "rateLimit needs a store")
+	})
+}
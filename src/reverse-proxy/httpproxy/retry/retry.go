@@ -0,0 +1,115 @@
+// Package retry decides, if and when a failed request to an upstream is sent again.
+package retry
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRetries are allowed without earning them, so the budget doesn't prevent retries while there is little traffic
+const minRetries = 10
+
+type Config struct {
+	// Attempts are the retries after the first request, 0 disables them
+	Attempts *int `yaml:"attempts,omitempty" json:"attempts,omitempty"`
+	// Budget is the share of retries in the requests, 0.2 allows one retry per five requests
+	Budget float64 `yaml:"budget,omitempty" json:"budget,omitempty"`
+	// Backoff is the longest wait before the first retry, it doubles with every further retry up to MaxBackoff
+	Backoff    time.Duration `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+	MaxBackoff time.Duration `yaml:"maxBackoff,omitempty" json:"maxBackoff,omitempty"`
+}
+
+// WithDefaults returns the config with the defaults for the unset values
+func (config Config) WithDefaults() Config {
+	if config.Attempts == nil {
+		attempts := 2
+		config.Attempts = &attempts
+	}
+	if config.Budget == 0 {
+		config.Budget = 0.2
+	}
+	if config.Backoff == 0 {
+		config.Backoff = 25 * time.Millisecond
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = 250 * time.Millisecond
+	}
+	return config
+}
+
+func (config Config) Validate() error {
+	if (config.Attempts != nil && *config.Attempts < 0) || config.Backoff < 0 || config.MaxBackoff < 0 {
+		return errors.New("the values of retry must not be negative")
+	}
+	if config.Budget < 0 || config.Budget > 1 {
+		return errors.New("the budget of retry must be between 0 and 1")
+	}
+	return nil
+}
+
+// Policy is the retry policy of a mapping. Its budget is shared by all requests of the mapping,
+// so retries can't multiply the load on upstreams, which are already failing.
+type Policy struct {
+	config Config
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+func NewPolicy(config Config) *Policy {
+	return &Policy{config: config.WithDefaults(), tokens: minRetries}
+}
+
+func (policy *Policy) Attempts() int {
+	return *policy.config.Attempts
+}
+
+// Deposit adds the share of a request to the budget
+func (policy *Policy) Deposit() {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.tokens = min(policy.tokens+policy.config.Budget, minRetries)
+}
+
+// Withdraw takes a retry from the budget, it returns false if the budget is spent
+func (policy *Policy) Withdraw() bool {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	if policy.tokens < 1 {
+		return false
+	}
+	policy.tokens--
+	return true
+}
+
+// Backoff returns the time to wait before the retry, which starts at 1. It is jittered
+// between 0 and the backoff of the retry, so retries of concurrent requests are spread.
+func (policy *Policy) Backoff(retry int) time.Duration {
+	backoff := policy.config.Backoff << (retry - 1)
+	if backoff > policy.config.MaxBackoff || backoff <= 0 {
+		backoff = policy.config.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
+
+// Retryable reports if the request may be sent again. Only idempotent methods are retried
+// and only if their body can be sent again.
+func Retryable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
+
+// RetryableStatus reports if the status code of an upstream means, that another upstream may succeed
+func RetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable || statusCode == http.StatusGatewayTimeout
+}
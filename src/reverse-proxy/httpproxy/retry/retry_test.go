@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Run("Withdraw", func(t *testing.T) {
+		t.Run("should allow retries up to the budget", func(t *testing.T) {
+			// given
+			policy := NewPolicy(Config{Budget: 0.5})
+			for i := 0; i < minRetries; i++ {
+				policy.Withdraw()
+			}
+
+			// when
+			spent := policy.Withdraw()
+			policy.Deposit()
+			policy.Deposit()
+			earned := policy.Withdraw()
+			again := policy.Withdraw()
+
+			// then
+			assert.False(t, spent)
+			assert.True(t, earned)
+			assert.False(t, again)
+		})
+
+		t.Run("should not save more than the minimum retries", func(t *testing.T) {
+			// given
+			policy := NewPolicy(Config{Budget: 1})
+
+			// when
+			for i := 0; i < 100; i++ {
+				policy.Deposit()
+			}
+			allowed := 0
+			for policy.Withdraw() {
+				allowed++
+			}
+
+			// then
+			assert.Equal(t, minRetries, allowed)
+		})
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		t.Run("should jitter the doubled backoff up to the maximum", func(t *testing.T) {
+			// given
+			policy := NewPolicy(Config{Backoff: 10 * time.Millisecond, MaxBackoff: 30 * time.Millisecond})
+
+			for i := 0; i < 100; i++ {
+				// when
+				first := policy.Backoff(1)
+				second := policy.Backoff(2)
+				tenth := policy.Backoff(10)
+
+				// then
+				assert.Less(t, first, 10*time.Millisecond)
+				assert.Less(t, second, 20*time.Millisecond)
+				assert.Less(t, tenth, 30*time.Millisecond)
+				assert.GreaterOrEqual(t, first, time.Duration(0))
+			}
+		})
+	})
+}
+
+func TestRetryable(t *testing.T) {
+	t.Run("should only retry idempotent methods", func(t *testing.T) {
+		assert.True(t, Retryable(httptest.NewRequest("GET", "/", nil)))
+		assert.True(t, Retryable(httptest.NewRequest("DELETE", "/", nil)))
+		assert.False(t, Retryable(httptest.NewRequest("POST", "/", nil)))
+		assert.False(t, Retryable(httptest.NewRequest("PATCH", "/", nil)))
+	})
+
+	t.Run("should only retry a body, which can be sent again", func(t *testing.T) {
+		// given
+		incoming := httptest.NewRequest("PUT", "/", strings.NewReader("body"))
+		outgoing, _ := http.NewRequest("PUT", "/", strings.NewReader("body"))
+
+		// then
+		assert.False(t, Retryable(incoming))
+		assert.True(t, Retryable(outgoing))
+	})
+}
+
+func TestRetryableStatus(t *testing.T) {
+	assert.True(t, RetryableStatus(http.StatusBadGateway))
+	assert.True(t, RetryableStatus(http.StatusServiceUnavailable))
+	assert.True(t, RetryableStatus(http.StatusGatewayTimeout))
+	assert.False(t, RetryableStatus(http.StatusInternalServerError))
+	assert.False(t, RetryableStatus(http.StatusOK))
+}
+
+func TestWithDefaults(t *testing.T) {
+	disabled := 0
+
+	assert.Equal(t, 2, *Config{}.WithDefaults().Attempts)
+	assert.Equal(t, 0, *Config{Attempts: &disabled}.WithDefaults().Attempts)
+}
+
+func TestValidate(t *testing.T) {
+	negative := -1
+
+	assert.NoError(t, Config{}.Validate())
+	assert.EqualError(t, Config{Attempts: &negative}.Validate(), "the values of retry must not be negative")
+	assert.EqualError(t, Config{Budget: 1.5}.Validate(), "the budget of retry must be between 0 and 1")
+}
@@ -0,0 +1,106 @@
+package httpproxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RewriteConfig replaces the path of the request with Replacement, which may use the capture groups
+// of Pattern like $1 or ${name}. Without a pattern the path pattern of the mapping is used, so its
+// wildcards are the capture groups.
+type RewriteConfig struct {
+	Pattern     string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Replacement string `yaml:"replacement" json:"replacement"`
+}
+
+// HeaderRules change the headers of a request or a response. They are applied in the order remove, set, add.
+type HeaderRules struct {
+	Remove []string          `yaml:"remove,omitempty" json:"remove,omitempty"`
+	Set    map[string]string `yaml:"set,omitempty" json:"set,omitempty"`
+	Add    map[string]string `yaml:"add,omitempty" json:"add,omitempty"`
+}
+
+func (rules *HeaderRules) apply(header http.Header) {
+	if rules == nil {
+		return
+	}
+	for _, name := range rules.Remove {
+		header.Del(name)
+	}
+	for name, value := range rules.Set {
+		header.Set(name, value)
+	}
+	for name, value := range rules.Add {
+		header.Add(name, value)
+	}
+}
+
+// newRewrite validates the rewriting options of the mapping and compiles the rewrite pattern
+func newRewrite(config MappingConfig, pathPattern *regexp.Regexp) (*regexp.Regexp, error) {
+	if config.PrefixReplacement != "" && config.StripPrefix == "" {
+		return nil, errors.New("prefixReplacement needs a stripPrefix")
+	}
+	if config.Rewrite == nil {
+		return nil, nil
+	}
+	if config.StripPrefix != "" {
+		return nil, errors.New("either stripPrefix or rewrite can be used")
+	}
+	if config.Rewrite.Replacement == "" {
+		return nil, errors.New("rewrite needs a replacement")
+	}
+	if config.Rewrite.Pattern == "" {
+		return pathPattern, nil
+	}
+
+	pattern, err := regexp.Compile(config.Rewrite.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rewrite pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+// rewritePath applies the prefix or the rewrite pattern of the mapping to the path of the incoming request
+func (mapping *RouteMapping) rewritePath(path string) string {
+	if prefix := mapping.config.StripPrefix; prefix != "" {
+		if !strings.HasPrefix(path, prefix) {
+			return path
+		}
+		path = mapping.config.PrefixReplacement + strings.TrimPrefix(path, prefix)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		return path
+	}
+
+	if mapping.rewrite == nil {
+		return path
+	}
+	match := mapping.rewrite.FindStringSubmatchIndex(path)
+	if match == nil {
+		return path
+	}
+	// only the matched part is replaced, the path patterns aren't anchored
+	replacement := mapping.rewrite.ExpandString(nil, mapping.config.Rewrite.Replacement, path, match)
+	return path[:match[0]] + string(replacement) + path[match[1]:]
+}
+
+// rewriteRequest applies the path, header and host rules of the mapping to the outgoing request,
+// before it is forwarded
+func (mapping *RouteMapping) rewriteRequest(out *http.Request) {
+	if path := mapping.rewritePath(out.URL.Path); path != out.URL.Path {
+		out.URL.Path = path
+		out.URL.RawPath = ""
+	}
+	mapping.config.RequestHeaders.apply(out.Header)
+	if mapping.config.UpstreamHost != "" {
+		out.Host = mapping.config.UpstreamHost
+	}
+}
+
+func (mapping *RouteMapping) rewriteResponse(res *http.Response) {
+	mapping.config.ResponseHeaders.apply(res.Header)
+}
@@ -0,0 +1,148 @@
+package httpproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	clientMocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client/_mocks"
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRewritePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MappingConfig
+		path   string
+		want   string
+	}{
+		{"should keep the path without rules", MappingConfig{Path: "/api/*"}, "/api/books", "/api/books"},
+		{"should strip the prefix", MappingConfig{Path: "/api/*", StripPrefix: "/api"}, "/api/books/1", "/books/1"},
+		{"should keep a slash after stripping", MappingConfig{Path: "/api", StripPrefix: "/api"}, "/api", "/"},
+		{"should replace the prefix", MappingConfig{Path: "/api/v1/*", StripPrefix: "/api/v1", PrefixReplacement: "/v2"}, "/api/v1/books", "/v2/books"},
+		{"should rewrite with the wildcards of the path", MappingConfig{Path: "/api/v1/books/*", Rewrite: &RewriteConfig{Replacement: "/books/$1"}}, "/api/v1/books/42", "/books/42"},
+		{"should only rewrite the matched part", MappingConfig{Path: "/api/*", Rewrite: &RewriteConfig{Replacement: "/v2/$1"}}, "/api/books/42", "/v2/books/42"},
+		{"should rewrite with named groups of the pattern", MappingConfig{Path: "/users*", Rewrite: &RewriteConfig{Pattern: `^/users/(?P<id>\d+)$`, Replacement: "/profiles/${id}"}}, "/users/7", "/profiles/7"},
+		{"should keep a path, which doesn't match the pattern", MappingConfig{Path: "/users*", Rewrite: &RewriteConfig{Pattern: `^/users/(\d+)$`, Replacement: "/profiles/$1"}}, "/users/me", "/users/me"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// given
+			test.config.Hosts = []string{"http://upstream:8080"}
+			mapping, err := NewRouteMapping(test.config, Dependencies{})
+			assert.NoError(t, err)
+
+			// when
+			path := mapping.rewritePath(test.path)
+
+			// then
+			assert.Equal(t, test.want, path)
+		})
+	}
+
+	t.Run("should reject invalid rewrite rules", func(t *testing.T) {
+		// given
+		configs := []MappingConfig{
+			{Path: "/a", Hosts: []string{"http://a:8080"}, PrefixReplacement: "/b"},
+			{Path: "/a", Hosts: []string{"http://a:8080"}, StripPrefix: "/a", Rewrite: &RewriteConfig{Replacement: "/b"}},
+			{Path: "/a", Hosts: []string{"http://a:8080"}, Rewrite: &RewriteConfig{Pattern: "/a"}},
+			{Path: "/a", Hosts: []string{"http://a:8080"}, Rewrite: &RewriteConfig{Pattern: "(", Replacement: "/b"}},
+		}
+
+		// when
+		_, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "mapping 0 (/a): prefixReplacement needs a stripPrefix")
+		assert.ErrorContains(t, err, "mapping 1 (/a): either stripPrefix or rewrite can be used")
+		assert.ErrorContains(t, err, "mapping 2 (/a): rewrite needs a replacement")
+		assert.ErrorContains(t, err, "mapping 3 (/a): invalid rewrite pattern")
+	})
+}
+
+func TestWildcardPath(t *testing.T) {
+	t.Run("should expand the wildcards of the path without a host wildcard", func(t *testing.T) {
+		// given
+		mapping, _ := NewRouteMapping(MappingConfig{Path: "/api/*/books", Hosts: []string{"http://upstream:8080"}}, Dependencies{})
+
+		// then
+		assert.True(t, mapping.path.MatchString("/api/v1/books"))
+		assert.False(t, mapping.path.MatchString("/api/v1/beta/books"))
+	})
+}
+
+func TestRewriteRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+	client := clientMocks.NewMockClient(ctrl)
+
+	config := MappingConfig{
+		Hosts:       []string{"http://upstream:8080/internal"},
+		StripPrefix: "/the",
+		RequestHeaders: &HeaderRules{
+			Remove: []string{"Cookie"},
+			Set:    map[string]string{"X-Env": "test"},
+			Add:    map[string]string{"X-Forwarded-Prefix": "/the"},
+		},
+		ResponseHeaders: &HeaderRules{
+			Remove: []string{"Server"},
+			Set:    map[string]string{"Cache-Control": "no-store"},
+		},
+		UpstreamHost: "books.internal",
+	}
+
+	response := func() *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Server": {"nginx"}}, Body: http.NoBody}
+	}
+	assertRequest := func(r *http.Request) {
+		assert.Equal(t, "/internal/route", r.URL.Path)
+		assert.Equal(t, "books.internal", r.Host)
+		assert.Empty(t, r.Header.Get("Cookie"))
+		assert.Equal(t, "test", r.Header.Get("X-Env"))
+		assert.Equal(t, "/the", r.Header.Get("X-Forwarded-Prefix"))
+		assert.Equal(t, "example.com", r.Header.Get("X-Forwarded-Host"))
+	}
+
+	t.Run("should apply the rules in the HTTPUtilProxy", func(t *testing.T) {
+		// given
+		proxy := newTestProxy(t, roundTripper, config)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(response(), nil).Do(assertRequest)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+		r.Header.Set("Cookie", "session=1")
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Server"))
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("should apply the rules in the HTTPProxy", func(t *testing.T) {
+		// given
+		proxy := NewHTTPProxy(client)
+		mappingConfig := config
+		mappingConfig.Host, mappingConfig.Path = "*", "/the/route"
+		mapping, _ := NewRouteMapping(mappingConfig, Dependencies{})
+		proxy.Append(mapping)
+		client.EXPECT().Do(gomock.Any()).Return(response(), nil).Do(assertRequest)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+		r.Header.Set("Cookie", "session=1")
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Server"))
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	})
+}
@@ -0,0 +1,81 @@
+package strategy
+
+import (
+	"hash/fnv"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+// pointsPerWeight is the number of points of an upstream with weight 1 on the ring.
+// More points spread the keys more evenly.
+const pointsPerWeight = 100
+
+// KeyFunc returns the key of a request, requests with the same key are sent to the same upstream
+type KeyFunc func(*http.Request) string
+
+func HeaderKey(name string) KeyFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+func CookieKey(name string) KeyFunc {
+	return func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return cookie.Value
+	}
+}
+
+type point struct {
+	hash     uint32
+	upstream *upstream.Upstream
+}
+
+// ConsistentHashStrategy places the upstreams on a hash ring, so only the keys of an
+// added or removed upstream move to another one. Requests without a key are hashed by their client IP.
+// The keys of an unavailable upstream move to the next available one on the ring.
+type ConsistentHashStrategy struct {
+	key  KeyFunc
+	ring []point
+}
+
+func NewConsistentHashStrategy(upstreams []*upstream.Upstream, key KeyFunc) *ConsistentHashStrategy {
+	var ring []point
+	for _, u := range upstreams {
+		for i := 0; i < pointsPerWeight*u.Weight; i++ {
+			ring = append(ring, point{hash(u.Url.String() + "#" + strconv.Itoa(i)), u})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+
+	return &ConsistentHashStrategy{key: key, ring: ring}
+}
+
+func (s *ConsistentHashStrategy) Next(r *http.Request) *upstream.Upstream {
+	key := s.key(r)
+	if key == "" {
+		key, _, _ = net.SplitHostPort(r.RemoteAddr)
+	}
+
+	h := hash(key)
+	index := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= h })
+	for i := 0; i < len(s.ring); i++ {
+		if u := s.ring[(index+i)%len(s.ring)].upstream; u.Available() {
+			return u
+		}
+	}
+	return nil
+}
+
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
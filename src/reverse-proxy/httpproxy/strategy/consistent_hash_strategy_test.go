@@ -0,0 +1,132 @@
+package strategy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsistentHashStrategy(t *testing.T) {
+	requestWithHeader := func(value string) *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-User-Id", value)
+		return r
+	}
+
+	t.Run("Next", func(t *testing.T) {
+		t.Run("should return the same upstream for the same header", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewConsistentHashStrategy(upstreams, HeaderKey("X-User-Id"))
+
+			// when
+			first := strategyImpl.Next(requestWithHeader("1"))
+			second := strategyImpl.Next(requestWithHeader("1"))
+
+			// then
+			assert.Same(t, first, second)
+		})
+
+		t.Run("should return the same upstream for the same cookie", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewConsistentHashStrategy(upstreams, CookieKey("session"))
+
+			first := httptest.NewRequest("GET", "/", nil)
+			first.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+			first.RemoteAddr = "10.0.0.1:1234"
+			second := httptest.NewRequest("GET", "/", nil)
+			second.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+			second.RemoteAddr = "10.0.0.2:1234"
+
+			// when
+			firstUpstream := strategyImpl.Next(first)
+			secondUpstream := strategyImpl.Next(second)
+
+			// then
+			assert.Same(t, firstUpstream, secondUpstream)
+		})
+
+		t.Run("should hash the client IP without a key", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewConsistentHashStrategy(upstreams, HeaderKey("X-User-Id"))
+
+			first := httptest.NewRequest("GET", "/", nil)
+			first.RemoteAddr = "10.0.0.1:1234"
+			second := httptest.NewRequest("GET", "/", nil)
+			second.RemoteAddr = "10.0.0.1:5678"
+
+			// when
+			firstUpstream := strategyImpl.Next(first)
+			secondUpstream := strategyImpl.Next(second)
+
+			// then
+			assert.Same(t, firstUpstream, secondUpstream)
+		})
+
+		t.Run("should spread the keys over all upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewConsistentHashStrategy(upstreams, HeaderKey("X-User-Id"))
+
+			// when
+			counts := map[*upstream.Upstream]int{}
+			for i := 0; i < 300; i++ {
+				counts[strategyImpl.Next(requestWithHeader(fmt.Sprint(i)))]++
+			}
+
+			// then
+			for _, u := range upstreams {
+				assert.Greater(t, counts[u], 50)
+			}
+		})
+
+		t.Run("should only move the keys of a removed upstream", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			before := NewConsistentHashStrategy(upstreams, HeaderKey("X-User-Id"))
+			after := NewConsistentHashStrategy(upstreams[:2], HeaderKey("X-User-Id"))
+
+			// when
+			moved := 0
+			for i := 0; i < 300; i++ {
+				r := requestWithHeader(fmt.Sprint(i))
+				previous := before.Next(r)
+				if previous != upstreams[2] && previous != after.Next(r) {
+					moved++
+				}
+			}
+
+			// then
+			assert.Equal(t, 0, moved)
+		})
+
+		t.Run("should only move the keys of an unavailable upstream", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewConsistentHashStrategy(upstreams, HeaderKey("X-User-Id"))
+			before := map[string]*upstream.Upstream{}
+			for i := 0; i < 300; i++ {
+				key := fmt.Sprint(i)
+				before[key] = strategyImpl.Next(requestWithHeader(key))
+			}
+
+			// when
+			markUnavailable(upstreams[2])
+
+			// then
+			for key, previous := range before {
+				next := strategyImpl.Next(requestWithHeader(key))
+				assert.NotEqual(t, upstreams[2], next)
+				if previous != upstreams[2] {
+					assert.Equal(t, previous, next)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package strategy
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+// LeastInFlightStrategy selects the upstream with the fewest requests in flight.
+// Ties are broken round-robin, so idle upstreams are used evenly.
+type LeastInFlightStrategy struct {
+	counter   atomic.Uint64
+	upstreams []*upstream.Upstream
+}
+
+func NewLeastInFlightStrategy(upstreams []*upstream.Upstream) *LeastInFlightStrategy {
+	return &LeastInFlightStrategy{upstreams: upstreams}
+}
+
+func (s *LeastInFlightStrategy) Next(*http.Request) *upstream.Upstream {
+	start := s.counter.Add(1) - 1
+	count := uint64(len(s.upstreams))
+
+	var min *upstream.Upstream
+	for i := uint64(0); i < count; i++ {
+		u := s.upstreams[(start+i)%count]
+		if u.Available() && (min == nil || u.InFlight() < min.InFlight()) {
+			min = u
+		}
+	}
+	return min
+}
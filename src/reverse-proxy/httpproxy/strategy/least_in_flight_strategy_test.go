@@ -0,0 +1,62 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastInFlightStrategy(t *testing.T) {
+	t.Run("Next", func(t *testing.T) {
+		t.Run("should return the upstream with the fewest requests in flight", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewLeastInFlightStrategy(upstreams)
+
+			upstreams[0].Acquire()
+			upstreams[0].Acquire()
+			release := upstreams[1].Acquire()
+			upstreams[2].Acquire()
+			upstreams[2].Acquire()
+
+			// when
+			first := strategyImpl.Next(nil)
+			release()
+			upstreams[0].Acquire()
+			second := strategyImpl.Next(nil)
+
+			// then
+			assert.Equal(t, upstreams[1], first)
+			assert.Equal(t, upstreams[1], second)
+		})
+
+		t.Run("should rotate between idle upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1)
+			strategyImpl := NewLeastInFlightStrategy(upstreams)
+
+			// when
+			counts := countNext(strategyImpl, 10)
+
+			// then
+			assert.Equal(t, 5, counts[upstreams[0]])
+			assert.Equal(t, 5, counts[upstreams[1]])
+		})
+
+		t.Run("should skip unavailable upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1)
+			strategyImpl := NewLeastInFlightStrategy(upstreams)
+			upstreams[1].Acquire()
+			markUnavailable(upstreams[0])
+
+			// when
+			next := strategyImpl.Next(nil)
+
+			// then
+			assert.Equal(t, upstreams[1], next)
+			markUnavailable(upstreams[1])
+			assert.Nil(t, strategyImpl.Next(nil))
+		})
+	})
+}
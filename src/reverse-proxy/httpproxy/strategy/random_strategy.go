@@ -0,0 +1,29 @@
+package strategy
+
+import (
+	"math/rand"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+type RandomStrategy struct {
+	upstreams []*upstream.Upstream
+}
+
+func NewRandomStrategy(upstreams []*upstream.Upstream) *RandomStrategy {
+	return &RandomStrategy{upstreams: upstreams}
+}
+
+func (s *RandomStrategy) Next(*http.Request) *upstream.Upstream {
+	available := make([]*upstream.Upstream, 0, len(s.upstreams))
+	for _, u := range s.upstreams {
+		if u.Available() {
+			available = append(available, u)
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+	return available[rand.Intn(len(available))]
+}
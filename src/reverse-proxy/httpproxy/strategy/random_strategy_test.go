@@ -0,0 +1,42 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomStrategy(t *testing.T) {
+	t.Run("Next", func(t *testing.T) {
+		t.Run("should return every upstream", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewRandomStrategy(upstreams)
+
+			// when
+			counts := countNext(strategyImpl, 300)
+
+			// then
+			assert.Len(t, counts, 3)
+			for _, u := range upstreams {
+				assert.Greater(t, counts[u], 0)
+			}
+		})
+
+		t.Run("should only return available upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewRandomStrategy(upstreams)
+			markUnavailable(upstreams[0], upstreams[2])
+
+			// when
+			counts := countNext(strategyImpl, 20)
+
+			// then
+			assert.Equal(t, map[*upstream.Upstream]int{upstreams[1]: 20}, counts)
+			markUnavailable(upstreams[1])
+			assert.Nil(t, strategyImpl.Next(nil))
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package strategy
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+type RoundRobinStrategy struct {
+	counter   atomic.Uint64
+	upstreams []*upstream.Upstream
+}
+
+func NewRoundRobinStrategy(upstreams []*upstream.Upstream) *RoundRobinStrategy {
+	return &RoundRobinStrategy{upstreams: upstreams}
+}
+
+func (s *RoundRobinStrategy) Next(*http.Request) *upstream.Upstream {
+	// the counter is advanced for skipped upstreams too, so the next one doesn't get their requests as well
+	count := uint64(len(s.upstreams))
+	for i := uint64(0); i < count; i++ {
+		current := s.counter.Add(1) - 1
+		if u := s.upstreams[current%count]; u.Available() {
+			return u
+		}
+	}
+	return nil
+}
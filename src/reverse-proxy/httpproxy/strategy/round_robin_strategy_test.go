@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundRobinStrategy(t *testing.T) {
+	t.Run("Next", func(t *testing.T) {
+		t.Run("should return the upstreams after another", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewRoundRobinStrategy(upstreams)
+
+			// when
+			first := strategyImpl.Next(nil)
+			second := strategyImpl.Next(nil)
+			third := strategyImpl.Next(nil)
+			fourth := strategyImpl.Next(nil)
+
+			// then
+			assert.Equal(t, upstreams[0], first)
+			assert.Equal(t, upstreams[1], second)
+			assert.Equal(t, upstreams[2], third)
+			assert.Equal(t, upstreams[0], fourth)
+		})
+
+		t.Run("should distribute concurrent requests evenly", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1)
+			strategyImpl := NewRoundRobinStrategy(upstreams)
+
+			var mu sync.Mutex
+			counts := map[int]int{}
+			var wg sync.WaitGroup
+
+			// when
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					next := strategyImpl.Next(nil)
+					mu.Lock()
+					defer mu.Unlock()
+					if next == upstreams[0] {
+						counts[0]++
+					} else {
+						counts[1]++
+					}
+				}()
+			}
+			wg.Wait()
+
+			// then
+			assert.Equal(t, map[int]int{0: 50, 1: 50}, counts)
+		})
+
+		t.Run("should skip unavailable upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1, 1)
+			strategyImpl := NewRoundRobinStrategy(upstreams)
+			markUnavailable(upstreams[1])
+
+			// when
+			counts := countNext(strategyImpl, 6)
+
+			// then
+			assert.Equal(t, map[*upstream.Upstream]int{upstreams[0]: 3, upstreams[2]: 3}, counts)
+		})
+
+		t.Run("should return nil without available upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(1, 1)
+			strategyImpl := NewRoundRobinStrategy(upstreams)
+			markUnavailable(upstreams...)
+
+			// when
+			next := strategyImpl.Next(nil)
+
+			// then
+			assert.Nil(t, next)
+		})
+	})
+}
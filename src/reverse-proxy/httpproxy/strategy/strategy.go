@@ -0,0 +1,21 @@
+package strategy
+
+import (
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+const (
+	RoundRobin         = "round-robin"
+	WeightedRoundRobin = "weighted-round-robin"
+	Random             = "random"
+	LeastInFlight      = "least-inflight"
+	ConsistentHash     = "consistent-hash"
+)
+
+// Strategy selects the upstream of a request. It is called by concurrent requests, so it must be safe for concurrent use.
+// Unavailable upstreams are skipped, nil is returned if none is available.
+type Strategy interface {
+	Next(*http.Request) *upstream.Upstream
+}
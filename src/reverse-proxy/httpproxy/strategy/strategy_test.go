@@ -0,0 +1,31 @@
+package strategy
+
+import (
+	"net/url"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+func newUpstreams(weights ...int) []*upstream.Upstream {
+	upstreams := make([]*upstream.Upstream, len(weights))
+	for i, weight := range weights {
+		URL, _ := url.Parse("http://upstream-" + string(rune('a'+i)) + ":8080")
+		upstreams[i] = upstream.NewUpstream(URL, weight)
+	}
+	return upstreams
+}
+
+// markUnavailable fails the active health check of the upstreams
+func markUnavailable(upstreams ...*upstream.Upstream) {
+	for _, u := range upstreams {
+		u.ReportCheck(false, 1, 1)
+	}
+}
+
+func countNext(strategy Strategy, times int) map[*upstream.Upstream]int {
+	counts := map[*upstream.Upstream]int{}
+	for i := 0; i < times; i++ {
+		counts[strategy.Next(nil)]++
+	}
+	return counts
+}
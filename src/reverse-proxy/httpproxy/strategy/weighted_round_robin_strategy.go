@@ -0,0 +1,47 @@
+package strategy
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+// WeightedRoundRobinStrategy is the smooth weighted round-robin of nginx. An upstream with
+// weight 3 gets 3 of 4 requests next to one with weight 1, but they are interleaved (a, a, b, a)
+// instead of sent in bursts.
+type WeightedRoundRobinStrategy struct {
+	mu        sync.Mutex
+	upstreams []*upstream.Upstream
+	current   []int
+}
+
+func NewWeightedRoundRobinStrategy(upstreams []*upstream.Upstream) *WeightedRoundRobinStrategy {
+	return &WeightedRoundRobinStrategy{
+		upstreams: upstreams,
+		current:   make([]int, len(upstreams)),
+	}
+}
+
+// Next only weighs the available upstreams, so their share grows while others are unavailable
+func (s *WeightedRoundRobinStrategy) Next(*http.Request) *upstream.Upstream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	best, total := -1, 0
+	for i, u := range s.upstreams {
+		if !u.Available() {
+			continue
+		}
+		s.current[i] += u.Weight
+		total += u.Weight
+		if best == -1 || s.current[i] > s.current[best] {
+			best = i
+		}
+	}
+	if best == -1 {
+		return nil
+	}
+	s.current[best] -= total
+	return s.upstreams[best]
+}
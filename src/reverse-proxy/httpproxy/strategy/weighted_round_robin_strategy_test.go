@@ -0,0 +1,56 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedRoundRobinStrategy(t *testing.T) {
+	t.Run("Next", func(t *testing.T) {
+		t.Run("should interleave the upstreams by their weight", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(3, 1)
+			strategyImpl := NewWeightedRoundRobinStrategy(upstreams)
+
+			// when
+			var order []*upstream.Upstream
+			for i := 0; i < 4; i++ {
+				order = append(order, strategyImpl.Next(nil))
+			}
+
+			// then
+			assert.Equal(t, []*upstream.Upstream{upstreams[0], upstreams[0], upstreams[1], upstreams[0]}, order)
+		})
+
+		t.Run("should distribute the requests by their weight", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(5, 3, 2)
+			strategyImpl := NewWeightedRoundRobinStrategy(upstreams)
+
+			// when
+			counts := countNext(strategyImpl, 100)
+
+			// then
+			assert.Equal(t, 50, counts[upstreams[0]])
+			assert.Equal(t, 30, counts[upstreams[1]])
+			assert.Equal(t, 20, counts[upstreams[2]])
+		})
+
+		t.Run("should only weigh the available upstreams", func(t *testing.T) {
+			// given
+			upstreams := newUpstreams(3, 1, 1)
+			strategyImpl := NewWeightedRoundRobinStrategy(upstreams)
+			markUnavailable(upstreams[0])
+
+			// when
+			counts := countNext(strategyImpl, 4)
+
+			// then
+			assert.Equal(t, map[*upstream.Upstream]int{upstreams[1]: 2, upstreams[2]: 2}, counts)
+			markUnavailable(upstreams[1:]...)
+			assert.Nil(t, strategyImpl.Next(nil))
+		})
+	})
+}
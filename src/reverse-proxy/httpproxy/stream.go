@@ -0,0 +1,248 @@
+package httpproxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// flushInterval is the longest time, a part of a streamed response stays in the buffer of the proxy
+const flushInterval = 100 * time.Millisecond
+
+// hopByHopHeaders only apply to a single connection, so they aren't forwarded (RFC 7230, section 6.1)
+var hopByHopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// removeHopByHopHeaders removes the hop-by-hop headers and those named in the Connection header
+func removeHopByHopHeaders(header http.Header) {
+	for _, value := range header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				header.Del(name)
+			}
+		}
+	}
+	for _, name := range hopByHopHeaders {
+		header.Del(name)
+	}
+}
+
+// upgradeType is the protocol of the Upgrade header, if the Connection header asks for an upgrade
+func upgradeType(header http.Header) string {
+	if !httpguts.HeaderValuesContainsToken(header["Connection"], "Upgrade") {
+		return ""
+	}
+	return header.Get("Upgrade")
+}
+
+// prepareHopByHopHeaders removes the hop-by-hop headers of the incoming request,
+// but keeps those of an upgrade and the announcement of trailers
+func prepareHopByHopHeaders(r *http.Request) {
+	upgrade := upgradeType(r.Header)
+	acceptsTrailers := httpguts.HeaderValuesContainsToken(r.Header["Te"], "trailers")
+	removeHopByHopHeaders(r.Header)
+
+	if acceptsTrailers {
+		r.Header.Set("Te", "trailers")
+	}
+	if upgrade != "" {
+		r.Header.Set("Connection", "Upgrade")
+		r.Header.Set("Upgrade", upgrade)
+	}
+	// the connection to the upstream is reused, even if the client closes its own
+	r.Close = false
+}
+
+// copyResponse writes the status, the headers, the body and the trailers of the response
+func copyResponse(w http.ResponseWriter, res *http.Response) error {
+	removeHopByHopHeaders(res.Header)
+	for name, values := range res.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	announcedTrailers := len(res.Trailer)
+	if announcedTrailers > 0 {
+		names := make([]string, 0, announcedTrailers)
+		for name := range res.Trailer {
+			names = append(names, name)
+		}
+		w.Header().Add("Trailer", strings.Join(names, ", "))
+	}
+
+	w.WriteHeader(res.StatusCode)
+
+	writer := newFlushingWriter(w, streamingInterval(res))
+	_, err := io.Copy(writer, res.Body)
+	writer.stop()
+	if err != nil {
+		return err
+	}
+
+	// trailers, which weren't announced, need the prefix to be sent
+	unannounced := len(res.Trailer) != announcedTrailers
+	for name, values := range res.Trailer {
+		if unannounced {
+			name = http.TrailerPrefix + name
+		}
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	return nil
+}
+
+// streamingInterval flushes every write of event streams and responses of unknown length,
+// other responses are flushed periodically
+func streamingInterval(res *http.Response) time.Duration {
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" || res.ContentLength == -1 {
+		return -1
+	}
+	return flushInterval
+}
+
+// flushingWriter flushes the writes to the client after the interval or right away, if it's negative
+type flushingWriter struct {
+	w          io.Writer
+	controller *http.ResponseController
+	interval   time.Duration
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	pending bool
+}
+
+func newFlushingWriter(w http.ResponseWriter, interval time.Duration) *flushingWriter {
+	return &flushingWriter{w: w, controller: http.NewResponseController(w), interval: interval}
+}
+
+func (writer *flushingWriter) Write(p []byte) (int, error) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	n, err := writer.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	if writer.interval < 0 {
+		writer.controller.Flush()
+		return n, nil
+	}
+	if !writer.pending {
+		writer.pending = true
+		if writer.timer == nil {
+			writer.timer = time.AfterFunc(writer.interval, writer.flush)
+		} else {
+			writer.timer.Reset(writer.interval)
+		}
+	}
+	return n, nil
+}
+
+func (writer *flushingWriter) flush() {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	if !writer.pending {
+		return
+	}
+	writer.pending = false
+	writer.controller.Flush()
+}
+
+// stop cancels the pending flush, the rest is flushed, when the handler returns
+func (writer *flushingWriter) stop() {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	writer.pending = false
+	if writer.timer != nil {
+		writer.timer.Stop()
+	}
+}
+
+// upgradedConn is the upstream connection of a 101 response, if it switched to the requested protocol
+func upgradedConn(r *http.Request, res *http.Response) (io.ReadWriteCloser, error) {
+	requested, switched := upgradeType(r.Header), upgradeType(res.Header)
+	if requested == "" || !strings.EqualFold(requested, switched) {
+		res.Body.Close()
+		return nil, fmt.Errorf("the upstream switched to the protocol %q instead of %q", switched, requested)
+	}
+	upstreamConn, ok := res.Body.(io.ReadWriteCloser)
+	if !ok {
+		res.Body.Close()
+		return nil, errors.New("the upstream connection of the protocol switch isn't writable")
+	}
+	return upstreamConn, nil
+}
+
+// serveSwitchedProtocols tunnels the connection of the client to the upstream after a 101 response, like for WebSockets
+func serveSwitchedProtocols(w http.ResponseWriter, r *http.Request, id string, mapping *RouteMapping, res *http.Response) {
+	upstreamConn, err := upgradedConn(r, res)
+	if err != nil {
+		writeError(w, r, id, mapping, err)
+		return
+	}
+	clientConn, buffered, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		upstreamConn.Close()
+		writeError(w, r, id, mapping, err)
+		return
+	}
+	if err := switchProtocols(clientConn, buffered, res, upstreamConn); err != nil {
+		slog.WarnContext(r.Context(), "the switched connection failed", "request_id", id, "error", err)
+	}
+}
+
+// switchProtocols writes the 101 response to the hijacked connection of the client and pipes it
+// to the upstream connection in both directions, until one of them is closed
+func switchProtocols(clientConn net.Conn, buffered *bufio.ReadWriter, res *http.Response, upstreamConn io.ReadWriteCloser) error {
+	defer clientConn.Close()
+	defer upstreamConn.Close()
+
+	upgrade := upgradeType(res.Header)
+	removeHopByHopHeaders(res.Header)
+	res.Header.Set("Connection", "Upgrade")
+	res.Header.Set("Upgrade", upgrade)
+
+	res.Body = nil
+	if err := res.Write(buffered); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(upstreamConn, buffered)
+		done <- err
+	}()
+	go func() {
+		_, err := io.Copy(clientConn, upstreamConn)
+		done <- err
+	}()
+	return <-done
+}
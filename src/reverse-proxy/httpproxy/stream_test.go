@@ -0,0 +1,213 @@
+package httpproxy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newStreamingProxy serves an HTTPProxy with a real client in front of the upstream
+func newStreamingProxy(t *testing.T, upstream http.Handler) *httptest.Server {
+	t.Helper()
+
+	upstreamServer := httptest.NewServer(upstream)
+	t.Cleanup(upstreamServer.Close)
+
+	proxy := NewHTTPProxy(&http.Client{})
+	mapping, err := NewRouteMapping(MappingConfig{Host: "*", Path: "/the/route", Hosts: []string{upstreamServer.URL}}, Dependencies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy.Append(mapping)
+
+	proxyServer := httptest.NewServer(proxy)
+	t.Cleanup(proxyServer.Close)
+	return proxyServer
+}
+
+func TestRemoveHopByHopHeaders(t *testing.T) {
+	t.Run("should remove the hop-by-hop headers and those of the Connection header", func(t *testing.T) {
+		// given
+		header := http.Header{
+			"Connection":        {"keep-alive, X-Hop"},
+			"Keep-Alive":        {"timeout=5"},
+			"Transfer-Encoding": {"chunked"},
+			"X-Hop":             {"1"},
+			"X-End":             {"2"},
+		}
+
+		// when
+		removeHopByHopHeaders(header)
+
+		// then
+		assert.Equal(t, http.Header{"X-End": {"2"}}, header)
+	})
+}
+
+func TestStreaming(t *testing.T) {
+	t.Run("should not forward hop-by-hop headers", func(t *testing.T) {
+		// given
+		proxy := newStreamingProxy(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("X-Hop"))
+			assert.Empty(t, r.Header.Get("Proxy-Authorization"))
+			assert.Equal(t, "trailers", r.Header.Get("Te"))
+			assert.Equal(t, "end", r.Header.Get("X-End"))
+			w.Header().Set("Connection", "X-Upstream-Hop")
+			w.Header().Set("X-Upstream-Hop", "1")
+		}))
+
+		r, _ := http.NewRequest("GET", proxy.URL+"/the/route", nil)
+		r.Header.Set("Connection", "X-Hop")
+		r.Header.Set("X-Hop", "1")
+		r.Header.Set("Proxy-Authorization", "Basic c2VjcmV0")
+		r.Header.Set("Te", "trailers")
+		r.Header.Set("X-End", "end")
+
+		// when
+		res, err := http.DefaultClient.Do(r)
+
+		// then
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get("X-Upstream-Hop"))
+	})
+
+	t.Run("should forward chunked bodies with their trailers", func(t *testing.T) {
+		// given
+		proxy := newStreamingProxy(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Trailer", "X-Checksum")
+			io.WriteString(w, "first ")
+			w.(http.Flusher).Flush()
+			io.WriteString(w, "second")
+			w.Header().Set("X-Checksum", "42")
+			w.Header().Set(http.TrailerPrefix+"X-Late", "late")
+		}))
+
+		// when
+		res, err := http.Get(proxy.URL + "/the/route")
+
+		// then
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "first second", string(body))
+		assert.Equal(t, []string{"chunked"}, res.TransferEncoding)
+		assert.Equal(t, "42", res.Trailer.Get("X-Checksum"))
+		assert.Equal(t, "late", res.Trailer.Get("X-Late"))
+	})
+
+	t.Run("should flush server-sent events right away", func(t *testing.T) {
+		// given
+		finish := make(chan struct{})
+		defer close(finish)
+		proxy := newStreamingProxy(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: first\n\n")
+			w.(http.Flusher).Flush()
+			<-finish
+		}))
+
+		// when
+		res, err := http.Get(proxy.URL + "/the/route")
+
+		// then
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		line, err := bufio.NewReader(res.Body).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "data: first\n", line)
+	})
+
+	t.Run("should flush the responses of a known length periodically", func(t *testing.T) {
+		// given
+		finish := make(chan struct{})
+		defer close(finish)
+		proxy := newStreamingProxy(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "10")
+			io.WriteString(w, "first")
+			w.(http.Flusher).Flush()
+			<-finish
+		}))
+
+		// when
+		res, err := http.Get(proxy.URL + "/the/route")
+
+		// then
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		first := make([]byte, 5)
+		_, err = io.ReadFull(res.Body, first)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", string(first))
+	})
+
+	t.Run("should tunnel a protocol switch in both directions", func(t *testing.T) {
+		// given
+		proxy := newStreamingProxy(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if upgradeType(r.Header) != "echo" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			conn, buffered, _ := http.NewResponseController(w).Hijack()
+			defer conn.Close()
+			io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+			for {
+				line, err := buffered.ReadString('\n')
+				if err != nil {
+					return
+				}
+				io.WriteString(conn, "echo: "+line)
+			}
+		}))
+
+		conn, err := net.Dial("tcp", strings.TrimPrefix(proxy.URL, "http://"))
+		assert.NoError(t, err)
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		// when
+		fmt.Fprintf(conn, "GET /the/route HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		reader := bufio.NewReader(conn)
+		res, err := http.ReadResponse(reader, nil)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+		assert.Equal(t, "echo", res.Header.Get("Upgrade"))
+
+		fmt.Fprintf(conn, "ping\n")
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: ping\n", line)
+	})
+
+	t.Run("should return 502 BAD GATEWAY if the upstream switches to another protocol", func(t *testing.T) {
+		// given
+		proxy := newStreamingProxy(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Connection", "Upgrade")
+			w.Header().Set("Upgrade", "other")
+			w.WriteHeader(http.StatusSwitchingProtocols)
+		}))
+
+		r, _ := http.NewRequest("GET", proxy.URL+"/the/route", nil)
+		r.Header.Set("Connection", "Upgrade")
+		r.Header.Set("Upgrade", "echo")
+
+		// when
+		res, err := http.DefaultClient.Do(r)
+
+		// then
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, res.StatusCode)
+	})
+}
@@ -0,0 +1,106 @@
+package httpproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"golang.org/x/net/http2"
+)
+
+// UpstreamTLSConfig configures the TLS connections to the https-hosts of a mapping
+type UpstreamTLSConfig struct {
+	// CAFile is the PEM bundle of the CAs, which verify the hosts, the system CAs are used without it
+	CAFile string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	// CertFile and KeyFile are the client certificate for mTLS
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+	// ServerName is verified instead of the host of the URL
+	ServerName string `yaml:"serverName,omitempty" json:"serverName,omitempty"`
+}
+
+func (config UpstreamTLSConfig) clientConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: config.ServerName}
+
+	if config.CAFile != "" {
+		bundle, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the CA bundle: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("the CA bundle %s has no certificates", config.CAFile)
+		}
+	}
+
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errors.New("mTLS needs a certFile and a keyFile")
+	}
+	if config.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// newTransport creates the transport of a mapping, which needs its own connections to the hosts.
+// It returns nil, if the transport of the proxy can be used.
+func newTransport(config MappingConfig) (http.RoundTripper, error) {
+	if config.H2C && config.TLS != nil {
+		return nil, errors.New("h2c can't be used with tls")
+	}
+
+	if config.H2C {
+		for _, host := range config.Hosts {
+			if !strings.HasPrefix(host, "http://") {
+				return nil, errors.New("h2c needs http-hosts")
+			}
+		}
+		// HTTP/2 with prior knowledge, the hosts must support it
+		return &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, addr)
+			},
+		}, nil
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.clientConfig()
+		if err != nil {
+			return nil, err
+		}
+		// HTTP/2 is negotiated with the hosts, which support it
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		transport.ForceAttemptHTTP2 = true
+		return transport, nil
+	}
+	return nil, nil
+}
+
+// instrumented counts the requests of the transport in the upstream metrics like the transport of the proxy
+func instrumented(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		return nil
+	}
+	return metrics.RoundTripper(transport)
+}
+
+// CloseIdleConnections closes the idle connections of the own transport of the mapping, when it was replaced
+func (mapping *RouteMapping) CloseIdleConnections() {
+	if closer, ok := mapping.transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
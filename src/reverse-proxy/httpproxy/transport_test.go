@@ -0,0 +1,163 @@
+package httpproxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func writePEM(t *testing.T, file string, blockType string, bytes []byte) string {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}), 0o600))
+	return file
+}
+
+// writeClientCertificate writes a self-signed client certificate and returns the files and the certificate
+func writeClientCertificate(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "reverse-proxy"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := writePEM(t, filepath.Join(dir, "client.crt"), "CERTIFICATE", der)
+	keyFile := writePEM(t, filepath.Join(dir, "client.key"), "EC PRIVATE KEY", keyDer)
+	return certFile, keyFile, certificate
+}
+
+func TestTransport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	// the proxy transport mustn't be called, when the mapping has its own one
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should speak h2c to the upstream", func(t *testing.T) {
+		// given
+		upstream := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Proto", r.Proto)
+		}), &http2.Server{}))
+		defer upstream.Close()
+		proxy := newTestProxy(t, roundTripper, MappingConfig{Hosts: []string{upstream.URL}, H2C: true})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "HTTP/2.0", w.Header().Get("X-Proto"))
+	})
+
+	t.Run("should authenticate with mTLS against the CA bundle", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		certFile, keyFile, clientCertificate := writeClientCertificate(t, dir)
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCertificate)
+
+		upstream := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Client", r.TLS.PeerCertificates[0].Subject.CommonName)
+			w.Header().Set("X-Proto", r.Proto)
+		}))
+		upstream.EnableHTTP2 = true
+		upstream.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+		upstream.StartTLS()
+		defer upstream.Close()
+		caFile := writePEM(t, filepath.Join(dir, "ca.crt"), "CERTIFICATE", upstream.Certificate().Raw)
+
+		proxy := newTestProxy(t, roundTripper, MappingConfig{
+			Hosts: []string{upstream.URL},
+			TLS:   &UpstreamTLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "example.com"},
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "reverse-proxy", w.Header().Get("X-Client"))
+		assert.Equal(t, "HTTP/2.0", w.Header().Get("X-Proto"))
+	})
+
+	t.Run("should not trust upstreams outside of the CA bundle", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		certFile, _, _ := writeClientCertificate(t, dir)
+		upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer upstream.Close()
+
+		// the client certificate is a CA bundle, which didn't sign the upstream
+		proxy := newTestProxy(t, roundTripper, MappingConfig{Hosts: []string{upstream.URL}, TLS: &UpstreamTLSConfig{CAFile: certFile}})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/the/route", nil)
+
+		// when
+		proxy.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("should reject invalid transport options", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		certFile, _, _ := writeClientCertificate(t, dir)
+		emptyFile := filepath.Join(dir, "empty.crt")
+		assert.NoError(t, os.WriteFile(emptyFile, nil, 0o600))
+		hosts := []string{"http://a:8080"}
+
+		configs := []MappingConfig{
+			{Path: "/a", Hosts: hosts, H2C: true, TLS: &UpstreamTLSConfig{}},
+			{Path: "/a", Hosts: []string{"https://a:8443"}, H2C: true},
+			{Path: "/a", Hosts: hosts, TLS: &UpstreamTLSConfig{CAFile: filepath.Join(dir, "missing.crt")}},
+			{Path: "/a", Hosts: hosts, TLS: &UpstreamTLSConfig{CAFile: emptyFile}},
+			{Path: "/a", Hosts: hosts, TLS: &UpstreamTLSConfig{CertFile: certFile}},
+			{Path: "/a", Hosts: hosts, TLS: &UpstreamTLSConfig{CertFile: certFile, KeyFile: emptyFile}},
+		}
+
+		// when
+		_, err := NewRouteMappings(configs, Dependencies{})
+
+		// then
+		assert.ErrorContains(t, err, "mapping 0 (/a): h2c can't be used with tls")
+		assert.ErrorContains(t, err, "mapping 1 (/a): h2c needs http-hosts")
+		assert.ErrorContains(t, err, "mapping 2 (/a): could not read the CA bundle")
+		assert.ErrorContains(t, err, "mapping 3 (/a): the CA bundle")
+		assert.ErrorContains(t, err, "mapping 4 (/a): mTLS needs a certFile and a keyFile")
+		assert.ErrorContains(t, err, "mapping 5 (/a): could not load the client certificate")
+	})
+}
@@ -0,0 +1,75 @@
+package upstream
+
+import "sync"
+
+// pool is shared by the upstreams of a mapping, so the outlier detection of one upstream
+// knows how many of its siblings are ejected or unavailable. Its mutex is always taken
+// after the mutex of an upstream.
+type pool struct {
+	mu   sync.Mutex
+	size int
+	// ejected are the upstreams ejected by the outlier detection, unavailable also
+	// contains the ones failing the active health checks
+	ejected     int
+	unavailable int
+}
+
+// NewPool lets the outlier detection of the upstreams of a mapping eject at most
+// maxEjectionPercent of them and never the last available one
+func NewPool(upstreams []*Upstream) {
+	p := &pool{size: len(upstreams)}
+	for _, u := range upstreams {
+		u.pool = p
+	}
+}
+
+// eject reserves the ejection of an upstream, which is available or not
+func (p *pool) eject(available bool, maxEjectionPercent int) bool {
+	if p == nil {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	maxEjected := max(p.size*maxEjectionPercent/100, 1)
+	if p.ejected >= maxEjected {
+		return false
+	}
+	if available && p.unavailable+1 >= p.size {
+		return false
+	}
+	p.ejected++
+	if available {
+		p.unavailable++
+	}
+	return true
+}
+
+// restore releases the ejection of an upstream, which is available again or not
+func (p *pool) restore(available bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ejected--
+	if available {
+		p.unavailable--
+	}
+}
+
+// setAvailable records an upstream becoming available or unavailable by its health checks
+func (p *pool) setAvailable(available bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if available {
+		p.unavailable--
+	} else {
+		p.unavailable++
+	}
+}
@@ -0,0 +1,225 @@
+package upstream
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+)
+
+// now is replaced by the tests to move through the ejection time
+var now = time.Now
+
+// OutlierDetection ejects an upstream after consecutive failed requests, which are 5xx responses
+// or connection errors. The ejection time doubles with every ejection up to MaxEjectionTime and is
+// reset, once the upstream served requests without an ejection for MaxEjectionTime. At most
+// MaxEjectionPercent of the upstreams of a mapping, but at least one, are ejected at the same time
+// and the last available upstream is never ejected.
+type OutlierDetection struct {
+	ConsecutiveFailures int           `yaml:"consecutiveFailures,omitempty" json:"consecutiveFailures,omitempty"`
+	BaseEjectionTime    time.Duration `yaml:"baseEjectionTime,omitempty" json:"baseEjectionTime,omitempty"`
+	MaxEjectionTime     time.Duration `yaml:"maxEjectionTime,omitempty" json:"maxEjectionTime,omitempty"`
+	MaxEjectionPercent  int           `yaml:"maxEjectionPercent,omitempty" json:"maxEjectionPercent,omitempty"`
+}
+
+// WithDefaults returns the detection with the defaults for the unset values
+func (detection OutlierDetection) WithDefaults() OutlierDetection {
+	if detection.ConsecutiveFailures == 0 {
+		detection.ConsecutiveFailures = 5
+	}
+	if detection.BaseEjectionTime == 0 {
+		detection.BaseEjectionTime = 30 * time.Second
+	}
+	if detection.MaxEjectionTime == 0 {
+		detection.MaxEjectionTime = 5 * time.Minute
+	}
+	if detection.MaxEjectionPercent == 0 {
+		detection.MaxEjectionPercent = 50
+	}
+	return detection
+}
+
+func (detection OutlierDetection) Validate() error {
+	if detection.ConsecutiveFailures < 0 || detection.BaseEjectionTime < 0 || detection.MaxEjectionTime < 0 {
+		return errors.New("the values of outlierDetection must not be negative")
+	}
+	if detection.MaxEjectionPercent < 0 || detection.MaxEjectionPercent > 100 {
+		return errors.New("the maxEjectionPercent of outlierDetection must be between 0 and 100")
+	}
+	return nil
+}
+
+// Upstream is a host of a mapping. It is shared by all requests of the mapping,
+// so its state is atomic or guarded by the mutex.
+type Upstream struct {
+	Url    *url.URL
+	Weight int
+	// Mapping names the mapping of the upstream in the metrics, it is set while the mapping is created
+	Mapping string
+	// OutlierDetection is set while the mapping is created, nil disables it
+	OutlierDetection *OutlierDetection
+	inFlight         atomic.Int64
+	// pool is shared with the other upstreams of the mapping, nil doesn't limit the ejections
+	pool *pool
+
+	mu sync.Mutex
+	// unhealthy is the result of the active health checks
+	unhealthy      bool
+	checkSuccesses int
+	checkFailures  int
+	// failures are the consecutive failed requests
+	failures     int
+	ejections    int
+	ejected      bool
+	ejectedUntil time.Time
+}
+
+func NewUpstream(url *url.URL, weight int) *Upstream {
+	return &Upstream{Url: url, Weight: weight}
+}
+
+// Acquire counts a request to the upstream until release is called
+func (u *Upstream) Acquire() (release func()) {
+	u.inFlight.Add(1)
+	return func() { u.inFlight.Add(-1) }
+}
+
+func (u *Upstream) InFlight() int64 {
+	return u.inFlight.Load()
+}
+
+// Available reports if requests may be sent to the upstream, it must be healthy and not ejected
+func (u *Upstream) Available() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return !u.unhealthy && !u.isEjected()
+}
+
+// isEjected brings the upstream back, when its ejection time is over. The mutex must be held.
+func (u *Upstream) isEjected() bool {
+	if u.ejected && !now().Before(u.ejectedUntil) {
+		u.ejected = false
+		u.pool.restore(!u.unhealthy)
+		slog.Info("upstream returned after its ejection", "upstream", u.Url.Host)
+		metrics.SetUpstreamHealth(u.Mapping, u.Url.Host, !u.unhealthy)
+	}
+	return u.ejected
+}
+
+// ReportCheck records the result of an active health check. The upstream becomes unhealthy
+// after unhealthyThreshold failed checks in a row and healthy again after healthyThreshold
+// successful ones.
+func (u *Upstream) ReportCheck(healthy bool, healthyThreshold int, unhealthyThreshold int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if healthy {
+		u.checkFailures = 0
+		u.checkSuccesses++
+		if u.unhealthy && u.checkSuccesses >= healthyThreshold {
+			ejected := u.isEjected()
+			u.unhealthy = false
+			if !ejected {
+				u.pool.setAvailable(true)
+			}
+			slog.Info("upstream became healthy", "upstream", u.Url.Host)
+			metrics.SetUpstreamHealth(u.Mapping, u.Url.Host, !ejected)
+		}
+		return
+	}
+
+	u.checkSuccesses = 0
+	u.checkFailures++
+	if !u.unhealthy && u.checkFailures >= unhealthyThreshold {
+		if !u.isEjected() {
+			u.pool.setAvailable(false)
+		}
+		u.unhealthy = true
+		slog.Warn("upstream became unhealthy", "upstream", u.Url.Host, "failed_checks", u.checkFailures)
+		metrics.SetUpstreamHealth(u.Mapping, u.Url.Host, false)
+	}
+}
+
+// ReportRequest records the result of a proxied request for the outlier detection.
+// Results of requests, which finish while the upstream is ejected, are ignored.
+func (u *Upstream) ReportRequest(success bool) {
+	if u.OutlierDetection == nil {
+		return
+	}
+	detection := u.OutlierDetection.WithDefaults()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.isEjected() {
+		return
+	}
+
+	if success {
+		u.failures = 0
+		if u.ejections > 0 && now().Sub(u.ejectedUntil) >= detection.MaxEjectionTime {
+			u.ejections = 0
+		}
+		return
+	}
+
+	u.failures++
+	if u.failures < detection.ConsecutiveFailures {
+		return
+	}
+
+	if !u.pool.eject(!u.unhealthy, detection.MaxEjectionPercent) {
+		u.failures = 0
+		slog.Warn("didn't eject upstream after consecutive failures, too many upstreams of the mapping are unavailable", "upstream", u.Url.Host)
+		return
+	}
+
+	duration := detection.BaseEjectionTime << u.ejections
+	if duration > detection.MaxEjectionTime || duration <= 0 {
+		duration = detection.MaxEjectionTime
+	}
+	u.failures = 0
+	u.ejections++
+	u.ejected = true
+	u.ejectedUntil = now().Add(duration)
+	slog.Warn("ejected upstream after consecutive failures", "upstream", u.Url.Host, "duration", duration, "ejections", u.ejections)
+	metrics.SetUpstreamHealth(u.Mapping, u.Url.Host, false)
+}
+
+// State is the health of an upstream shown by the admin endpoint
+type State struct {
+	Url                 string     `json:"url"`
+	Weight              int        `json:"weight"`
+	InFlight            int64      `json:"inFlight"`
+	Available           bool       `json:"available"`
+	Healthy             bool       `json:"healthy"`
+	Ejected             bool       `json:"ejected"`
+	EjectedUntil        *time.Time `json:"ejectedUntil,omitempty"`
+	Ejections           int        `json:"ejections"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+}
+
+func (u *Upstream) State() State {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	state := State{
+		Url:                 u.Url.String(),
+		Weight:              u.Weight,
+		InFlight:            u.InFlight(),
+		Healthy:             !u.unhealthy,
+		Ejected:             u.isEjected(),
+		Ejections:           u.ejections,
+		ConsecutiveFailures: u.failures,
+	}
+	state.Available = state.Healthy && !state.Ejected
+	if state.Ejected {
+		ejectedUntil := u.ejectedUntil
+		state.EjectedUntil = &ejectedUntil
+	}
+	return state
+}
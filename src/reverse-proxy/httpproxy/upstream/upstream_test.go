@@ -0,0 +1,248 @@
+package upstream
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestUpstream(detection *OutlierDetection) *Upstream {
+	URL, _ := url.Parse("http://upstream:8080")
+	u := NewUpstream(URL, 1)
+	u.OutlierDetection = detection
+	return u
+}
+
+// setNow moves the clock of the package to the returned time
+func setNow(t *testing.T) *time.Time {
+	current := time.Date(2023, 12, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+	return &current
+}
+
+func TestUpstream(t *testing.T) {
+	t.Run("ReportCheck", func(t *testing.T) {
+		t.Run("should become unhealthy and healthy again after the thresholds", func(t *testing.T) {
+			// given
+			u := newTestUpstream(nil)
+
+			// when
+			u.ReportCheck(false, 2, 3)
+			u.ReportCheck(false, 2, 3)
+			beforeThreshold := u.Available()
+			u.ReportCheck(false, 2, 3)
+			unhealthy := u.Available()
+			u.ReportCheck(true, 2, 3)
+			u.ReportCheck(true, 2, 3)
+
+			// then
+			assert.True(t, beforeThreshold)
+			assert.False(t, unhealthy)
+			assert.True(t, u.Available())
+		})
+
+		t.Run("should reset the failed checks after a successful one", func(t *testing.T) {
+			// given
+			u := newTestUpstream(nil)
+
+			// when
+			u.ReportCheck(false, 1, 2)
+			u.ReportCheck(true, 1, 2)
+			u.ReportCheck(false, 1, 2)
+
+			// then
+			assert.True(t, u.Available())
+		})
+	})
+
+	t.Run("ReportRequest", func(t *testing.T) {
+		t.Run("should ignore the requests without outlier detection", func(t *testing.T) {
+			// given
+			u := newTestUpstream(nil)
+
+			// when
+			for i := 0; i < 10; i++ {
+				u.ReportRequest(false)
+			}
+
+			// then
+			assert.True(t, u.Available())
+		})
+
+		t.Run("should eject the upstream after consecutive failures", func(t *testing.T) {
+			// given
+			current := setNow(t)
+			u := newTestUpstream(&OutlierDetection{ConsecutiveFailures: 3, BaseEjectionTime: time.Minute})
+
+			// when
+			u.ReportRequest(false)
+			u.ReportRequest(false)
+			u.ReportRequest(true)
+			u.ReportRequest(false)
+			u.ReportRequest(false)
+			interrupted := u.Available()
+			u.ReportRequest(false)
+
+			// then
+			assert.True(t, interrupted)
+			assert.False(t, u.Available())
+
+			state := u.State()
+			assert.True(t, state.Ejected)
+			assert.Equal(t, current.Add(time.Minute), *state.EjectedUntil)
+			assert.Equal(t, 1, state.Ejections)
+		})
+
+		t.Run("should bring the upstream back with backoff", func(t *testing.T) {
+			// given
+			current := setNow(t)
+			u := newTestUpstream(&OutlierDetection{ConsecutiveFailures: 1, BaseEjectionTime: time.Minute, MaxEjectionTime: 3 * time.Minute})
+
+			// when
+			u.ReportRequest(false)
+			*current = current.Add(time.Minute)
+			afterFirst := u.Available()
+
+			u.ReportRequest(false)
+			*current = current.Add(time.Minute)
+			beforeSecondEnds := u.Available()
+			*current = current.Add(time.Minute)
+			afterSecond := u.Available()
+
+			u.ReportRequest(false)
+			*current = current.Add(3 * time.Minute)
+			afterThird := u.Available()
+
+			// then
+			assert.True(t, afterFirst)
+			assert.False(t, beforeSecondEnds)
+			assert.True(t, afterSecond)
+			assert.True(t, afterThird)
+			assert.Equal(t, 3, u.State().Ejections)
+		})
+
+		t.Run("should reset the backoff after serving for the max ejection time", func(t *testing.T) {
+			// given
+			current := setNow(t)
+			u := newTestUpstream(&OutlierDetection{ConsecutiveFailures: 1, BaseEjectionTime: time.Minute, MaxEjectionTime: 2 * time.Minute})
+			u.ReportRequest(false)
+			*current = current.Add(time.Minute)
+
+			// when
+			u.ReportRequest(true)
+			beforeMax := u.State().Ejections
+			*current = current.Add(2 * time.Minute)
+			u.ReportRequest(true)
+
+			// then
+			assert.Equal(t, 1, beforeMax)
+			assert.Equal(t, 0, u.State().Ejections)
+		})
+
+		t.Run("should ignore the requests while the upstream is ejected", func(t *testing.T) {
+			// given
+			setNow(t)
+			u := newTestUpstream(&OutlierDetection{ConsecutiveFailures: 1})
+			u.ReportRequest(false)
+
+			// when
+			u.ReportRequest(false)
+
+			// then
+			state := u.State()
+			assert.Equal(t, 1, state.Ejections)
+			assert.Equal(t, 0, state.ConsecutiveFailures)
+		})
+	})
+
+	t.Run("Pool", func(t *testing.T) {
+		newPool := func(count int, detection *OutlierDetection) []*Upstream {
+			upstreams := make([]*Upstream, count)
+			for i := range upstreams {
+				upstreams[i] = newTestUpstream(detection)
+			}
+			NewPool(upstreams)
+			return upstreams
+		}
+
+		t.Run("should never eject the last available upstream", func(t *testing.T) {
+			// given
+			setNow(t)
+			upstreams := newPool(2, &OutlierDetection{ConsecutiveFailures: 1, MaxEjectionPercent: 100})
+
+			// when
+			upstreams[0].ReportRequest(false)
+			upstreams[1].ReportRequest(false)
+
+			// then
+			assert.False(t, upstreams[0].Available())
+			assert.True(t, upstreams[1].Available())
+			assert.Equal(t, 0, upstreams[1].State().Ejections)
+		})
+
+		t.Run("should count the upstreams failing the health checks as unavailable", func(t *testing.T) {
+			// given
+			setNow(t)
+			upstreams := newPool(2, &OutlierDetection{ConsecutiveFailures: 1, MaxEjectionPercent: 100})
+			upstreams[0].ReportCheck(false, 1, 1)
+
+			// when
+			upstreams[1].ReportRequest(false)
+
+			// then
+			assert.True(t, upstreams[1].Available())
+		})
+
+		t.Run("should eject at most the max ejection percent of the upstreams", func(t *testing.T) {
+			// given
+			setNow(t)
+			upstreams := newPool(4, &OutlierDetection{ConsecutiveFailures: 1, MaxEjectionPercent: 50})
+
+			// when
+			for _, u := range upstreams {
+				u.ReportRequest(false)
+			}
+
+			// then
+			assert.False(t, upstreams[0].Available())
+			assert.False(t, upstreams[1].Available())
+			assert.True(t, upstreams[2].Available())
+			assert.True(t, upstreams[3].Available())
+		})
+
+		t.Run("should eject again, once an upstream returned", func(t *testing.T) {
+			// given
+			current := setNow(t)
+			upstreams := newPool(2, &OutlierDetection{ConsecutiveFailures: 1, BaseEjectionTime: time.Minute})
+			upstreams[0].ReportRequest(false)
+			*current = current.Add(time.Minute)
+			upstreams[0].ReportCheck(false, 1, 1)
+			upstreams[0].ReportCheck(true, 1, 1)
+
+			// when
+			upstreams[1].ReportRequest(false)
+
+			// then
+			assert.True(t, upstreams[0].Available())
+			assert.False(t, upstreams[1].Available())
+		})
+	})
+
+	t.Run("State", func(t *testing.T) {
+		t.Run("should show the health of the upstream", func(t *testing.T) {
+			// given
+			u := newTestUpstream(nil)
+			u.Acquire()
+			u.ReportCheck(false, 1, 1)
+
+			// when
+			state := u.State()
+
+			// then
+			assert.Equal(t, State{Url: "http://upstream:8080", Weight: 1, InFlight: 1}, state)
+		})
+	})
+}
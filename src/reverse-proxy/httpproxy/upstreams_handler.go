@@ -0,0 +1,55 @@
+package httpproxy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/circuitbreaker"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/upstream"
+)
+
+// MappingState is the health of the upstreams and the circuit breaker of a mapping
+type MappingState struct {
+	Host           string               `json:"host"`
+	Path           string               `json:"path"`
+	CircuitBreaker circuitbreaker.State `json:"circuitBreaker,omitempty"`
+	Upstreams      []upstream.State     `json:"upstreams"`
+}
+
+func (mapping *RouteMapping) State() MappingState {
+	states := make([]upstream.State, len(mapping.upstreams))
+	for i, u := range mapping.upstreams {
+		states[i] = u.State()
+	}
+	state := MappingState{Host: mapping.config.Host, Path: mapping.config.Path, Upstreams: states}
+	if mapping.breaker != nil {
+		state.CircuitBreaker = mapping.breaker.State()
+	}
+	return state
+}
+
+type upstreamsHandler struct {
+	proxy Proxy
+}
+
+// NewUpstreamsHandler shows the health of the upstreams of all mappings of the proxy as JSON
+func NewUpstreamsHandler(proxy Proxy) http.Handler {
+	return &upstreamsHandler{proxy}
+}
+
+func (handler *upstreamsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	mappings := handler.proxy.Mappings()
+	states := make([]MappingState, len(mappings))
+	for i, mapping := range mappings {
+		states[i] = mapping.State()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(states)
+}
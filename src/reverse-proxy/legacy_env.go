@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyMappingsEnv held a whole config document with the mappings, before they were read from MAPPINGS
+const legacyMappingsEnv = "CONFIG_FILE"
+
+// legacyEnviron passes the mappings of CONFIG_FILE as MAPPINGS, so old deployments keep working.
+// It fails, if both are set or CONFIG_FILE has no mappings, instead of starting without them.
+func legacyEnviron(environ []string) ([]string, error) {
+	var legacy string
+	found, hasMappings := false, false
+	for _, variable := range environ {
+		key, value, _ := strings.Cut(variable, "=")
+		switch key {
+		case legacyMappingsEnv:
+			legacy, found = value, true
+		case "MAPPINGS":
+			hasMappings = true
+		}
+	}
+	if !found {
+		return environ, nil
+	}
+	if hasMappings {
+		return nil, errors.New("CONFIG_FILE and MAPPINGS must not both be set, CONFIG_FILE is deprecated")
+	}
+
+	var document struct {
+		Mappings yaml.Node `yaml:"mappings"`
+	}
+	if err := yaml.Unmarshal([]byte(legacy), &document); err != nil {
+		return nil, fmt.Errorf("could not parse CONFIG_FILE: %w", err)
+	}
+	if document.Mappings.Kind != yaml.SequenceNode {
+		return nil, errors.New("CONFIG_FILE has no mappings-list")
+	}
+	mappings, err := yaml.Marshal(&document.Mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Warn("CONFIG_FILE is deprecated, the mappings are read from MAPPINGS instead")
+	return append(environ, "MAPPINGS="+string(mappings)), nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegacyEnviron(t *testing.T) {
+	t.Run("should read the mappings of CONFIG_FILE", func(t *testing.T) {
+		// given
+		environ := []string{"PORT=8080", "CONFIG_FILE=\nmappings:\n  - path: /api/v1/books*\n    hosts:\n      - http://book:8080\n"}
+
+		// when
+		environ, err := legacyEnviron(environ)
+		assert.NoError(t, err)
+		config := ApplicationConfig{}
+		err = (&appconfig.Loader{Name: "reverse-proxy", Environ: environ}).Load(&config)
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, config.Mappings, 1)
+		assert.Equal(t, "/api/v1/books*", config.Mappings[0].Path)
+		assert.Equal(t, []string{"http://book:8080"}, config.Mappings[0].Hosts)
+	})
+
+	t.Run("should keep the environment without CONFIG_FILE", func(t *testing.T) {
+		// when
+		environ, err := legacyEnviron([]string{"MAPPINGS=[]"})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"MAPPINGS=[]"}, environ)
+	})
+
+	t.Run("should fail, if CONFIG_FILE can't be used", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			environ []string
+			err     string
+		}{
+			{"with MAPPINGS", []string{"CONFIG_FILE=mappings: []", "MAPPINGS=[]"}, "must not both be set"},
+			{"without mappings", []string{"CONFIG_FILE=port: 8080"}, "CONFIG_FILE has no mappings-list"},
+			{"invalid", []string{"CONFIG_FILE=mappings: ["}, "could not parse CONFIG_FILE"},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				// when
+				_, err := legacyEnviron(test.environ)
+
+				// then
+				assert.ErrorContains(t, err, test.err)
+			})
+		}
+	})
+}
@@ -1,98 +1,132 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"strings"
+	"time"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/lifecycle"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy"
-	"github.com/caarlos0/env/v10"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/auth"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/cache"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/ratelimit"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/reload"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/tlsserver"
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
-type RouteMapping struct {
-	Host  string   `yaml:"host"`
-	Path  string   `yaml:"path"`
-	Hosts []string `yaml:"hosts"`
+// Mappings are read from the mappings-list of the config file or the YAML in the MAPPINGS-variable
+type Mappings []httpproxy.MappingConfig
+
+func (mappings *Mappings) UnmarshalText(text []byte) error {
+	return yaml.Unmarshal(text, (*[]httpproxy.MappingConfig)(mappings))
 }
 
 type ApplicationConfig struct {
-	Mappings []RouteMapping `yaml:"mappings"`
+	Mappings       Mappings              `env:"MAPPINGS" validate:"min=1"`
+	Port           uint16                `env:"PORT" envDefault:"8080" validate:"min=1"`
+	MetricsPort    uint16                `env:"METRICS_PORT" envDefault:"9090" validate:"min=1"`
+	ReloadInterval time.Duration         `env:"RELOAD_INTERVAL" envDefault:"5s"`
+	Log            logger.Config         `envPrefix:"LOG_"`
+	TLS            tlsserver.Config      `envPrefix:"TLS_"`
+	Cache          cache.StoreConfig     `envPrefix:"CACHE_"`
+	RateLimit      ratelimit.StoreConfig `envPrefix:"RATELIMIT_"`
+	Auth           auth.Config           `envPrefix:"AUTH_"`
+	Lifecycle      lifecycle.Config
 }
 
-type EnvConfig struct {
-	ConfigFilePath string `env:"CONFIG_FILE_PATH" envDefault:"config.yaml"`
-	ConfigFile     string `env:"CONFIG_FILE"`
-	Port           uint16 `env:"PORT" envDefault:"8080"`
-}
+func main() {
+	godotenv.Load()
 
-func LoadConfigFromFile(path string) (*ApplicationConfig, error) {
-	f, err := os.Open(path)
+	config := ApplicationConfig{}
+	loader := appconfig.NewLoader("reverse-proxy")
+	environ, err := legacyEnviron(loader.Environ)
 	if err != nil {
-		return nil, err
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
-	defer f.Close()
-
-	var config ApplicationConfig
-	if err := yaml.NewDecoder(f).Decode(&config); err != nil {
-		return nil, err
+	loader.Environ = environ
+	loader.DefaultFile = "config.yaml"
+	if err := loader.Load(&config); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
-
-	return &config, nil
-}
-
-func LoadConfigFromEnv(content string) (*ApplicationConfig, error) {
-	f := strings.NewReader(content)
-
-	var config ApplicationConfig
-	if err := yaml.NewDecoder(f).Decode(&config); err != nil {
-		return nil, err
+	logger.SetDefault(config.Log)
+
+	// the stores are shared by the mappings and kept on reloads, the buckets of the rate limits
+	// are shared with the other replicas, if a Redis server is configured
+	cacheStore := cache.NewStore(config.Cache)
+	dependencies := httpproxy.Dependencies{
+		RateLimitStore: ratelimit.NewStore(config.RateLimit),
+		CacheStore:     cacheStore,
 	}
 
-	return &config, nil
-}
+	transport := metrics.RoundTripper(http.DefaultTransport)
+	proxy := httpproxy.NewHTTPProxy(&http.Client{Transport: transport})
+	httpUtilProxy := httpproxy.NewHTTPUtilProxy(transport)
 
-func main() {
-	godotenv.Load()
+	// the whole configuration is loaded again, so the mappings are validated like on startup
+	reloader := reload.NewReloader(func() ([]*httpproxy.RouteMapping, error) {
+		config := ApplicationConfig{}
+		if err := loader.Load(&config); err != nil {
+			return nil, err
+		}
+		// the public key is read again, so a new key is used after a reload
+		var verifier *auth.Verifier
+		if config.Auth.Enabled() {
+			var err error
+			if verifier, err = auth.NewVerifier(config.Auth); err != nil {
+				return nil, err
+			}
+		}
+		// the verifier is only used, if all mappings were created
+		dependencies := dependencies
+		dependencies.Verifier = verifier
+		return httpproxy.NewRouteMappings(config.Mappings, dependencies)
+	}, proxy, httpUtilProxy)
+	if err := reloader.Reload(); err != nil {
+		log.Fatalf("Could not parse application config: %s", err.Error())
+	}
 
-	envConfig := EnvConfig{}
-	if err := env.Parse(&envConfig); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	go reloader.WatchSignal(context.Background())
+	if file := loader.File(); file != "" && config.ReloadInterval > 0 {
+		go reloader.WatchFile(context.Background(), file, config.ReloadInterval)
 	}
 
-	var config *ApplicationConfig
-	if envConfig.ConfigFile != "" {
-		var err error
-		config, err = LoadConfigFromEnv(envConfig.ConfigFile)
+	// the metrics and admin endpoints are served on their own port, so they aren't reachable through the proxy
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", metrics.Handler())
+	adminMux.Handle("/admin/mappings", reloader)
+	adminMux.Handle("/admin/upstreams", httpproxy.NewUpstreamsHandler(httpUtilProxy))
+	adminMux.Handle("/admin/cache", cache.NewPurgeHandler(cacheStore))
+	// the listeners are shut down gracefully, so the in-flight requests of both listeners are finished
+	runner := lifecycle.NewRunner(config.Lifecycle)
+	runner.AddHTTPServer("admin", fmt.Sprintf("0.0.0.0:%d", config.MetricsPort), adminMux)
+
+	if config.TLS.Enabled() {
+		store, err := tlsserver.NewCertStore(config.TLS.CertDir, config.TLS.DefaultCert)
 		if err != nil {
-			log.Fatalf("could not load application configuration: %s", err.Error())
+			log.Fatalf("Could not load the certificates: %s", err.Error())
 		}
-	} else {
-		var err error
-		config, err = LoadConfigFromFile(envConfig.ConfigFilePath)
-		if err != nil {
-			log.Fatalf("could not load application configuration: %s", err.Error())
+		if config.TLS.ReloadInterval > 0 {
+			go store.Watch(context.Background(), config.TLS.ReloadInterval)
 		}
-	}
 
-	proxy := httpproxy.NewHTTPProxy(http.DefaultClient)
-	httpUtilProxy := httpproxy.NewHTTPUtilProxy(http.DefaultTransport)
+		// ListenAndServeTLS configures HTTP/2, the certificates come from the store
+		runner.AddServer("tls", config.TLS.Server(httpUtilProxy, store.TLSConfig()))
+	}
 
-	for _, mapping := range config.Mappings {
-		if err := httpproxy.AddToProxy(proxy, mapping.Host, mapping.Path, mapping.Hosts); err != nil {
-			log.Fatalf("Could not parse application config #{err.Error()}")
-		}
-		if err := httpproxy.AddToProxy(httpUtilProxy, mapping.Host, mapping.Path, mapping.Hosts); err != nil {
-			log.Fatalf("Could not parse application config #{err.Error()}")
-		}
+	var handler http.Handler = httpUtilProxy
+	if config.TLS.RedirectHTTP {
+		handler = config.TLS.RedirectHandler()
 	}
+	runner.AddHTTPServer("http", fmt.Sprintf("0.0.0.0:%d", config.Port), handler)
 
-	log.Println("Server Started!")
-	addr := fmt.Sprintf("0.0.0.0:%d", envConfig.Port)
-	if err := http.ListenAndServe(addr, httpUtilProxy); err != nil {
-		log.Fatalf("error while listen and serve: %s", err.Error())
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatalf("error while running the servers: %s", err.Error())
 	}
 }
@@ -0,0 +1,144 @@
+// Package reload replaces the mappings of the proxies at runtime, when the config file changes or SIGHUP is received.
+package reload
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy"
+)
+
+// LoadFunc loads and validates the complete set of mappings
+type LoadFunc func() ([]*httpproxy.RouteMapping, error)
+
+// Status is shown by the admin endpoint
+type Status struct {
+	Mappings        []httpproxy.MappingConfig `json:"mappings"`
+	LoadedAt        time.Time                 `json:"loadedAt"`
+	LastReloadAt    time.Time                 `json:"lastReloadAt"`
+	LastReloadError string                    `json:"lastReloadError,omitempty"`
+}
+
+type Reloader struct {
+	load    LoadFunc
+	proxies []httpproxy.Proxy
+
+	mu       sync.Mutex
+	status   Status
+	mappings []*httpproxy.RouteMapping
+}
+
+func NewReloader(load LoadFunc, proxies ...httpproxy.Proxy) *Reloader {
+	return &Reloader{load: load, proxies: proxies}
+}
+
+// Reload loads the mappings and swaps them into all proxies. If they are invalid,
+// the active mappings are kept and the error is shown by the admin endpoint.
+// Requests, which are already served, aren't affected. The health checks of the new mappings
+// are started, the ones of the replaced mappings are stopped and their idle connections are closed.
+func (reloader *Reloader) Reload() error {
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+
+	reloader.status.LastReloadAt = time.Now()
+
+	mappings, err := reloader.load()
+	if err != nil {
+		reloader.status.LastReloadError = err.Error()
+		return err
+	}
+
+	for _, mapping := range mappings {
+		mapping.StartHealthChecks()
+	}
+	for _, proxy := range reloader.proxies {
+		proxy.Replace(mappings)
+	}
+	for _, mapping := range reloader.mappings {
+		mapping.StopHealthChecks()
+		mapping.CloseIdleConnections()
+	}
+	reloader.mappings = mappings
+
+	configs := make([]httpproxy.MappingConfig, len(mappings))
+	for i, mapping := range mappings {
+		configs[i] = mapping.Config()
+	}
+	reloader.status.Mappings = configs
+	reloader.status.LoadedAt = reloader.status.LastReloadAt
+	reloader.status.LastReloadError = ""
+	return nil
+}
+
+func (reloader *Reloader) Status() Status {
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+	return reloader.status
+}
+
+// ServeHTTP shows the status as JSON
+func (reloader *Reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reloader.Status())
+}
+
+// WatchSignal reloads on every SIGHUP until the context is done
+func (reloader *Reloader) WatchSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			reloader.reloadAndLog("SIGHUP")
+		}
+	}
+}
+
+// WatchFile polls the modification time and size of the file and reloads, when they change.
+// Polling also notices, when kubernetes swaps the symlink of a mounted ConfigMap.
+func (reloader *Reloader) WatchFile(ctx context.Context, path string, interval time.Duration) {
+	last, _ := os.Stat(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := os.Stat(path)
+		if err != nil || (last != nil && current.ModTime().Equal(last.ModTime()) && current.Size() == last.Size()) {
+			continue
+		}
+		last = current
+		reloader.reloadAndLog("file changed")
+	}
+}
+
+func (reloader *Reloader) reloadAndLog(reason string) {
+	if err := reloader.Reload(); err != nil {
+		slog.Error("could not reload the mappings, keeping the active ones", "reason", reason, "error", err)
+		return
+	}
+	slog.Info("reloaded the mappings", "reason", reason, "mappings", len(reloader.Status().Mappings))
+}
@@ -0,0 +1,173 @@
+package reload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/reverse-proxy/httpproxy/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func loadPaths(paths ...string) LoadFunc {
+	return func() ([]*httpproxy.RouteMapping, error) {
+		configs := make([]httpproxy.MappingConfig, len(paths))
+		for i, path := range paths {
+			configs[i] = httpproxy.MappingConfig{Host: "*", Path: path, Hosts: []string{"http://host:8080"}}
+		}
+		return httpproxy.NewRouteMappings(configs, httpproxy.Dependencies{})
+	}
+}
+
+func TestReloader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roundTripper := mocks.NewMockRoundTripper(ctrl)
+
+	t.Run("should replace the mappings of all proxies", func(t *testing.T) {
+		// given
+		first := httpproxy.NewHTTPUtilProxy(roundTripper)
+		second := httpproxy.NewHTTPUtilProxy(roundTripper)
+		reloader := NewReloader(loadPaths("/a", "/b"), first, second)
+
+		// when
+		err := reloader.Reload()
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, first.Mappings(), 2)
+		assert.Len(t, second.Mappings(), 2)
+
+		status := reloader.Status()
+		assert.Equal(t, "/b", status.Mappings[1].Path)
+		assert.False(t, status.LoadedAt.IsZero())
+		assert.Equal(t, status.LoadedAt, status.LastReloadAt)
+		assert.Empty(t, status.LastReloadError)
+	})
+
+	t.Run("should keep the active mappings if the new ones are invalid", func(t *testing.T) {
+		// given
+		proxy := httpproxy.NewHTTPUtilProxy(roundTripper)
+		valid := true
+		reloader := NewReloader(func() ([]*httpproxy.RouteMapping, error) {
+			if valid {
+				return loadPaths("/a")()
+			}
+			return nil, errors.New("invalid mappings")
+		}, proxy)
+		reloader.Reload()
+		loadedAt := reloader.Status().LoadedAt
+
+		// when
+		valid = false
+		err := reloader.Reload()
+
+		// then
+		assert.EqualError(t, err, "invalid mappings")
+		assert.Len(t, proxy.Mappings(), 1)
+
+		status := reloader.Status()
+		assert.Len(t, status.Mappings, 1)
+		assert.Equal(t, loadedAt, status.LoadedAt)
+		assert.True(t, status.LastReloadAt.After(loadedAt) || status.LastReloadAt.Equal(loadedAt))
+		assert.Equal(t, "invalid mappings", status.LastReloadError)
+	})
+
+	t.Run("should serve the status as JSON", func(t *testing.T) {
+		// given
+		reloader := NewReloader(loadPaths("/a"))
+		reloader.Reload()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/admin/mappings", nil)
+
+		// when
+		reloader.ServeHTTP(w, r)
+
+		// then
+		var status Status
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+		assert.Equal(t, []httpproxy.MappingConfig{{Host: "*", Path: "/a", Hosts: []string{"http://host:8080"}}}, status.Mappings)
+	})
+
+	t.Run("should only allow GET on the status", func(t *testing.T) {
+		// given
+		reloader := NewReloader(loadPaths("/a"))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/admin/mappings", nil)
+
+		// when
+		reloader.ServeHTTP(w, r)
+
+		// then
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("should reload when the file changes", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		os.WriteFile(path, []byte("a"), 0o600)
+
+		var reloads atomic.Int32
+		reloader := NewReloader(func() ([]*httpproxy.RouteMapping, error) {
+			reloads.Add(1)
+			return loadPaths("/a")()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go reloader.WatchFile(ctx, path, 10*time.Millisecond)
+
+		// when
+		time.Sleep(50 * time.Millisecond)
+		unchanged := reloads.Load()
+		os.WriteFile(path, []byte("ab"), 0o600)
+
+		// then
+		assert.Equal(t, int32(0), unchanged)
+		assert.Eventually(t, func() bool { return reloads.Load() == 1 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should start the health checks of the new mappings and stop the replaced ones", func(t *testing.T) {
+		// given
+		var checks atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			checks.Add(1)
+		}))
+		defer server.Close()
+
+		withHealthCheck := true
+		reloader := NewReloader(func() ([]*httpproxy.RouteMapping, error) {
+			config := httpproxy.MappingConfig{Host: "*", Path: "/a", Hosts: []string{server.URL}}
+			if withHealthCheck {
+				config.HealthCheck = &healthcheck.Config{Interval: 10 * time.Millisecond}
+			}
+			return httpproxy.NewRouteMappings([]httpproxy.MappingConfig{config}, httpproxy.Dependencies{})
+		})
+
+		// when
+		reloader.Reload()
+		assert.Eventually(t, func() bool { return checks.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+		withHealthCheck = false
+		reloader.Reload()
+		time.Sleep(20 * time.Millisecond)
+		stopped := checks.Load()
+		time.Sleep(50 * time.Millisecond)
+
+		// then
+		assert.Equal(t, stopped, checks.Load())
+	})
+}
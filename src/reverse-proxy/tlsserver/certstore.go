@@ -0,0 +1,181 @@
+package tlsserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CertStore holds the certificate pairs of a directory, a pair is a <name>.crt file with the
+// chain and a <name>.key file with the private key. The certificate of a connection is selected
+// by the server name (SNI) of the client.
+type CertStore struct {
+	dir         string
+	defaultName string
+
+	mu          sync.RWMutex
+	byName      map[string]*tls.Certificate
+	fallback    *tls.Certificate
+	fingerprint string
+}
+
+// NewCertStore loads the certificates of the directory. defaultName is the pair, which is used
+// for clients without a known server name, by default the first pair in the directory.
+func NewCertStore(dir string, defaultName string) (*CertStore, error) {
+	store := &CertStore{dir: dir, defaultName: defaultName}
+	if err := store.Load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Load reads the certificates of the directory again. If any of them is invalid,
+// the active certificates are kept.
+func (store *CertStore) Load() error {
+	fingerprint, err := store.currentFingerprint()
+	if err != nil {
+		return err
+	}
+
+	keyFiles, err := filepath.Glob(filepath.Join(store.dir, "*.key"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(keyFiles)
+
+	byName := map[string]*tls.Certificate{}
+	var fallback *tls.Certificate
+	for _, keyFile := range keyFiles {
+		name := strings.TrimSuffix(filepath.Base(keyFile), ".key")
+		certificate, err := loadPair(filepath.Join(store.dir, name+".crt"), keyFile)
+		if err != nil {
+			return fmt.Errorf("certificate %s: %w", name, err)
+		}
+
+		for _, serverName := range serverNames(certificate.Leaf) {
+			if _, ok := byName[serverName]; !ok {
+				byName[serverName] = certificate
+			}
+		}
+		if fallback == nil || name == store.defaultName {
+			fallback = certificate
+		}
+	}
+
+	if fallback == nil {
+		return fmt.Errorf("there are no certificates in %s", store.dir)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.byName = byName
+	store.fallback = fallback
+	store.fingerprint = fingerprint
+	return nil
+}
+
+func loadPair(certFile string, keyFile string) (*tls.Certificate, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0]); err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// serverNames are the DNS names of the certificate or its common name, if it has none
+func serverNames(leaf *x509.Certificate) []string {
+	names := leaf.DNSNames
+	if len(names) == 0 && leaf.Subject.CommonName != "" {
+		names = []string{leaf.Subject.CommonName}
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	return lowered
+}
+
+// GetCertificate selects the certificate of the server name, a wildcard certificate or the default one
+func (store *CertStore) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if certificate, ok := store.byName[name]; ok {
+		return certificate, nil
+	}
+	if _, parent, ok := strings.Cut(name, "."); ok {
+		if certificate, ok := store.byName["*."+parent]; ok {
+			return certificate, nil
+		}
+	}
+	return store.fallback, nil
+}
+
+// TLSConfig is the config of the listener, HTTP/2 is negotiated by the http.Server
+func (store *CertStore) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: store.GetCertificate}
+}
+
+// Watch polls the directory and loads the certificates again, when a file changes.
+// Polling also notices, when kubernetes swaps the symlink of a mounted secret.
+func (store *CertStore) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fingerprint, err := store.currentFingerprint()
+		store.mu.RLock()
+		unchanged := fingerprint == store.fingerprint
+		store.mu.RUnlock()
+		if err != nil || unchanged {
+			continue
+		}
+
+		if err := store.Load(); err != nil {
+			slog.Error("could not reload the certificates, keeping the active ones", "dir", store.dir, "error", err)
+			continue
+		}
+		slog.Info("reloaded the certificates", "dir", store.dir)
+	}
+}
+
+// currentFingerprint summarizes the names, sizes and modification times of the files in the directory
+func (store *CertStore) currentFingerprint() (string, error) {
+	entries, err := os.ReadDir(store.dir)
+	if err != nil {
+		return "", err
+	}
+
+	var fingerprint strings.Builder
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".crt") && !strings.HasSuffix(entry.Name(), ".key") {
+			continue
+		}
+		// Stat follows the symlinks, Info wouldn't
+		info, err := os.Stat(filepath.Join(store.dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&fingerprint, "%s:%d:%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return fingerprint.String(), nil
+}
@@ -0,0 +1,159 @@
+package tlsserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writePair writes a self-signed certificate for the DNS names as <name>.crt and <name>.key into the directory
+func writePair(t *testing.T, dir string, name string, commonName string, dnsNames ...string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0o600))
+}
+
+func selected(t *testing.T, store *CertStore, serverName string) string {
+	t.Helper()
+
+	certificate, err := store.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+	assert.NoError(t, err)
+	return certificate.Leaf.Subject.CommonName
+}
+
+func TestCertStore(t *testing.T) {
+	t.Run("should select the certificate by the server name", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		writePair(t, dir, "books", "books", "books.example.com")
+		writePair(t, dir, "users", "users", "users.example.com", "accounts.example.com")
+		writePair(t, dir, "wildcard", "wildcard", "*.example.com")
+		writePair(t, dir, "legacy", "legacy.example.org")
+
+		// when
+		store, err := NewCertStore(dir, "")
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "books", selected(t, store, "books.example.com"))
+		assert.Equal(t, "users", selected(t, store, "ACCOUNTS.example.com."))
+		assert.Equal(t, "wildcard", selected(t, store, "web.example.com"))
+		assert.Equal(t, "legacy.example.org", selected(t, store, "legacy.example.org"))
+	})
+
+	t.Run("should fall back to the default certificate", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		writePair(t, dir, "a", "a", "a.example.com")
+		writePair(t, dir, "b", "b", "b.example.com")
+
+		// when
+		firstStore, _ := NewCertStore(dir, "")
+		defaultStore, _ := NewCertStore(dir, "b")
+
+		// then
+		assert.Equal(t, "a", selected(t, firstStore, ""))
+		assert.Equal(t, "a", selected(t, firstStore, "unknown.example.com"))
+		assert.Equal(t, "b", selected(t, defaultStore, ""))
+	})
+
+	t.Run("should reject a directory without certificates", func(t *testing.T) {
+		// when
+		_, err := NewCertStore(t.TempDir(), "")
+
+		// then
+		assert.ErrorContains(t, err, "there are no certificates in")
+	})
+
+	t.Run("should keep the certificates, if a pair is invalid", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		writePair(t, dir, "books", "books", "books.example.com")
+		store, _ := NewCertStore(dir, "")
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken.key"), []byte("no key"), 0o600))
+
+		// when
+		err := store.Load()
+
+		// then
+		assert.ErrorContains(t, err, "certificate broken")
+		assert.Equal(t, "books", selected(t, store, "books.example.com"))
+	})
+
+	t.Run("should reload the certificates, when a file changes", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		writePair(t, dir, "books", "old", "books.example.com")
+		store, _ := NewCertStore(dir, "")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go store.Watch(ctx, 10*time.Millisecond)
+
+		// when
+		writePair(t, dir, "books", "new", "books.example.com")
+		// the modification time of the files might not change on coarse file systems
+		future := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(filepath.Join(dir, "books.crt"), future, future))
+
+		// then
+		assert.Eventually(t, func() bool {
+			return selected(t, store, "books.example.com") == "new"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should serve TLS with the selected certificate", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		writePair(t, dir, "books", "books", "books.example.com")
+		store, _ := NewCertStore(dir, "")
+
+		listener, err := tls.Listen("tcp", "127.0.0.1:0", store.TLSConfig())
+		assert.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			conn, err := listener.Accept()
+			if err == nil {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}
+		}()
+
+		// when
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{ServerName: "books.example.com", InsecureSkipVerify: true})
+
+		// then
+		assert.NoError(t, err)
+		defer conn.Close()
+		assert.Equal(t, "books", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	})
+}
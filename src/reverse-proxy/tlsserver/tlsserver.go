@@ -0,0 +1,79 @@
+// Package tlsserver terminates TLS for the proxy with the certificates of a directory.
+package tlsserver
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// CertDir enables the TLS listener, it holds the <name>.crt and <name>.key files
+	CertDir        string        `env:"CERT_DIR"`
+	DefaultCert    string        `env:"DEFAULT_CERT"`
+	Port           uint16        `env:"PORT" envDefault:"8443" validate:"min=1"`
+	ReloadInterval time.Duration `env:"RELOAD_INTERVAL" envDefault:"30s"`
+	// RedirectHTTP redirects the requests of the plain listener to HTTPS instead of proxying them
+	RedirectHTTP bool `env:"REDIRECT_HTTP" envDefault:"false"`
+	// PublicHost and PublicPort are the address, under which the clients reach the TLS listener, e.g. behind
+	// a load balancer or a port mapping. The redirect uses the host of the request and Port without them.
+	PublicHost string `env:"PUBLIC_HOST"`
+	PublicPort uint16 `env:"PUBLIC_PORT"`
+	// ReadTimeout limits reading a request with its body, WriteTimeout writing its response, 0 disables them
+	ReadTimeout  time.Duration `env:"READ_TIMEOUT" envDefault:"30s"`
+	WriteTimeout time.Duration `env:"WRITE_TIMEOUT" envDefault:"60s"`
+}
+
+func (config Config) Enabled() bool {
+	return config.CertDir != ""
+}
+
+func (config Config) Validate() error {
+	if config.RedirectHTTP && !config.Enabled() {
+		return errors.New("REDIRECT_HTTP needs a CERT_DIR")
+	}
+	return nil
+}
+
+// Server creates the TLS listener of the handler with the timeouts of the config
+func (config Config) Server(handler http.Handler, tlsConfig *tls.Config) *http.Server {
+	return &http.Server{
+		Addr:         fmt.Sprintf("0.0.0.0:%d", config.Port),
+		Handler:      handler,
+		TLSConfig:    tlsConfig,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+	}
+}
+
+// RedirectHandler redirects the requests to the public address of the TLS listener
+func (config Config) RedirectHandler() http.Handler {
+	port := config.PublicPort
+	if port == 0 {
+		port = config.Port
+	}
+	return RedirectHandler(config.PublicHost, port)
+}
+
+// RedirectHandler redirects every request permanently to the same URL on the TLS port. The host of the
+// request is kept, if host is empty.
+func RedirectHandler(host string, port uint16) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := host
+		if host == "" {
+			host = r.Host
+		}
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if port != 443 {
+			host = net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(int(port)))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,66 @@
+package tlsserver
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		target string
+		host   string
+		want   string
+	}{
+		{"should redirect to the tls port", Config{Port: 8443}, "/books?page=2", "example.com:8080", "https://example.com:8443/books?page=2"},
+		{"should omit the default port", Config{Port: 443}, "/books", "example.com", "https://example.com/books"},
+		{"should keep IPv6 hosts", Config{Port: 8443}, "/", "[::1]:8080", "https://[::1]:8443/"},
+		{"should redirect to the public port", Config{Port: 8443, PublicPort: 443}, "/books", "example.com:8080", "https://example.com/books"},
+		{"should redirect to the public host", Config{Port: 8443, PublicHost: "books.example.com", PublicPort: 8000}, "/books", "localhost:8080", "https://books.example.com:8000/books"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", test.target, nil)
+			r.Host = test.host
+
+			// when
+			test.config.RedirectHandler().ServeHTTP(w, r)
+
+			// then
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, test.want, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("should create the server with the timeouts", func(t *testing.T) {
+		// given
+		config := Config{Port: 8443, ReadTimeout: 30 * time.Second, WriteTimeout: time.Minute}
+		tlsConfig := &tls.Config{}
+
+		// when
+		server := config.Server(http.NotFoundHandler(), tlsConfig)
+
+		// then
+		assert.Equal(t, "0.0.0.0:8443", server.Addr)
+		assert.Same(t, tlsConfig, server.TLSConfig)
+		assert.Equal(t, 30*time.Second, server.ReadTimeout)
+		assert.Equal(t, time.Minute, server.WriteTimeout)
+	})
+
+	t.Run("should only redirect with a certificate directory", func(t *testing.T) {
+		assert.Error(t, Config{RedirectHTTP: true}.Validate())
+		assert.NoError(t, Config{RedirectHTTP: true, CertDir: "/certs"}.Validate())
+		assert.NoError(t, Config{}.Validate())
+	})
+}
@@ -9,6 +9,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -38,15 +39,15 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // ResetDatabase mocks base method.
-func (m *MockRepository) ResetDatabase() error {
+func (m *MockRepository) ResetDatabase(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetDatabase")
+	ret := m.ctrl.Call(m, "ResetDatabase", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ResetDatabase indicates an expected call of ResetDatabase.
-func (mr *MockRepositoryMockRecorder) ResetDatabase() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ResetDatabase(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetDatabase", reflect.TypeOf((*MockRepository)(nil).ResetDatabase))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetDatabase", reflect.TypeOf((*MockRepository)(nil).ResetDatabase), ctx)
 }
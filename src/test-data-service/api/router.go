@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/openapi"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/test-data-service/controller"
@@ -19,8 +20,13 @@ func New(
 ) *Router {
 	router := router.New()
 
+	router.GET("/openapi.json", openapi.Handler(router, openapi.Info{Title: "Test-Data-Service", Version: "1.0.0"}))
 	router.GET("/health", healthController.ProvideHealth)
-	router.POST("/api/v1/reset", controller.ResetDatabase)
+	router.GET("/health/live", healthController.ProvideLiveness)
+	router.GET("/health/ready", healthController.ProvideReadiness)
+	router.POST("/api/v1/reset", controller.ResetDatabase).
+		Describe("Resets the database to the test data").
+		Response(http.StatusOK, nil)
 
 	return &Router{router}
 }
@@ -19,8 +19,8 @@ func TestRouter(t *testing.T) {
 	router := New(controller, healthController)
 
 	t.Run("/health", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not GET", func(t *testing.T) {
-			tests := []string{"HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not GET", func(t *testing.T) {
+			tests := []string{"POST", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 			for _, test := range tests {
 				// given
 				w := httptest.NewRecorder()
@@ -30,7 +30,7 @@ func TestRouter(t *testing.T) {
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -53,9 +53,9 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/reset", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not POST", func(t *testing.T) {
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not POST", func(t *testing.T) {
 
-			tests := []string{"GET", "HEAD", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+			tests := []string{"GET", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 
 			for _, test := range tests {
 				// given
@@ -66,7 +66,7 @@ func TestRouter(t *testing.T) {
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
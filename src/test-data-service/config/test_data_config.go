@@ -3,7 +3,7 @@ package config
 import "os"
 
 type TestDataConfig struct {
-	UserPassword string `env:"USER_PASSWORD,notEmpty"`
+	UserPassword string `env:"USER_PASSWORD,notEmpty" secret:"true"`
 	FilePath     string `env:"FILE_PATH"`
 	File         string `env:"FILE"`
 }
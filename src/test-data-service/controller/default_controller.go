@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"log/slog"
 	"net/http"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/test-data-service/repository"
 )
 
@@ -15,9 +17,10 @@ func NewDefaultController(repository repository.Repository) *DefaultController {
 }
 
 func (c *DefaultController) ResetDatabase(w http.ResponseWriter, r *http.Request) {
-	err := c.repository.ResetDatabase()
+	err := c.repository.ResetDatabase(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "could not reset the database", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 }
@@ -25,7 +25,7 @@ func TestDefaultController(t *testing.T) {
 
 			repository.
 				EXPECT().
-				ResetDatabase().
+				ResetDatabase(gomock.Any()).
 				Return(errors.New("error")).
 				Times(1)
 
@@ -43,7 +43,7 @@ func TestDefaultController(t *testing.T) {
 
 			repository.
 				EXPECT().
-				ResetDatabase().
+				ResetDatabase(gomock.Any()).
 				Return(nil).
 				Times(1)
 
@@ -1,58 +1,88 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/crypto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/database"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/lifecycle"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/tracing"
 	router "github.com/akatranlp/hsfl-master-ai-cloud-engineering/test-data-service/api"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/test-data-service/config"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/test-data-service/controller"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/test-data-service/repository"
-	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 )
 
 type ApplicationConfig struct {
 	Database    database.PsqlConfig   `envPrefix:"POSTGRES_"`
-	Port        uint16                `env:"PORT" envDefault:"8080"`
+	Port        uint16                `env:"PORT" envDefault:"8080" validate:"min=1"`
+	MetricsPort uint16                `env:"METRICS_PORT" envDefault:"9090" validate:"min=1"`
 	TestData    config.TestDataConfig `envPrefix:"TEST_DATA_"`
 	ResetOnInit bool                  `env:"RESET_ON_INIT" envDefault:"false"`
+	HTTP        middleware.Config     `envPrefix:"HTTP_"`
+	Health      health.Config         `envPrefix:"HEALTH_"`
+	Tracing     tracing.Config        `envPrefix:"TRACING_"`
+	Log         logger.Config         `envPrefix:"LOG_"`
+	Lifecycle   lifecycle.Config
 }
 
 func main() {
 	godotenv.Load()
 
 	config := ApplicationConfig{}
-	if err := env.Parse(&config); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	if err := appconfig.Load("test-data-service", &config); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
 
+	logger.SetDefault(config.Log)
+
+	runner := lifecycle.NewRunner(config.Lifecycle)
+
+	tracerProvider, err := tracing.NewProvider(context.Background(), "test-data-service", config.Tracing)
+	if err != nil {
+		log.Fatalf("could not create the tracer provider: %s", err.Error())
+	}
+	runner.AddCloser("tracer provider", tracerProvider)
+
+	checker := health.NewChecker(config.Health)
+	runner.OnShutdown(checker.Shutdown)
+
 	hasher := crypto.NewBcryptHasher()
 
 	repository, err := repository.NewPsqlRepository(config.Database, config.TestData, hasher)
 	if err != nil {
 		log.Fatalf("could not create repository: %s", err.Error())
 	}
+	runner.AddCloser("repository", repository)
+	if err := metrics.RegisterDBStats("test-data", repository); err != nil {
+		log.Fatalf("could not register the database metrics: %s", err.Error())
+	}
 
 	if config.ResetOnInit {
-		if err := repository.ResetDatabase(); err != nil {
+		if err := repository.ResetDatabase(context.Background()); err != nil {
 			log.Fatalf("could not reset database: %s", err.Error())
 		}
 	}
 
 	controller := controller.NewDefaultController(repository)
 
-	healthController := health.NewDefaultController()
+	checker.Register("database", repository.Ping)
+	healthController := health.NewDefaultController(checker)
+
+	handler := middleware.Default(router.New(controller, healthController), config.HTTP)
 
-	handler := router.New(controller, healthController)
+	runner.AddHTTPServer("REST-Server", fmt.Sprintf("0.0.0.0:%d", config.Port), handler)
+	runner.AddHTTPServer("Metrics-Server", fmt.Sprintf("0.0.0.0:%d", config.MetricsPort), metrics.Handler())
 
-	log.Printf("REST-Server started on Port: %d\n", config.Port)
-	addr := fmt.Sprintf("0.0.0.0:%d", config.Port)
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		log.Fatalf("error while listen and serve: %s", err.Error())
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatalf("error while running the servers: %s", err.Error())
 	}
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -22,8 +23,7 @@ func NewPsqlRepository(
 	testDataConfig config.Config,
 	hasher crypto.Hasher,
 ) (*PsqlRepository, error) {
-	dsn := config.Dsn()
-	db, err := sql.Open("postgres", dsn)
+	db, err := database.Open("postgres", config)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +31,21 @@ func NewPsqlRepository(
 	return &PsqlRepository{db, testDataConfig, hasher}, nil
 }
 
+// Close closes the connection pool of the repository.
+func (r *PsqlRepository) Close() error {
+	return r.db.Close()
+}
+
+// Ping checks that the database can be reached.
+func (r *PsqlRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool for the metrics.
+func (r *PsqlRepository) Stats() sql.DBStats {
+	return r.db.Stats()
+}
+
 const resetDataBaseQuery = `
 drop table if exists transactions;
 drop table if exists chapters;
@@ -38,7 +53,7 @@ drop table if exists books;
 drop table if exists users;
 `
 
-func (r *PsqlRepository) ResetDatabase() error {
+func (r *PsqlRepository) ResetDatabase(ctx context.Context) error {
 	data, err := r.testDataConfig.GetSqlString()
 	if err != nil {
 		return err
@@ -53,7 +68,7 @@ func (r *PsqlRepository) ResetDatabase() error {
 
 	command = strings.ReplaceAll(command, "$1", fmt.Sprintf("'%s'", hashedPassword))
 
-	_, err = r.db.Exec(command)
+	_, err = r.db.ExecContext(ctx, command)
 	if err != nil {
 		return err
 	}
@@ -55,7 +55,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			assert.Nil(t, user)
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -73,7 +73,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			assert.Equal(t, "newPassword", user.password)
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -36,7 +37,7 @@ func TestPsqlRepository(t *testing.T) {
 				Return("", errors.New("error"))
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.Error(t, err)
@@ -62,7 +63,7 @@ func TestPsqlRepository(t *testing.T) {
 				Return(nil, errors.New("error"))
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.Error(t, err)
@@ -93,7 +94,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("error"))
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.Error(t, err)
@@ -128,7 +129,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 0))
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -164,10 +165,22 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 0))
 
 			// when
-			err := repository.ResetDatabase()
+			err := repository.ResetDatabase(context.Background())
 
 			// then
 			assert.NoError(t, err)
 		})
 	})
+
+	t.Run("Close", func(t *testing.T) {
+		// given
+		dbmock.ExpectClose()
+
+		// when
+		err := repository.Close()
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, dbmock.ExpectationsWereMet())
+	})
 }
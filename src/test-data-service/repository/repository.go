@@ -1,5 +1,7 @@
 package repository
 
+import "context"
+
 type Repository interface {
-	ResetDatabase() error
+	ResetDatabase(ctx context.Context) error
 }
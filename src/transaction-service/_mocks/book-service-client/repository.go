@@ -9,6 +9,7 @@
 package book_service_client_mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
@@ -39,16 +40,16 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // ValidateChapterId mocks base method.
-func (m *MockRepository) ValidateChapterId(userId, chapterId, bookId uint64) (*shared_types.ValidateChapterIdResponse, error) {
+func (m *MockRepository) ValidateChapterId(ctx context.Context, userId, chapterId, bookId uint64) (*shared_types.ValidateChapterIdResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateChapterId", userId, chapterId, bookId)
+	ret := m.ctrl.Call(m, "ValidateChapterId", ctx, userId, chapterId, bookId)
 	ret0, _ := ret[0].(*shared_types.ValidateChapterIdResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ValidateChapterId indicates an expected call of ValidateChapterId.
-func (mr *MockRepositoryMockRecorder) ValidateChapterId(userId, chapterId, bookId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ValidateChapterId(ctx, userId, chapterId, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChapterId", reflect.TypeOf((*MockRepository)(nil).ValidateChapterId), userId, chapterId, bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateChapterId", reflect.TypeOf((*MockRepository)(nil).ValidateChapterId), ctx, userId, chapterId, bookId)
 }
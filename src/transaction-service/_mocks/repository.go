@@ -9,6 +9,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/model"
@@ -39,74 +40,74 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(arg0 []*model.Transaction) error {
+func (m *MockRepository) Create(ctx context.Context, transactions []*model.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", ctx, transactions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, transactions any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, transactions)
 }
 
 // FindAllForReceivingUserId mocks base method.
-func (m *MockRepository) FindAllForReceivingUserId(userId uint64) ([]*model.Transaction, error) {
+func (m *MockRepository) FindAllForReceivingUserId(ctx context.Context, userId uint64) ([]*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllForReceivingUserId", userId)
+	ret := m.ctrl.Call(m, "FindAllForReceivingUserId", ctx, userId)
 	ret0, _ := ret[0].([]*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAllForReceivingUserId indicates an expected call of FindAllForReceivingUserId.
-func (mr *MockRepositoryMockRecorder) FindAllForReceivingUserId(userId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAllForReceivingUserId(ctx, userId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllForReceivingUserId", reflect.TypeOf((*MockRepository)(nil).FindAllForReceivingUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllForReceivingUserId", reflect.TypeOf((*MockRepository)(nil).FindAllForReceivingUserId), ctx, userId)
 }
 
 // FindAllForUserId mocks base method.
-func (m *MockRepository) FindAllForUserId(userId uint64) ([]*model.Transaction, error) {
+func (m *MockRepository) FindAllForUserId(ctx context.Context, userId uint64) ([]*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllForUserId", userId)
+	ret := m.ctrl.Call(m, "FindAllForUserId", ctx, userId)
 	ret0, _ := ret[0].([]*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAllForUserId indicates an expected call of FindAllForUserId.
-func (mr *MockRepositoryMockRecorder) FindAllForUserId(userId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAllForUserId(ctx, userId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllForUserId", reflect.TypeOf((*MockRepository)(nil).FindAllForUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllForUserId", reflect.TypeOf((*MockRepository)(nil).FindAllForUserId), ctx, userId)
 }
 
 // FindForUserIdAndChapterId mocks base method.
-func (m *MockRepository) FindForUserIdAndChapterId(userId, chapterId, bookId uint64) (*model.Transaction, error) {
+func (m *MockRepository) FindForUserIdAndChapterId(ctx context.Context, userId, chapterId, bookId uint64) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindForUserIdAndChapterId", userId, chapterId, bookId)
+	ret := m.ctrl.Call(m, "FindForUserIdAndChapterId", ctx, userId, chapterId, bookId)
 	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindForUserIdAndChapterId indicates an expected call of FindForUserIdAndChapterId.
-func (mr *MockRepositoryMockRecorder) FindForUserIdAndChapterId(userId, chapterId, bookId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindForUserIdAndChapterId(ctx, userId, chapterId, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindForUserIdAndChapterId", reflect.TypeOf((*MockRepository)(nil).FindForUserIdAndChapterId), userId, chapterId, bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindForUserIdAndChapterId", reflect.TypeOf((*MockRepository)(nil).FindForUserIdAndChapterId), ctx, userId, chapterId, bookId)
 }
 
 // Migrate mocks base method.
-func (m *MockRepository) Migrate() error {
+func (m *MockRepository) Migrate(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Migrate")
+	ret := m.ctrl.Call(m, "Migrate", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Migrate indicates an expected call of Migrate.
-func (mr *MockRepositoryMockRecorder) Migrate() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Migrate(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockRepository)(nil).Migrate))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockRepository)(nil).Migrate), ctx)
 }
@@ -9,9 +9,9 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
-	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,17 +39,16 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // CheckChapterBought mocks base method.
-func (m *MockService) CheckChapterBought(userId, chapterId, bookId uint64) (bool, shared_types.Code, error) {
+func (m *MockService) CheckChapterBought(ctx context.Context, userId, chapterId, bookId uint64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckChapterBought", userId, chapterId, bookId)
+	ret := m.ctrl.Call(m, "CheckChapterBought", ctx, userId, chapterId, bookId)
 	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(shared_types.Code)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CheckChapterBought indicates an expected call of CheckChapterBought.
-func (mr *MockServiceMockRecorder) CheckChapterBought(userId, chapterId, bookId any) *gomock.Call {
+func (mr *MockServiceMockRecorder) CheckChapterBought(ctx, userId, chapterId, bookId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckChapterBought", reflect.TypeOf((*MockService)(nil).CheckChapterBought), userId, chapterId, bookId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckChapterBought", reflect.TypeOf((*MockService)(nil).CheckChapterBought), ctx, userId, chapterId, bookId)
 }
@@ -9,6 +9,7 @@
 package user_service_client_mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -38,15 +39,15 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // MoveBalance mocks base method.
-func (m *MockRepository) MoveBalance(userId, receivingUserId uint64, amount int64) error {
+func (m *MockRepository) MoveBalance(ctx context.Context, userId, receivingUserId uint64, amount int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveBalance", userId, receivingUserId, amount)
+	ret := m.ctrl.Call(m, "MoveBalance", ctx, userId, receivingUserId, amount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MoveBalance indicates an expected call of MoveBalance.
-func (mr *MockRepositoryMockRecorder) MoveBalance(userId, receivingUserId, amount any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) MoveBalance(ctx, userId, receivingUserId, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveBalance", reflect.TypeOf((*MockRepository)(nil).MoveBalance), userId, receivingUserId, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveBalance", reflect.TypeOf((*MockRepository)(nil).MoveBalance), ctx, userId, receivingUserId, amount)
 }
@@ -5,8 +5,11 @@ import (
 
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/openapi"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	controller "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/controller"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/model"
 )
 
 type Router struct {
@@ -20,12 +23,24 @@ func New(
 ) *Router {
 	transactionsRouter := router.New()
 
+	transactionsRouter.GET("/openapi.json", openapi.Handler(transactionsRouter, openapi.Info{Title: "Transaction-Service", Version: "1.0.0"}))
 	transactionsRouter.GET("/health", healthController.ProvideHealth)
-	transactionsRouter.POST("/check-chapter-bought", transactionController.CheckChapterBought)
-
-	transactionsRouter.USE("/api/v1/transactions", authController.AuthenticationMiddleware)
-	transactionsRouter.GET("/api/v1/transactions", transactionController.GetYourTransactions)
-	transactionsRouter.POST("/api/v1/transactions", transactionController.CreateTransaction)
+	transactionsRouter.GET("/health/live", healthController.ProvideLiveness)
+	transactionsRouter.GET("/health/ready", healthController.ProvideReadiness)
+	transactionsRouter.POST("/check-chapter-bought", transactionController.CheckChapterBought).
+		Describe("Checks if a user bought a chapter").
+		Request(shared_types.CheckChapterBoughtRequest{}).
+		Response(http.StatusOK, shared_types.CheckChapterBoughtResponse{})
+
+	transactions := transactionsRouter.Group("/api/v1/transactions", authController.AuthenticationMiddleware)
+	transactions.GET("", transactionController.GetYourTransactions).
+		Describe("Get the transactions of the authenticated user, the received ones if receiving is set").
+		Query("receiving", "string").
+		Response(http.StatusOK, []*model.Transaction{})
+	transactions.POST("", transactionController.CreateTransaction).
+		Describe("Buy a chapter").
+		Request(controller.CreateTransactionRequest{}).
+		Response(http.StatusOK, nil)
 
 	return &Router{transactionsRouter}
 }
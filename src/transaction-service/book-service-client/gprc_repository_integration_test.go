@@ -71,7 +71,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			res, err := repository.ValidateChapterId(1, 1000, 1)
+			res, err := repository.ValidateChapterId(context.Background(), 1, 1000, 1)
 
 			// then
 			assert.Error(t, err)
@@ -83,7 +83,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			res, err := repository.ValidateChapterId(1, 1, 1000)
+			res, err := repository.ValidateChapterId(context.Background(), 1, 1, 1000)
 
 			// then
 			assert.Error(t, err)
@@ -95,7 +95,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 
 			// given
 			// when
-			res, err := repository.ValidateChapterId(1, 1, 1)
+			res, err := repository.ValidateChapterId(context.Background(), 1, 1, 1)
 
 			// then
 			assert.Error(t, err)
@@ -110,7 +110,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 			log.Println(shouldBooks)
 
 			// when
-			res, err := repository.ValidateChapterId(2, 1, 1)
+			res, err := repository.ValidateChapterId(context.Background(), 2, 1, 1)
 
 			// then
 			assert.NoError(t, err)
@@ -2,6 +2,7 @@ package book_service_client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -20,7 +21,7 @@ func NewHTTPRepository(bookServiceURL *url.URL, client client.Client) *HTTPRepos
 	return &HTTPRepository{bookServiceURL, client}
 }
 
-func (repo *HTTPRepository) ValidateChapterId(userId uint64, chapterId uint64, bookId uint64) (*shared_types.ValidateChapterIdResponse, error) {
+func (repo *HTTPRepository) ValidateChapterId(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) (*shared_types.ValidateChapterIdResponse, error) {
 	host := repo.bookServiceURL.String()
 
 	body := &shared_types.ValidateChapterIdRequest{UserId: userId, ChapterId: chapterId, BookId: bookId}
@@ -29,7 +30,7 @@ func (repo *HTTPRepository) ValidateChapterId(userId uint64, chapterId uint64, b
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", host, bytes.NewBuffer(reqBody))
+	req, err := client.NewRequest(ctx, "POST", host, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package book_service_client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -40,7 +41,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(nil, errors.New("error with request"))
 
 			// when
-			res, err := repo.ValidateChapterId(userId, chapterId, bookId)
+			res, err := repo.ValidateChapterId(context.Background(), userId, chapterId, bookId)
 
 			// then
 			assert.Error(t, err)
@@ -70,7 +71,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			res, err := repo.ValidateChapterId(userId, chapterId, bookId)
+			res, err := repo.ValidateChapterId(context.Background(), userId, chapterId, bookId)
 
 			// then
 			assert.Error(t, err)
@@ -101,7 +102,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// then
-			res, err := repo.ValidateChapterId(userId, chapterId, bookId)
+			res, err := repo.ValidateChapterId(context.Background(), userId, chapterId, bookId)
 
 			// then
 			assert.Error(t, err)
@@ -133,7 +134,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			res, err := repo.ValidateChapterId(userId, chapterId, bookId)
+			res, err := repo.ValidateChapterId(context.Background(), userId, chapterId, bookId)
 
 			// then
 			assert.Error(t, err)
@@ -165,7 +166,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			res, err := repo.ValidateChapterId(userId, chapterId, bookId)
+			res, err := repo.ValidateChapterId(context.Background(), userId, chapterId, bookId)
 
 			// then
 			assert.NoError(t, err)
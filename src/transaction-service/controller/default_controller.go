@@ -1,12 +1,16 @@
 package controller
 
 import (
+	"database/sql"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/validation"
 	book_service_client "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/book-service-client"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/model"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/repository"
@@ -38,13 +42,13 @@ func (ctrl *DefaultController) GetYourTransactions(w http.ResponseWriter, r *htt
 	var transactions []*model.Transaction
 	var err error
 	if receiving {
-		transactions, err = ctrl.transactionRepository.FindAllForReceivingUserId(userId)
+		transactions, err = ctrl.transactionRepository.FindAllForReceivingUserId(r.Context(), userId)
 	} else {
-		transactions, err = ctrl.transactionRepository.FindAllForUserId(userId)
+		transactions, err = ctrl.transactionRepository.FindAllForUserId(r.Context(), userId)
 	}
 
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -52,80 +56,74 @@ func (ctrl *DefaultController) GetYourTransactions(w http.ResponseWriter, r *htt
 	json.NewEncoder(w).Encode(transactions)
 }
 
-type createTransactionRequest struct {
-	ChapterID uint64 `json:"chapterID"`
-	BookID    uint64 `json:"bookID"`
-}
-
-func (r createTransactionRequest) isValid() bool {
-	return r.ChapterID != 0 && r.BookID != 0
+type CreateTransactionRequest struct {
+	ChapterID uint64 `json:"chapterID" validate:"required"`
+	BookID    uint64 `json:"bookID" validate:"required"`
 }
 
 func (ctrl *DefaultController) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 	userId := r.Context().Value(auth_middleware.AuthenticatedUserId).(uint64)
 
-	var request createTransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("json-decode", err)
-		w.WriteHeader(http.StatusBadRequest)
+	var request CreateTransactionRequest
+	if err := validation.Decode(r, &request); err != nil {
+		slog.InfoContext(r.Context(), "invalid create transaction request", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	if !request.isValid() {
-		log.Println("not valid create transaction request")
-		w.WriteHeader(http.StatusBadRequest)
+	_, err := ctrl.transactionRepository.FindForUserIdAndChapterId(r.Context(), userId, request.ChapterID, request.BookID)
+	if err == nil {
+		problem.Write(w, r, shared_types.NewError(shared_types.AlreadyExists, "CHAPTER_ALREADY_BOUGHT", "you already bought this chapter"))
 		return
 	}
-
-	_, err := ctrl.transactionRepository.FindForUserIdAndChapterId(userId, request.ChapterID, request.BookID)
-	if err == nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if !errors.Is(err, sql.ErrNoRows) {
+		slog.ErrorContext(r.Context(), "could not check if the chapter was bought", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	validatedInfo, err := ctrl.bookClientRepository.ValidateChapterId(userId, request.ChapterID, request.BookID)
+	validatedInfo, err := ctrl.bookClientRepository.ValidateChapterId(r.Context(), userId, request.ChapterID, request.BookID)
 	if err != nil {
-		log.Println("validate chapter error", err)
-		w.WriteHeader(http.StatusBadRequest)
+		slog.WarnContext(r.Context(), "could not validate the chapter", "error", err)
+		var sharedErr *shared_types.Error
+		if !errors.As(err, &sharedErr) {
+			sharedErr = shared_types.WrapError(err, shared_types.FailedPrecondition, "CHAPTER_NOT_BUYABLE", "you cannot buy this chapter")
+		}
+		problem.Write(w, r, sharedErr)
 		return
 	}
 
-	if err := ctrl.transactionRepository.Create([]*model.Transaction{{
+	if err := ctrl.transactionRepository.Create(r.Context(), []*model.Transaction{{
 		ChapterID:       request.ChapterID,
 		PayingUserID:    userId,
 		ReceivingUserID: validatedInfo.ReceivingUserId,
 		BookID:          validatedInfo.BookId,
 		Amount:          validatedInfo.Amount,
 	}}); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
-	if err := ctrl.userClientRepository.MoveBalance(userId, validatedInfo.ReceivingUserId, int64(validatedInfo.Amount)); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.userClientRepository.MoveBalance(r.Context(), userId, validatedInfo.ReceivingUserId, int64(validatedInfo.Amount)); err != nil {
+		slog.ErrorContext(r.Context(), "could not move the balance", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 }
 
 func (ctrl *DefaultController) CheckChapterBought(w http.ResponseWriter, r *http.Request) {
 	var request shared_types.CheckChapterBoughtRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.IsValid() {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	success, statusCode, err := ctrl.service.CheckChapterBought(request.UserID, request.ChapterID, request.BookID)
+	success, err := ctrl.service.CheckChapterBought(r.Context(), request.UserID, request.ChapterID, request.BookID)
 	if err != nil {
-		w.WriteHeader(statusCode.ToHTTPStatusCode())
+		problem.Write(w, r, err)
 		return
 	}
 
-	w.WriteHeader(statusCode.ToHTTPStatusCode())
 	w.Header().Add("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(shared_types.CheckChapterBoughtResponse{Success: success})
 }
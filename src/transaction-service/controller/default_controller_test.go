@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"io"
@@ -39,7 +40,7 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindAllForUserId(uint64(1)).
+				FindAllForUserId(gomock.Any(), uint64(1)).
 				Return(nil, errors.New("query failed")).
 				Times(1)
 
@@ -58,7 +59,7 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindAllForUserId(uint64(1)).
+				FindAllForUserId(gomock.Any(), uint64(1)).
 				Return([]*model.Transaction{{ID: 999}}, nil).
 				Times(1)
 
@@ -85,7 +86,7 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindAllForReceivingUserId(uint64(1)).
+				FindAllForReceivingUserId(gomock.Any(), uint64(1)).
 				Return(nil, errors.New("query failed")).
 				Times(1)
 
@@ -104,7 +105,7 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindAllForReceivingUserId(uint64(1)).
+				FindAllForReceivingUserId(gomock.Any(), uint64(1)).
 				Return([]*model.Transaction{{ID: 999}}, nil).
 				Times(1)
 
@@ -164,7 +165,7 @@ func TestTransactionDefaultController(t *testing.T) {
 			}
 		})
 
-		t.Run("should return 400 BAD REQUEST if transaction already exist", func(t *testing.T) {
+		t.Run("should return 409 CONFLICT if transaction already exist", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("POST", "/api/v1/transactions",
@@ -176,14 +177,36 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindForUserIdAndChapterId(id, chapterId, bookId).
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
 				Return(&model.Transaction{ID: 1}, nil)
 
 			// when
 			controller.CreateTransaction(w, r)
 
 			// then
-			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, http.StatusConflict, w.Code)
+		})
+
+		t.Run("should return 500 INTERNAL SERVER ERROR if the purchase can't be checked", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/transactions",
+				strings.NewReader(`{"chapterID":1, "bookID":1}`))
+			id := uint64(1)
+			chapterId := uint64(1)
+			bookId := uint64(1)
+			r = r.WithContext(context.WithValue(r.Context(), auth_middleware.AuthenticatedUserId, id))
+
+			transactionRepository.
+				EXPECT().
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, errors.New("connection refused"))
+
+			// when
+			controller.CreateTransaction(w, r)
+
+			// then
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
 		})
 
 		t.Run("should return 400 INTERNAL SERVER ERROR if validate ChapterId failed", func(t *testing.T) {
@@ -198,12 +221,12 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindForUserIdAndChapterId(id, chapterId, bookId).
-				Return(nil, errors.New("transaction doesn't exist"))
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, sql.ErrNoRows)
 
 			bookClientRepository.
 				EXPECT().
-				ValidateChapterId(id, chapterId, bookId).
+				ValidateChapterId(gomock.Any(), id, chapterId, bookId).
 				Return(nil, errors.New("client error"))
 
 			// when
@@ -213,6 +236,35 @@ func TestTransactionDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, w.Code)
 		})
 
+		t.Run("should return the problem of the book-service if the chapter can't be bought", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/transactions",
+				strings.NewReader(`{"chapterID":1, "bookID":1}`))
+			id := uint64(1)
+			chapterId := uint64(1)
+			bookId := uint64(1)
+			r = r.WithContext(context.WithValue(r.Context(), auth_middleware.AuthenticatedUserId, id))
+
+			transactionRepository.
+				EXPECT().
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, sql.ErrNoRows)
+
+			bookClientRepository.
+				EXPECT().
+				ValidateChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, shared_types.NewError(shared_types.NotFound, "CHAPTER_NOT_FOUND", "chapter not found"))
+
+			// when
+			controller.CreateTransaction(w, r)
+
+			// then
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), `"reason":"CHAPTER_NOT_FOUND"`)
+		})
+
 		t.Run("should return 500 INTERNAL SERVER ERROR if persisting failed", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
@@ -225,17 +277,17 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindForUserIdAndChapterId(id, chapterId, bookId).
-				Return(nil, errors.New("transaction doesn't exist"))
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, sql.ErrNoRows)
 
 			bookClientRepository.
 				EXPECT().
-				ValidateChapterId(id, chapterId, bookId).
+				ValidateChapterId(gomock.Any(), id, chapterId, bookId).
 				Return(&shared_types.ValidateChapterIdResponse{ChapterId: 1, ReceivingUserId: 2, Amount: 100, BookId: 1}, nil)
 
 			transactionRepository.
 				EXPECT().
-				Create([]*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
+				Create(gomock.Any(), []*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
 				Return(errors.New("database error"))
 
 			// when
@@ -257,22 +309,22 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindForUserIdAndChapterId(id, chapterId, bookId).
-				Return(nil, errors.New("transaction doesn't exist"))
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, sql.ErrNoRows)
 
 			bookClientRepository.
 				EXPECT().
-				ValidateChapterId(id, chapterId, bookId).
+				ValidateChapterId(gomock.Any(), id, chapterId, bookId).
 				Return(&shared_types.ValidateChapterIdResponse{ChapterId: 1, ReceivingUserId: 2, Amount: 100, BookId: 1}, nil)
 
 			transactionRepository.
 				EXPECT().
-				Create([]*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
+				Create(gomock.Any(), []*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
 				Return(nil)
 
 			userClientRepository.
 				EXPECT().
-				MoveBalance(id, uint64(2), int64(100)).
+				MoveBalance(gomock.Any(), id, uint64(2), int64(100)).
 				Return(errors.New("failed"))
 
 			// when
@@ -282,6 +334,43 @@ func TestTransactionDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, w.Code)
 		})
 
+		t.Run("should return the problem of the user-service if moving the balance failed", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/transactions",
+				strings.NewReader(`{"chapterID":1, "bookID":1}`))
+			id := uint64(1)
+			chapterId := uint64(1)
+			bookId := uint64(1)
+			r = r.WithContext(context.WithValue(r.Context(), auth_middleware.AuthenticatedUserId, id))
+
+			transactionRepository.
+				EXPECT().
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, sql.ErrNoRows)
+
+			bookClientRepository.
+				EXPECT().
+				ValidateChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(&shared_types.ValidateChapterIdResponse{ChapterId: 1, ReceivingUserId: 2, Amount: 100, BookId: 1}, nil)
+
+			transactionRepository.
+				EXPECT().
+				Create(gomock.Any(), []*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
+				Return(nil)
+
+			userClientRepository.
+				EXPECT().
+				MoveBalance(gomock.Any(), id, uint64(2), int64(100)).
+				Return(shared_types.NewError(shared_types.Unavailable, "UNKNOWN", "service unavailable"))
+
+			// when
+			controller.CreateTransaction(w, r)
+
+			// then
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		})
+
 		t.Run("should create new transaction", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
@@ -294,22 +383,22 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			transactionRepository.
 				EXPECT().
-				FindForUserIdAndChapterId(id, chapterId, bookId).
-				Return(nil, errors.New("transaction doesn't exist"))
+				FindForUserIdAndChapterId(gomock.Any(), id, chapterId, bookId).
+				Return(nil, sql.ErrNoRows)
 
 			bookClientRepository.
 				EXPECT().
-				ValidateChapterId(id, chapterId, bookId).
+				ValidateChapterId(gomock.Any(), id, chapterId, bookId).
 				Return(&shared_types.ValidateChapterIdResponse{ChapterId: 1, ReceivingUserId: 2, Amount: 100, BookId: 1}, nil)
 
 			transactionRepository.
 				EXPECT().
-				Create([]*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
+				Create(gomock.Any(), []*model.Transaction{{ChapterID: 1, Amount: 100, ReceivingUserID: 2, PayingUserID: 1, BookID: 1}}).
 				Return(nil)
 
 			userClientRepository.
 				EXPECT().
-				MoveBalance(id, uint64(2), int64(100)).
+				MoveBalance(gomock.Any(), id, uint64(2), int64(100)).
 				Return(nil)
 
 			// when
@@ -367,8 +456,8 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				CheckChapterBought(uint64(1), uint64(1), uint64(1)).
-				Return(false, shared_types.NotFound, errors.New("transaction doesn't exist"))
+				CheckChapterBought(gomock.Any(), uint64(1), uint64(1), uint64(1)).
+				Return(false, shared_types.NewError(shared_types.NotFound, "TRANSACTION_NOT_FOUND", "transaction doesn't exist"))
 
 			// when
 			controller.CheckChapterBought(w, r)
@@ -384,8 +473,8 @@ func TestTransactionDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				CheckChapterBought(uint64(1), uint64(1), uint64(1)).
-				Return(true, shared_types.OK, nil)
+				CheckChapterBought(gomock.Any(), uint64(1), uint64(1), uint64(1)).
+				Return(true, nil)
 
 			// when
 			controller.CheckChapterBought(w, r)
@@ -2,11 +2,10 @@ package grpc
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/transaction-service/proto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/service"
-	"google.golang.org/grpc/status"
 )
 
 type server struct {
@@ -21,10 +20,10 @@ func NewServer(
 }
 
 func (s *server) CheckChapterBought(ctx context.Context, req *proto.CheckChapterBoughtRequest) (*proto.CheckChapterBoughtResponse, error) {
-	success, statuCode, err := s.service.CheckChapterBought(req.UserId, req.ChapterId, req.BookId)
+	success, err := s.service.CheckChapterBought(ctx, req.UserId, req.ChapterId, req.BookId)
 	if err != nil {
-		log.Println("ERROR [CheckChapterBought - FindForUserIdAndChapterId]: ", err.Error())
-		return nil, status.Error(statuCode.ToGRPCStatusCode(), err.Error())
+		slog.ErrorContext(ctx, "could not check if the chapter was bought", "error", err)
+		return nil, err
 	}
 
 	response := &proto.CheckChapterBoughtResponse{
@@ -1,18 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net"
 	"net/http"
 	"net/url"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/database"
 	bproto "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/book-service/proto"
 	tproto "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/transaction-service/proto"
 	uproto "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/user-service/proto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/lifecycle"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/tracing"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/api/router"
 	book_service_client "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/book-service-client"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/controller"
@@ -20,7 +26,6 @@ import (
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/repository"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/service"
 	user_service_client "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/user-service-client"
-	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -29,44 +34,67 @@ import (
 
 type ApplicationConfig struct {
 	Database            database.PsqlConfig `envPrefix:"POSTGRES_"`
-	Port                uint16              `env:"PORT" envDefault:"8080"`
-	GrpcPort            uint16              `env:"GRPC_PORT" envDefault:"8081"`
+	Port                uint16              `env:"PORT" envDefault:"8080" validate:"min=1"`
+	MetricsPort         uint16              `env:"METRICS_PORT" envDefault:"9090" validate:"min=1"`
+	GrpcPort            uint16              `env:"GRPC_PORT" envDefault:"8081" validate:"min=1"`
 	GrpcCommunication   bool                `env:"GRPC_COMMUNICATION" envDefault:"true"`
 	AuthIsActive        bool                `env:"AUTH_IS_ACTIVE" envDefault:"false"`
 	AuthServiceEndpoint url.URL             `env:"AUTH_SERVICE_ENDPOINT,notEmpty"`
 	BookServiceEndpoint url.URL             `env:"BOOK_SERVICE_ENDPOINT,notEmpty"`
 	UserServiceEndpoint url.URL             `env:"USER_SERVICE_ENDPOINT,notEmpty"`
+	HTTP                middleware.Config   `envPrefix:"HTTP_"`
+	Health              health.Config       `envPrefix:"HEALTH_"`
+	Tracing             tracing.Config      `envPrefix:"TRACING_"`
+	Log                 logger.Config       `envPrefix:"LOG_"`
+	Lifecycle           lifecycle.Config
 }
 
 func main() {
 	godotenv.Load()
 
 	config := ApplicationConfig{}
-	if err := env.Parse(&config); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	if err := appconfig.Load("transaction-service", &config); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
 
+	logger.SetDefault(config.Log)
+
+	runner := lifecycle.NewRunner(config.Lifecycle)
+
+	tracerProvider, err := tracing.NewProvider(context.Background(), "transaction-service", config.Tracing)
+	if err != nil {
+		log.Fatalf("could not create the tracer provider: %s", err.Error())
+	}
+	runner.AddCloser("tracer provider", tracerProvider)
+
+	checker := health.NewChecker(config.Health)
+	runner.OnShutdown(checker.Shutdown)
+
 	transactionRepository, err := repository.NewPsqlRepository(config.Database)
 	if err != nil {
 		log.Fatalf("could not create user repository: %s", err.Error())
 	}
+	runner.AddCloser("repository", transactionRepository)
+	if err := metrics.RegisterDBStats("transactions", transactionRepository); err != nil {
+		log.Fatalf("could not register the database metrics: %s", err.Error())
+	}
 
 	var authRepository auth_middleware.Repository
 	var bookServiceClientRepository book_service_client.Repository
 	var userServiceClientRepository user_service_client.Repository
 
 	if config.GrpcCommunication {
-		userConn, err := grpc.Dial(config.AuthServiceEndpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		userConn, err := grpc.Dial(config.AuthServiceEndpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()), metrics.DialOption(), tracing.DialOption(), logger.DialOption())
 		if err != nil {
 			log.Fatalf("could not connect: %v", err)
 		}
-		defer userConn.Close()
+		runner.AddCloser("user-service connection", userConn)
 
-		bookConn, err := grpc.Dial(config.BookServiceEndpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		bookConn, err := grpc.Dial(config.BookServiceEndpoint.Host, grpc.WithTransportCredentials(insecure.NewCredentials()), metrics.DialOption(), tracing.DialOption(), logger.DialOption())
 		if err != nil {
 			log.Fatalf("could not connect: %v", err)
 		}
-		defer bookConn.Close()
+		runner.AddCloser("book-service connection", bookConn)
 
 		userGrpcClient := uproto.NewUserServiceClient(userConn)
 		bookGrpcClient := bproto.NewBookServiceClient(bookConn)
@@ -74,48 +102,49 @@ func main() {
 		authRepository = auth_middleware.NewGRPCRepository(userGrpcClient)
 		bookServiceClientRepository = book_service_client.NewGRPCRepository(bookGrpcClient)
 		userServiceClientRepository = user_service_client.NewGRPCRepository(userGrpcClient)
+
+		checker.Register("user-service", health.GRPCConn(userConn))
+		checker.RegisterOptional("book-service", health.GRPCConn(bookConn))
 	} else {
-		authRepository = auth_middleware.NewHTTPRepository(&config.AuthServiceEndpoint, http.DefaultClient)
-		bookServiceClientRepository = book_service_client.NewHTTPRepository(&config.BookServiceEndpoint, http.DefaultClient)
-		userServiceClientRepository = user_service_client.NewHTTPRepository(&config.UserServiceEndpoint, http.DefaultClient)
+		httpClient := &http.Client{Transport: tracing.Transport(http.DefaultTransport)}
+		authRepository = auth_middleware.NewHTTPRepository(&config.AuthServiceEndpoint, httpClient)
+		bookServiceClientRepository = book_service_client.NewHTTPRepository(&config.BookServiceEndpoint, httpClient)
+		userServiceClientRepository = user_service_client.NewHTTPRepository(&config.UserServiceEndpoint, httpClient)
+
+		checker.Register("user-service", health.HTTP(http.DefaultClient, fmt.Sprintf("%s://%s/health/live", config.UserServiceEndpoint.Scheme, config.UserServiceEndpoint.Host)))
+		checker.RegisterOptional("book-service", health.HTTP(http.DefaultClient, fmt.Sprintf("%s://%s/health/live", config.BookServiceEndpoint.Scheme, config.BookServiceEndpoint.Host)))
 	}
 
 	service := service.NewDefaultService(transactionRepository)
 
 	authController := auth_middleware.NewDefaultController(authRepository, config.AuthIsActive)
-	healthController := health.NewDefaultController()
 
 	controller := controller.NewDefaultController(transactionRepository, bookServiceClientRepository, userServiceClientRepository, service)
 
-	handler := router.New(controller, authController, healthController)
+	checker.Register("database", transactionRepository.Ping)
+	healthController := health.NewDefaultController(checker)
+
+	handler := middleware.Default(router.New(controller, authController, healthController), config.HTTP)
 
-	if err := transactionRepository.Migrate(); err != nil {
+	if err := transactionRepository.Migrate(context.Background()); err != nil {
 		log.Fatalf("could not migrate: %s", err.Error())
 	}
 
 	if config.GrpcCommunication {
-		grpcAddr := fmt.Sprintf("0.0.0.0:%d", config.GrpcPort)
-		listener, err := net.Listen("tcp", grpcAddr)
-		if err != nil {
-			log.Fatalf("could not listen: %v", err)
-		}
-
-		srv := grpc.NewServer()
+		srv := grpc.NewServer(append(metrics.ServerOptions(), tracing.ServerOption(), logger.ServerOption())...)
 		reflection.Register(srv)
 		grpcServer := grpc_server.NewServer(service)
 		tproto.RegisterTransactionServiceServer(srv, grpcServer)
 
-		go func() {
-			log.Printf("GRPC-Server started on Port: %d\n", config.GrpcPort)
-			if err := srv.Serve(listener); err != nil {
-				log.Fatalf("could not serve: %v", err)
-			}
-		}()
+		health.RegisterGRPC(srv, checker)
+
+		runner.AddGRPCServer("GRPC-Server", fmt.Sprintf("0.0.0.0:%d", config.GrpcPort), srv)
 	}
 
-	log.Printf("REST-Server started on Port: %d\n", config.Port)
-	addr := fmt.Sprintf("0.0.0.0:%d", config.Port)
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		log.Fatalf("error while listen and serve: %s", err.Error())
+	runner.AddHTTPServer("REST-Server", fmt.Sprintf("0.0.0.0:%d", config.Port), handler)
+	runner.AddHTTPServer("Metrics-Server", fmt.Sprintf("0.0.0.0:%d", config.MetricsPort), metrics.Handler())
+
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatalf("error while running the servers: %s", err.Error())
 	}
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -15,8 +16,7 @@ type PsqlRepository struct {
 }
 
 func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
-	dsn := config.Dsn()
-	db, err := sql.Open("postgres", dsn)
+	db, err := database.Open("postgres", config)
 	db.SetMaxOpenConns(10)
 	if err != nil {
 		return nil, err
@@ -25,6 +25,21 @@ func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
 	return &PsqlRepository{db}, nil
 }
 
+// Close closes the connection pool of the repository.
+func (repo *PsqlRepository) Close() error {
+	return repo.db.Close()
+}
+
+// Ping checks that the database can be reached.
+func (repo *PsqlRepository) Ping(ctx context.Context) error {
+	return repo.db.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool for the metrics.
+func (repo *PsqlRepository) Stats() sql.DBStats {
+	return repo.db.Stats()
+}
+
 const createTransactionsTable = `
 create table if not exists transactions (
 	id					serial primary key,
@@ -39,8 +54,8 @@ create table if not exists transactions (
 )
 `
 
-func (repo *PsqlRepository) Migrate() error {
-	_, err := repo.db.Exec(createTransactionsTable)
+func (repo *PsqlRepository) Migrate(ctx context.Context) error {
+	_, err := repo.db.ExecContext(ctx, createTransactionsTable)
 	return err
 }
 
@@ -48,7 +63,7 @@ const createTransactionsBatchQuery = `
 insert into transactions (bookid, chapterid, receivinguserid, payinguserid, amount) values %s
 `
 
-func (repo *PsqlRepository) Create(transactions []*model.Transaction) error {
+func (repo *PsqlRepository) Create(ctx context.Context, transactions []*model.Transaction) error {
 	placeholders := make([]string, len(transactions))
 	values := make([]interface{}, len(transactions)*5)
 
@@ -62,7 +77,7 @@ func (repo *PsqlRepository) Create(transactions []*model.Transaction) error {
 	}
 
 	query := fmt.Sprintf(createTransactionsBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, values...)
+	_, err := repo.db.ExecContext(ctx, query, values...)
 	return err
 }
 
@@ -70,8 +85,8 @@ const findAllTransactionsQuery = `
 select id, bookid, chapterid, receivinguserid, payinguserid, amount from transactions
 `
 
-func (repo *PsqlRepository) FindAll() ([]*model.Transaction, error) {
-	rows, err := repo.db.Query(findAllTransactionsQuery)
+func (repo *PsqlRepository) FindAll(ctx context.Context) ([]*model.Transaction, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllTransactionsQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -93,8 +108,8 @@ const findAllTransactionsForUserQuery = `
 select id, bookid, chapterid, receivinguserid, payinguserid, amount from transactions where payinguserid = $1
 `
 
-func (repo *PsqlRepository) FindAllForUserId(userId uint64) ([]*model.Transaction, error) {
-	rows, err := repo.db.Query(findAllTransactionsForUserQuery, userId)
+func (repo *PsqlRepository) FindAllForUserId(ctx context.Context, userId uint64) ([]*model.Transaction, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllTransactionsForUserQuery, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -116,8 +131,8 @@ const findAllTransactionsForReceivingUserQuery = `
 select id, bookid, chapterid, receivinguserid, payinguserid, amount from transactions where receivinguserid = $1
 `
 
-func (repo *PsqlRepository) FindAllForReceivingUserId(userId uint64) ([]*model.Transaction, error) {
-	rows, err := repo.db.Query(findAllTransactionsForReceivingUserQuery, userId)
+func (repo *PsqlRepository) FindAllForReceivingUserId(ctx context.Context, userId uint64) ([]*model.Transaction, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllTransactionsForReceivingUserQuery, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +154,8 @@ const findTransactionbyIDQuery = `
 select id, bookid, chapterid, receivinguserid, payinguserid, amount from transactions where id = $1
 `
 
-func (repo *PsqlRepository) FindById(id uint64) (*model.Transaction, error) {
-	row := repo.db.QueryRow(findTransactionbyIDQuery, id)
+func (repo *PsqlRepository) FindById(ctx context.Context, id uint64) (*model.Transaction, error) {
+	row := repo.db.QueryRowContext(ctx, findTransactionbyIDQuery, id)
 	transaction := &model.Transaction{}
 	if err := row.Scan(&transaction.ID, &transaction.BookID, &transaction.ChapterID, &transaction.ReceivingUserID, &transaction.PayingUserID, &transaction.Amount); err != nil {
 		return nil, err
@@ -153,8 +168,8 @@ const findTransactionByUserIdAndChapterIdQuery = `
 select id, bookid, chapterid, receivinguserid, payinguserid, amount from transactions where chapterid = $1 and bookid = $2 and payinguserid = $3
 `
 
-func (repo *PsqlRepository) FindForUserIdAndChapterId(userId uint64, chapterId uint64, bookId uint64) (*model.Transaction, error) {
-	row := repo.db.QueryRow(findTransactionByUserIdAndChapterIdQuery, chapterId, bookId, userId)
+func (repo *PsqlRepository) FindForUserIdAndChapterId(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) (*model.Transaction, error) {
+	row := repo.db.QueryRowContext(ctx, findTransactionByUserIdAndChapterIdQuery, chapterId, bookId, userId)
 	transaction := &model.Transaction{}
 	if err := row.Scan(&transaction.ID, &transaction.BookID, &transaction.ChapterID, &transaction.ReceivingUserID, &transaction.PayingUserID, &transaction.Amount); err != nil {
 		return nil, err
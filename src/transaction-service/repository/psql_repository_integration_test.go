@@ -45,7 +45,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			// given
 			createUserBookAndChapterTable(t, repository.db)
 			// when
-			err := repository.Migrate()
+			err := repository.Migrate(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -77,7 +77,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 				},
 			}
 			// when
-			err := repository.Create(transactions)
+			err := repository.Create(context.Background(), transactions)
 
 			// then
 			assert.NoError(t, err)
@@ -115,7 +115,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			transactions, err := repository.FindAll()
+			transactions, err := repository.FindAll(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -152,7 +152,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			transactions, err := repository.FindAllForUserId(1)
+			transactions, err := repository.FindAllForUserId(context.Background(), 1)
 
 			// then
 			assert.NoError(t, err)
@@ -189,7 +189,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			transactions, err := repository.FindAllForReceivingUserId(1)
+			transactions, err := repository.FindAllForReceivingUserId(context.Background(), 1)
 
 			// then
 			assert.NoError(t, err)
@@ -212,7 +212,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			})
 
 			// when
-			transaction, err := repository.FindById(9)
+			transaction, err := repository.FindById(context.Background(), 9)
 
 			// then
 			assert.NoError(t, err)
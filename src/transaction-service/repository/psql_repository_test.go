@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Create(transactions)
+			err := repository.Create(context.Background(), transactions)
 
 			// then
 			assert.Error(t, err)
@@ -66,7 +67,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Create(transactions)
+			err := repository.Create(context.Background(), transactions)
 
 			// then
 			assert.NoError(t, err)
@@ -81,7 +82,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			transactions, err := repository.FindAllForUserId(uint64(2))
+			transactions, err := repository.FindAllForUserId(context.Background(), uint64(2))
 
 			// then
 			assert.Error(t, err)
@@ -98,7 +99,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(2, 1, 2, 1, 2, 100))
 
 			// when
-			transactions, err := repository.FindAllForUserId(uint64(2))
+			transactions, err := repository.FindAllForUserId(context.Background(), uint64(2))
 
 			// then
 			assert.NoError(t, err)
@@ -117,7 +118,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			transactions, err := repository.FindAllForReceivingUserId(uint64(1))
+			transactions, err := repository.FindAllForReceivingUserId(context.Background(), uint64(1))
 
 			// then
 			assert.Error(t, err)
@@ -134,7 +135,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(2, 1, 2, 1, 2, 100))
 
 			// when
-			transactions, err := repository.FindAllForReceivingUserId(uint64(1))
+			transactions, err := repository.FindAllForReceivingUserId(context.Background(), uint64(1))
 
 			// then
 			assert.NoError(t, err)
@@ -153,7 +154,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			transaction, err := repository.FindForUserIdAndChapterId(uint64(1), uint64(1), uint64(1))
+			transaction, err := repository.FindForUserIdAndChapterId(context.Background(), uint64(1), uint64(1), uint64(1))
 
 			// then
 			assert.Error(t, err)
@@ -178,7 +179,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(1, 1, 1, 1, 2, 100))
 
 			// when
-			transaction, err := repository.FindForUserIdAndChapterId(uint64(1), uint64(1), uint64(1))
+			transaction, err := repository.FindForUserIdAndChapterId(context.Background(), uint64(1), uint64(1), uint64(1))
 
 			// then
 			assert.NoError(t, err)
@@ -186,4 +187,16 @@ func TestPsqlRepository(t *testing.T) {
 			assert.NoError(t, dbmock.ExpectationsWereMet())
 		})
 	})
+
+	t.Run("Close", func(t *testing.T) {
+		// given
+		dbmock.ExpectClose()
+
+		// when
+		err := repository.Close()
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, dbmock.ExpectationsWereMet())
+	})
 }
@@ -1,11 +1,15 @@
 package repository
 
-import "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/model"
+import (
+	"context"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/model"
+)
 
 type Repository interface {
-	Migrate() error
-	Create([]*model.Transaction) error
-	FindForUserIdAndChapterId(userId uint64, chapterId uint64, bookId uint64) (*model.Transaction, error)
-	FindAllForUserId(userId uint64) ([]*model.Transaction, error)
-	FindAllForReceivingUserId(userId uint64) ([]*model.Transaction, error)
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, transactions []*model.Transaction) error
+	FindForUserIdAndChapterId(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) (*model.Transaction, error)
+	FindAllForUserId(ctx context.Context, userId uint64) ([]*model.Transaction, error)
+	FindAllForReceivingUserId(ctx context.Context, userId uint64) ([]*model.Transaction, error)
 }
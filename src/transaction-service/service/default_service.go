@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	repository "github.com/akatranlp/hsfl-master-ai-cloud-engineering/transaction-service/repository"
 )
@@ -15,11 +17,11 @@ func NewDefaultService(transactionRepository repository.Repository) *DefaultServ
 	}
 }
 
-func (s *DefaultService) CheckChapterBought(userId uint64, chapterId uint64, bookId uint64) (bool, shared_types.Code, error) {
-	transaction, err := s.transactionRepository.FindForUserIdAndChapterId(userId, chapterId, bookId)
+func (s *DefaultService) CheckChapterBought(ctx context.Context, userId uint64, chapterId uint64, bookId uint64) (bool, error) {
+	transaction, err := s.transactionRepository.FindForUserIdAndChapterId(ctx, userId, chapterId, bookId)
 	if err != nil {
-		return false, shared_types.NotFound, err
+		return false, shared_types.WrapNotFound(err, "TRANSACTION_NOT_FOUND", "transaction not found")
 	}
 
-	return transaction != nil, shared_types.OK, nil
+	return transaction != nil, nil
 }
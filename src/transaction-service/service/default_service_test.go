@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
@@ -23,15 +25,31 @@ func TestDefaultService(t *testing.T) {
 			// given
 			repository.
 				EXPECT().
-				FindForUserIdAndChapterId(uint64(1), uint64(1), uint64(1)).
-				Return(nil, errors.New("not found"))
+				FindForUserIdAndChapterId(gomock.Any(), uint64(1), uint64(1), uint64(1)).
+				Return(nil, sql.ErrNoRows)
 
 			// when
-			success, statusCode, err := service.CheckChapterBought(1, 1, 1)
+			success, err := service.CheckChapterBought(context.Background(), 1, 1, 1)
 
 			// given
 			assert.Error(t, err)
-			assert.Equal(t, shared_types.NotFound, statusCode)
+			assert.Equal(t, shared_types.NotFound, shared_types.CodeOf(err))
+			assert.False(t, success)
+		})
+
+		t.Run("should return Internal if the repository fails", func(t *testing.T) {
+			// given
+			repository.
+				EXPECT().
+				FindForUserIdAndChapterId(gomock.Any(), uint64(1), uint64(1), uint64(1)).
+				Return(nil, errors.New("connection refused"))
+
+			// when
+			success, err := service.CheckChapterBought(context.Background(), 1, 1, 1)
+
+			// given
+			assert.Error(t, err)
+			assert.Equal(t, shared_types.Internal, shared_types.CodeOf(err))
 			assert.False(t, success)
 		})
 
@@ -39,15 +57,15 @@ func TestDefaultService(t *testing.T) {
 			// given
 			repository.
 				EXPECT().
-				FindForUserIdAndChapterId(uint64(1), uint64(1), uint64(1)).
+				FindForUserIdAndChapterId(gomock.Any(), uint64(1), uint64(1), uint64(1)).
 				Return(nil, nil)
 
 			// when
-			success, statusCode, err := service.CheckChapterBought(1, 1, 1)
+			success, err := service.CheckChapterBought(context.Background(), 1, 1, 1)
 
 			// given
 			assert.NoError(t, err)
-			assert.Equal(t, shared_types.OK, statusCode)
+			assert.Equal(t, shared_types.OK, shared_types.CodeOf(err))
 			assert.False(t, success)
 		})
 
@@ -64,15 +82,15 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				FindForUserIdAndChapterId(uint64(1), uint64(1), uint64(1)).
+				FindForUserIdAndChapterId(gomock.Any(), uint64(1), uint64(1), uint64(1)).
 				Return(transaction, nil)
 
 			// when
-			success, statusCode, err := service.CheckChapterBought(1, 1, 1)
+			success, err := service.CheckChapterBought(context.Background(), 1, 1, 1)
 
 			// given
 			assert.NoError(t, err)
-			assert.Equal(t, shared_types.OK, statusCode)
+			assert.Equal(t, shared_types.OK, shared_types.CodeOf(err))
 			assert.True(t, success)
 		})
 	})
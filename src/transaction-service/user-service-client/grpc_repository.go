@@ -5,8 +5,7 @@ import (
 	"errors"
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/user-service/proto"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 )
 
 type GRPCRepository struct {
@@ -19,20 +18,16 @@ func NewGRPCRepository(client proto.UserServiceClient) *GRPCRepository {
 	}
 }
 
-func (repo *GRPCRepository) MoveBalance(userId uint64, receivingUserId uint64, amount int64) error {
+func (repo *GRPCRepository) MoveBalance(ctx context.Context, userId uint64, receivingUserId uint64, amount int64) error {
 	req := &proto.MoveUserAmountRequest{
 		UserId:          userId,
 		ReceivingUserId: receivingUserId,
 		Amount:          amount,
 	}
 
-	res, err := repo.client.MoveUserAmount(context.Background(), req)
+	res, err := repo.client.MoveUserAmount(ctx, req)
 	if err != nil {
-		st, ok := status.FromError(err)
-		if ok && st.Code() == codes.InvalidArgument {
-			return errors.New("you cannot buy this book")
-		}
-		return err
+		return shared_types.FromGRPCError(err)
 	}
 
 	if !res.Success {
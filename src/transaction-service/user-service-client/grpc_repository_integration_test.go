@@ -74,7 +74,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 			shouldUsers := getAllUsers(t, userServiceRESTPort)
 
 			// when
-			err := repository.MoveBalance(1000, 2, 100)
+			err := repository.MoveBalance(context.Background(), 1000, 2, 100)
 
 			// then
 			assert.Error(t, err)
@@ -89,7 +89,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 			shouldUsers := getAllUsers(t, userServiceRESTPort)
 
 			// when
-			err := repository.MoveBalance(1, 1000, 100)
+			err := repository.MoveBalance(context.Background(), 1, 1000, 100)
 
 			// then
 			assert.Error(t, err)
@@ -105,7 +105,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 			shouldUsers := getAllUsers(t, userServiceRESTPort)
 
 			// when
-			err := repository.MoveBalance(1, 2, 100000)
+			err := repository.MoveBalance(context.Background(), 1, 2, 100000)
 
 			// then
 			assert.Error(t, err)
@@ -120,7 +120,7 @@ func TestIntegrationGRPCRepository(t *testing.T) {
 			shouldUsers := getAllUsers(t, userServiceRESTPort)
 
 			// when
-			err := repository.MoveBalance(1, 2, 1000)
+			err := repository.MoveBalance(context.Background(), 1, 2, 1000)
 
 			// then
 			assert.NoError(t, err)
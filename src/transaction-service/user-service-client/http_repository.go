@@ -2,6 +2,7 @@ package user_service_client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client"
@@ -19,7 +20,7 @@ func NewHTTPRepository(userServiceURL *url.URL, client client.Client) *HTTPRepos
 	return &HTTPRepository{userServiceURL, client}
 }
 
-func (repo *HTTPRepository) MoveBalance(userId uint64, receivingUserId uint64, amount int64) error {
+func (repo *HTTPRepository) MoveBalance(ctx context.Context, userId uint64, receivingUserId uint64, amount int64) error {
 	host := repo.userServiceURL.String()
 
 	body := &shared_types.MoveBalanceRequest{UserId: userId, ReceivingUserId: receivingUserId, Amount: amount}
@@ -28,7 +29,7 @@ func (repo *HTTPRepository) MoveBalance(userId uint64, receivingUserId uint64, a
 		return err
 	}
 
-	req, err := http.NewRequest("POST", host, bytes.NewBuffer(reqBody))
+	req, err := client.NewRequest(ctx, "POST", host, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return err
 	}
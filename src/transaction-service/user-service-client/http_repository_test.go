@@ -2,6 +2,7 @@ package user_service_client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/client/_mocks"
 	"github.com/stretchr/testify/assert"
@@ -39,7 +40,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(nil, errors.New("error with request"))
 
 			// when
-			err := repo.MoveBalance(userId, receivingUserId, amount)
+			err := repo.MoveBalance(context.Background(), userId, receivingUserId, amount)
 
 			// then
 			assert.Error(t, err)
@@ -68,7 +69,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			err := repo.MoveBalance(userId, receivingUserId, amount)
+			err := repo.MoveBalance(context.Background(), userId, receivingUserId, amount)
 
 			// then
 			assert.Error(t, err)
@@ -97,7 +98,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			err := repo.MoveBalance(userId, receivingUserId, amount)
+			err := repo.MoveBalance(context.Background(), userId, receivingUserId, amount)
 
 			// then
 			assert.Error(t, err)
@@ -128,7 +129,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			err := repo.MoveBalance(userId, receivingUserId, amount)
+			err := repo.MoveBalance(context.Background(), userId, receivingUserId, amount)
 
 			// then
 			assert.Error(t, err)
@@ -159,7 +160,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			err := repo.MoveBalance(userId, receivingUserId, amount)
+			err := repo.MoveBalance(context.Background(), userId, receivingUserId, amount)
 
 			// then
 			assert.Error(t, err)
@@ -190,7 +191,7 @@ func TestHTTPRepository(t *testing.T) {
 				Return(response, nil)
 
 			// when
-			err := repo.MoveBalance(userId, receivingUserId, amount)
+			err := repo.MoveBalance(context.Background(), userId, receivingUserId, amount)
 
 			// then
 			assert.NoError(t, err)
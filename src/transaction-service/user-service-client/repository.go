@@ -1,5 +1,7 @@
 package user_service_client
 
+import "context"
+
 type Repository interface {
-	MoveBalance(userId uint64, receivingUserId uint64, amount int64) error
+	MoveBalance(ctx context.Context, userId uint64, receivingUserId uint64, amount int64) error
 }
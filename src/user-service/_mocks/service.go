@@ -9,9 +9,9 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
-	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	model "github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/model"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,48 +40,45 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // MoveUserAmount mocks base method.
-func (m *MockService) MoveUserAmount(payingUserId, receivingUserId uint64, amount int64) (shared_types.Code, error) {
+func (m *MockService) MoveUserAmount(ctx context.Context, payingUserId, receivingUserId uint64, amount int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveUserAmount", payingUserId, receivingUserId, amount)
-	ret0, _ := ret[0].(shared_types.Code)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MoveUserAmount", ctx, payingUserId, receivingUserId, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // MoveUserAmount indicates an expected call of MoveUserAmount.
-func (mr *MockServiceMockRecorder) MoveUserAmount(payingUserId, receivingUserId, amount any) *gomock.Call {
+func (mr *MockServiceMockRecorder) MoveUserAmount(ctx, payingUserId, receivingUserId, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUserAmount", reflect.TypeOf((*MockService)(nil).MoveUserAmount), payingUserId, receivingUserId, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUserAmount", reflect.TypeOf((*MockService)(nil).MoveUserAmount), ctx, payingUserId, receivingUserId, amount)
 }
 
 // ValidateAccessToken mocks base method.
-func (m *MockService) ValidateAccessToken(token string) (*model.DbUser, shared_types.Code, error) {
+func (m *MockService) ValidateAccessToken(ctx context.Context, token string) (*model.DbUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateAccessToken", token)
+	ret := m.ctrl.Call(m, "ValidateAccessToken", ctx, token)
 	ret0, _ := ret[0].(*model.DbUser)
-	ret1, _ := ret[1].(shared_types.Code)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ValidateAccessToken indicates an expected call of ValidateAccessToken.
-func (mr *MockServiceMockRecorder) ValidateAccessToken(token any) *gomock.Call {
+func (mr *MockServiceMockRecorder) ValidateAccessToken(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAccessToken", reflect.TypeOf((*MockService)(nil).ValidateAccessToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAccessToken", reflect.TypeOf((*MockService)(nil).ValidateAccessToken), ctx, token)
 }
 
 // ValidateRefreshToken mocks base method.
-func (m *MockService) ValidateRefreshToken(token string) (*model.DbUser, shared_types.Code, error) {
+func (m *MockService) ValidateRefreshToken(ctx context.Context, token string) (*model.DbUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateRefreshToken", token)
+	ret := m.ctrl.Call(m, "ValidateRefreshToken", ctx, token)
 	ret0, _ := ret[0].(*model.DbUser)
-	ret1, _ := ret[1].(shared_types.Code)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ValidateRefreshToken indicates an expected call of ValidateRefreshToken.
-func (mr *MockServiceMockRecorder) ValidateRefreshToken(token any) *gomock.Call {
+func (mr *MockServiceMockRecorder) ValidateRefreshToken(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRefreshToken", reflect.TypeOf((*MockService)(nil).ValidateRefreshToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRefreshToken", reflect.TypeOf((*MockService)(nil).ValidateRefreshToken), ctx, token)
 }
@@ -3,9 +3,13 @@ package router
 import (
 	"net/http"
 
+	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/openapi"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
+	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/controller"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/model"
 )
 
 type Router struct {
@@ -17,24 +21,58 @@ func New(
 	healthController health.Controller,
 ) *Router {
 	r := router.New()
+	r.GET("/openapi.json", openapi.Handler(r, openapi.Info{Title: "User-Service", Version: "1.0.0"}))
 	r.GET("/health", healthController.ProvideHealth)
-	r.POST("/validate-token", userController.ValidateToken)
-	r.POST("/move-user-amount", userController.MoveUserAmount)
+	r.GET("/health/live", healthController.ProvideLiveness)
+	r.GET("/health/ready", healthController.ProvideReadiness)
+	r.POST("/validate-token", userController.ValidateToken).
+		Describe("Validates an access token").
+		Request(controller.ValidateTokenRequest{}).
+		Response(http.StatusOK, auth_middleware.VerifyTokenResponse{}).
+		Response(http.StatusUnauthorized, nil)
+	r.POST("/move-user-amount", userController.MoveUserAmount).
+		Describe("Moves an amount from one user to another").
+		Request(shared_types.MoveBalanceRequest{}).
+		Response(http.StatusOK, shared_types.MoveBalanceResponse{})
 
-	r.POST("/api/v1/login", userController.Login)
-	r.POST("/api/v1/register", userController.Register)
-	r.POST("/api/v1/refresh-token", userController.RefreshToken)
+	r.POST("/api/v1/login", userController.Login).
+		Describe("Login with email and password").
+		Request(controller.LoginRequest{}).
+		Response(http.StatusOK, controller.LoginResponse{}).
+		Response(http.StatusUnauthorized, nil)
+	r.POST("/api/v1/register", userController.Register).
+		Describe("Register a new user").
+		Request(controller.RegisterRequest{}).
+		Response(http.StatusCreated, nil).
+		Response(http.StatusConflict, nil)
+	r.POST("/api/v1/refresh-token", userController.RefreshToken).
+		Describe("Get a new access token with the refresh token cookie").
+		Response(http.StatusOK, controller.LoginResponse{}).
+		Response(http.StatusUnauthorized, nil)
 
-	r.USE("/api/v1/logout", userController.AuthenticationMiddleWare)
-	r.POST("/api/v1/logout", userController.Logout)
+	r.POST("/api/v1/logout", userController.Logout, userController.AuthenticationMiddleWare).
+		Describe("Logout and invalidate all tokens, if all is set").
+		Query("all", "string").
+		Response(http.StatusOK, nil)
 
-	r.USE("/api/v1/users", userController.AuthenticationMiddleWare)
-
-	r.GET("/api/v1/users", userController.GetUsers)
-	r.GET("/api/v1/users/me", userController.GetMe)
-	r.PATCH("/api/v1/users/me", userController.PatchMe)
-	r.DELETE("/api/v1/users/me", userController.DeleteMe)
-	r.GET("/api/v1/users/:userid", userController.GetUser)
+	users := r.Group("/api/v1/users", userController.AuthenticationMiddleWare)
+	users.GET("", userController.GetUsers).
+		Describe("Get all users").
+		Response(http.StatusOK, []model.UserDTO{})
+	users.GET("/me", userController.GetMe).
+		Describe("Get the authenticated user").
+		Response(http.StatusOK, model.UserDTO{})
+	users.PATCH("/me", userController.PatchMe).
+		Describe("Update the authenticated user").
+		Request(controller.PatchMeRequest{}).
+		Response(http.StatusOK, nil)
+	users.DELETE("/me", userController.DeleteMe).
+		Describe("Delete the authenticated user").
+		Response(http.StatusOK, nil)
+	users.GET("/:userid<uint>", userController.GetUser).
+		Describe("Get a user").
+		Response(http.StatusOK, model.UserDTO{}).
+		Response(http.StatusNotFound, nil)
 
 	return &Router{r}
 }
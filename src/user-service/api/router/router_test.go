@@ -69,26 +69,18 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/users", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not GET", func(t *testing.T) {
-			tests := []string{"HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not GET", func(t *testing.T) {
+			tests := []string{"POST", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 			for _, test := range tests {
 				// given
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(test, "/api/v1/users", nil)
 
-				userController.
-					EXPECT().
-					AuthenticationMiddleWare(w, r, gomock.Any()).
-					Do(func(w http.ResponseWriter, r *http.Request, next lib_router.Next) {
-						next(r)
-					}).
-					Times(1)
-
 				// when
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -119,9 +111,9 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/login", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not POST", func(t *testing.T) {
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not POST", func(t *testing.T) {
 
-			tests := []string{"GET", "HEAD", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+			tests := []string{"GET", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 
 			for _, test := range tests {
 				// given
@@ -132,7 +124,7 @@ func TestRouter(t *testing.T) {
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -155,8 +147,8 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/register", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not POST", func(t *testing.T) {
-			tests := []string{"GET", "HEAD", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not POST", func(t *testing.T) {
+			tests := []string{"GET", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 
 			for _, test := range tests {
 				// given
@@ -167,7 +159,7 @@ func TestRouter(t *testing.T) {
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -190,8 +182,8 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/refresh-token", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not POST", func(t *testing.T) {
-			tests := []string{"GET", "HEAD", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not POST", func(t *testing.T) {
+			tests := []string{"GET", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 
 			for _, test := range tests {
 				// given
@@ -202,7 +194,7 @@ func TestRouter(t *testing.T) {
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -225,27 +217,19 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/logout", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not POST", func(t *testing.T) {
-			tests := []string{"GET", "HEAD", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not POST", func(t *testing.T) {
+			tests := []string{"GET", "PUT", "DELETE", "CONNECT", "TRACE", "PATCH"}
 
 			for _, test := range tests {
 				// given
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(test, "/api/v1/logout", nil)
 
-				userController.
-					EXPECT().
-					AuthenticationMiddleWare(w, r, gomock.Any()).
-					Do(func(w http.ResponseWriter, r *http.Request, next lib_router.Next) {
-						next(r)
-					}).
-					Times(1)
-
 				// when
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -276,27 +260,19 @@ func TestRouter(t *testing.T) {
 	})
 
 	t.Run("/api/v1/users/me", func(t *testing.T) {
-		t.Run("should return 404 NOT FOUND if method is not GET, DELETE or PATCH", func(t *testing.T) {
-			tests := []string{"HEAD", "POST", "CONNECT", "OPTIONS", "TRACE", "PUT"}
+		t.Run("should return 405 METHOD NOT ALLOWED if method is not GET, DELETE or PATCH", func(t *testing.T) {
+			tests := []string{"POST", "CONNECT", "TRACE", "PUT"}
 
 			for _, test := range tests {
 				// given
 				w := httptest.NewRecorder()
 				r := httptest.NewRequest(test, "/api/v1/users/me", nil)
 
-				userController.
-					EXPECT().
-					AuthenticationMiddleWare(w, r, gomock.Any()).
-					Do(func(w http.ResponseWriter, r *http.Request, next lib_router.Next) {
-						next(r)
-					}).
-					Times(1)
-
 				// when
 				router.ServeHTTP(w, r)
 
 				// then
-				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 			}
 		})
 
@@ -452,4 +428,50 @@ func TestRouter(t *testing.T) {
 		})
 
 	*/
+
+	t.Run("/health", func(t *testing.T) {
+		tests := []struct {
+			path   string
+			expect func(w http.ResponseWriter, r *http.Request) *gomock.Call
+		}{
+			{"/health", func(w http.ResponseWriter, r *http.Request) *gomock.Call {
+				return healthController.EXPECT().ProvideHealth(w, r)
+			}},
+			{"/health/live", func(w http.ResponseWriter, r *http.Request) *gomock.Call {
+				return healthController.EXPECT().ProvideLiveness(w, r)
+			}},
+			{"/health/ready", func(w http.ResponseWriter, r *http.Request) *gomock.Call {
+				return healthController.EXPECT().ProvideReadiness(w, r)
+			}},
+		}
+
+		for _, test := range tests {
+			t.Run(test.path, func(t *testing.T) {
+				// given
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest("GET", test.path, nil)
+				test.expect(w, r).Times(1)
+
+				// when
+				router.ServeHTTP(w, r)
+
+				// then
+				assert.Equal(t, http.StatusOK, w.Code)
+			})
+		}
+	})
+
+	t.Run("/metrics", func(t *testing.T) {
+		t.Run("should not serve the metrics on the API", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/metrics", nil)
+
+			// when
+			router.ServeHTTP(w, r)
+
+			// then
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	})
 }
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -9,12 +11,27 @@ import (
 
 type JwtConfig struct {
 	PrivateKeyPath  string        `env:"PRIVATE_KEY_PATH"`
-	PrivateKey      string        `env:"PRIVATE_KEY"`
+	PrivateKey      string        `env:"PRIVATE_KEY" secret:"true"`
 	PublicKeyPath   string        `env:"PUBLIC_KEY_PATH"`
 	PublicKey       string        `env:"PUBLIC_KEY"`
 	TokenExpiration time.Duration `env:"TOKEN_EXPIRATION,notEmpty"`
 }
 
+// Validate is called by the appconfig-package, every key has to be given directly or as path
+func (config JwtConfig) Validate() error {
+	var problems []string
+	if config.PrivateKey == "" && config.PrivateKeyPath == "" {
+		problems = append(problems, "PRIVATE_KEY or PRIVATE_KEY_PATH must be set")
+	}
+	if config.PublicKey == "" && config.PublicKeyPath == "" {
+		problems = append(problems, "PUBLIC_KEY or PUBLIC_KEY_PATH must be set")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
 func (config JwtConfig) ReadPrivateKey() (any, error) {
 	var bytes []byte
 	if config.PrivateKey != "" {
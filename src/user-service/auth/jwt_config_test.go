@@ -330,4 +330,30 @@ func TestJwtConfig(t *testing.T) {
 			assert.Equal(t, publicKey, key)
 		})
 	})
+	t.Run("Validate", func(t *testing.T) {
+		t.Run("should accept keys and key paths", func(t *testing.T) {
+			// given
+			config := JwtConfig{
+				PrivateKey:    "private",
+				PublicKeyPath: "public",
+			}
+
+			// when
+			err := config.Validate()
+
+			// then
+			assert.NoError(t, err)
+		})
+
+		t.Run("should report every missing key", func(t *testing.T) {
+			// given
+			config := JwtConfig{}
+
+			// when
+			err := config.Validate()
+
+			// then
+			assert.EqualError(t, err, "PRIVATE_KEY or PRIVATE_KEY_PATH must be set, PUBLIC_KEY or PUBLIC_KEY_PATH must be set")
+		})
+	})
 }
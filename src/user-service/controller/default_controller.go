@@ -4,16 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
 
 	auth_middleware "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/auth-middleware"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/crypto"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/problem"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/utils"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/validation"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/auth"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/repository"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/service"
@@ -26,21 +28,19 @@ type contextKey int
 
 const authenticatedUserKey contextKey = 0
 
-type loginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+var errInvalidCredentials = shared_types.NewError(shared_types.Unauthenticated, "INVALID_CREDENTIALS", "email or password is wrong")
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
-type loginResponse struct {
+type LoginResponse struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int    `json:"expires_in"`
 }
 
-func (r *loginRequest) isValid() bool {
-	return r.Email != "" && r.Password != ""
-}
-
 type DefaultController struct {
 	userRepository        repository.Repository
 	service               service.Service
@@ -64,33 +64,28 @@ func NewDefaultController(
 }
 
 func (ctrl *DefaultController) Login(w http.ResponseWriter, r *http.Request) {
-	var request loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.isValid() {
-		w.WriteHeader(http.StatusBadRequest)
+	var request LoginRequest
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	users, err := ctrl.userRepository.FindByEmail(request.Email)
+	users, err := ctrl.userRepository.FindByEmail(r.Context(), request.Email)
 	if err != nil {
-		log.Printf("could not find user by email: %s", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		slog.InfoContext(r.Context(), "could not find the user by email", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	if len(users) < 1 {
 		w.Header().Add("WWW-Authenticate", "Bearer")
-		w.WriteHeader(http.StatusUnauthorized)
+		problem.Write(w, r, errInvalidCredentials)
 		return
 	}
 
 	if ok := ctrl.hasher.Validate([]byte(request.Password), users[0].Password); !ok {
 		w.Header().Add("WWW-Authenticate", "Bearer")
-		w.WriteHeader(http.StatusUnauthorized)
+		problem.Write(w, r, errInvalidCredentials)
 		return
 	}
 
@@ -100,7 +95,7 @@ func (ctrl *DefaultController) Login(w http.ResponseWriter, r *http.Request) {
 		"token_version": users[0].TokenVersion,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -111,7 +106,7 @@ func (ctrl *DefaultController) Login(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -126,58 +121,49 @@ func (ctrl *DefaultController) Login(w http.ResponseWriter, r *http.Request) {
 	http.SetCookie(w, &newCookie)
 
 	w.Header().Add("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(loginResponse{
+	json.NewEncoder(w).Encode(LoginResponse{
 		AccessToken: accessToken,
 		TokenType:   "Bearer",
 		ExpiresIn:   int(ctrl.accessTokenGenerator.GetTokenExpiration().Seconds()),
 	})
 }
 
-type registerRequest struct {
-	Email       string `json:"email"`
-	Password    string `json:"password"`
-	ProfileName string `json:"profileName"`
-}
-
-func (r *registerRequest) isValid() bool {
-	return r.Email != "" && r.Password != "" && r.ProfileName != ""
+type RegisterRequest struct {
+	Email       string `json:"email" validate:"required,email,max=100"`
+	Password    string `json:"password" validate:"required,max=72"`
+	ProfileName string `json:"profileName" validate:"required,max=100"`
 }
 
 func (ctrl *DefaultController) Register(w http.ResponseWriter, r *http.Request) {
-	var request registerRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.isValid() {
-		w.WriteHeader(http.StatusBadRequest)
+	var request RegisterRequest
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	user, err := ctrl.userRepository.FindByEmail(request.Email)
+	user, err := ctrl.userRepository.FindByEmail(r.Context(), request.Email)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	if len(user) > 0 {
-		w.WriteHeader(http.StatusConflict)
+		problem.Write(w, r, shared_types.NewError(shared_types.AlreadyExists, "EMAIL_ALREADY_REGISTERED", "a user with this email already exists"))
 		return
 	}
 
 	hashedPassword, err := ctrl.hasher.Hash([]byte(request.Password))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
-	if err := ctrl.userRepository.Create([]*model.DbUser{{
+	if err := ctrl.userRepository.Create(r.Context(), []*model.DbUser{{
 		Email:       request.Email,
 		Password:    hashedPassword,
 		ProfileName: request.ProfileName,
 	}}); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -189,16 +175,16 @@ func (ctrl *DefaultController) RefreshToken(w http.ResponseWriter, r *http.Reque
 	if ctrl.authIsActive {
 		cookie, err := r.Cookie("refresh_token")
 		if err != nil {
-			log.Println("ERROR [REFRESH_TOKEN - get cookie]: ", err.Error())
-			http.Error(w, "There was no cookie in the request!", http.StatusUnauthorized)
+			slog.InfoContext(r.Context(), "there is no refresh token cookie", "error", err)
+			problem.Write(w, r, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_MISSING", "there was no cookie in the request"))
 			return
 		}
 		token = cookie.Value
 	}
 
-	user, statusCode, err := ctrl.service.ValidateRefreshToken(token)
+	user, err := ctrl.service.ValidateRefreshToken(r.Context(), token)
 	if user == nil {
-		http.Error(w, err.Error(), statusCode.ToHTTPStatusCode())
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -209,7 +195,7 @@ func (ctrl *DefaultController) RefreshToken(w http.ResponseWriter, r *http.Reque
 	})
 
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -220,7 +206,7 @@ func (ctrl *DefaultController) RefreshToken(w http.ResponseWriter, r *http.Reque
 	})
 
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -235,7 +221,7 @@ func (ctrl *DefaultController) RefreshToken(w http.ResponseWriter, r *http.Reque
 	http.SetCookie(w, &newCookie)
 
 	w.Header().Add("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(loginResponse{
+	json.NewEncoder(w).Encode(LoginResponse{
 		AccessToken: accessToken,
 		TokenType:   "Bearer",
 		ExpiresIn:   int(ctrl.accessTokenGenerator.GetTokenExpiration().Seconds()),
@@ -248,8 +234,8 @@ func (ctrl *DefaultController) Logout(w http.ResponseWriter, r *http.Request) {
 	if all != "" {
 		user := r.Context().Value(authenticatedUserKey).(*model.DbUser)
 		newTokenVersion := user.TokenVersion + 1
-		if err := ctrl.userRepository.Update(user.ID, &model.DbUserPatch{TokenVersion: &newTokenVersion}); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+		if err := ctrl.userRepository.Update(r.Context(), user.ID, &model.DbUserPatch{TokenVersion: &newTokenVersion}); err != nil {
+			problem.Write(w, r, err)
 			return
 		}
 	}
@@ -266,15 +252,14 @@ func (ctrl *DefaultController) Logout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (ctrl *DefaultController) GetUsers(w http.ResponseWriter, _ *http.Request) {
-	newUsers, err, _ := ctrl.g.Do("get-users", func() (interface{}, error) {
-		return ctrl.userRepository.FindAll()
+func (ctrl *DefaultController) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := utils.DoShared(r.Context(), ctrl.g, "get-users", func(ctx context.Context) ([]*model.DbUser, error) {
+		return ctrl.userRepository.FindAll(ctx)
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
-	users := newUsers.([]*model.DbUser)
 
 	userDto := utils.Map(users, func(user *model.DbUser) model.UserDTO {
 		return user.ToDto()
@@ -292,39 +277,36 @@ func (ctrl *DefaultController) GetMe(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ctrl *DefaultController) GetUser(w http.ResponseWriter, r *http.Request) {
-	userId := r.Context().Value("userid").(string)
-
-	id, err := strconv.ParseUint(userId, 10, 64)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	id, ok := router.Param[uint64](r, "userid")
+	if !ok {
+		problem.Write(w, r, shared_types.NewError(shared_types.InvalidArgument, "USER_ID_MISSING", "can't find the userId"))
 		return
 	}
 
-	newUser, err, _ := ctrl.g.Do(fmt.Sprintf("user-%d", id), func() (interface{}, error) {
-		return ctrl.userRepository.FindById(id)
+	user, err := utils.DoShared(r.Context(), ctrl.g, fmt.Sprintf("user-%d", id), func(ctx context.Context) (*model.DbUser, error) {
+		return ctrl.userRepository.FindById(ctx, id)
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		problem.Write(w, r, shared_types.WrapNotFound(err, "USER_NOT_FOUND", "can't find the user"))
 		return
 	}
-	user := newUser.(*model.DbUser)
 
 	w.Header().Add("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user.ToDto())
 }
 
-type putMeRequest struct {
-	Password    string `json:"password"`
-	ProfileName string `json:"profileName"`
+type PatchMeRequest struct {
+	Password    string `json:"password" validate:"max=72"`
+	ProfileName string `json:"profileName" validate:"max=100"`
 	Balance     *int64 `json:"balance"`
 }
 
 func (ctrl *DefaultController) PatchMe(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(authenticatedUserKey).(*model.DbUser)
 
-	var request putMeRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	var request PatchMeRequest
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -333,7 +315,7 @@ func (ctrl *DefaultController) PatchMe(w http.ResponseWriter, r *http.Request) {
 	if request.Password != "" {
 		hashedPassword, err := ctrl.hasher.Hash([]byte(request.Password))
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.Write(w, r, err)
 			return
 		}
 		patchUser.Password = &hashedPassword
@@ -345,45 +327,35 @@ func (ctrl *DefaultController) PatchMe(w http.ResponseWriter, r *http.Request) {
 		patchUser.Balance = request.Balance
 	}
 
-	if err := ctrl.userRepository.Update(user.ID, &patchUser); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.userRepository.Update(r.Context(), user.ID, &patchUser); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 }
 
 func (ctrl *DefaultController) DeleteMe(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(authenticatedUserKey).(*model.DbUser)
-	if err := ctrl.userRepository.Delete([]*model.DbUser{user}); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := ctrl.userRepository.Delete(r.Context(), []*model.DbUser{user}); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 }
 
-type validateTokenRequest struct {
-	Token string `json:"token"`
-}
-
-func (r *validateTokenRequest) isValid() bool {
-	return r.Token != ""
+type ValidateTokenRequest struct {
+	Token string `json:"token" validate:"required"`
 }
 
 func (ctrl *DefaultController) ValidateToken(w http.ResponseWriter, r *http.Request) {
-	var request validateTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Println("ERROR [VALIDATE_TOKEN]: ", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.isValid() {
-		log.Println("ERROR [VALIDATE_TOKEN]: ", "is not valid")
-		w.WriteHeader(http.StatusBadRequest)
+	var request ValidateTokenRequest
+	if err := validation.Decode(r, &request); err != nil {
+		slog.InfoContext(r.Context(), "could not validate the token", "error", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	user, statusCode, err := ctrl.service.ValidateAccessToken(request.Token)
+	user, err := ctrl.service.ValidateAccessToken(r.Context(), request.Token)
 	if user == nil {
-		http.Error(w, err.Error(), statusCode.ToHTTPStatusCode())
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -396,19 +368,14 @@ func (ctrl *DefaultController) ValidateToken(w http.ResponseWriter, r *http.Requ
 
 func (ctrl *DefaultController) MoveUserAmount(w http.ResponseWriter, r *http.Request) {
 	var request shared_types.MoveBalanceRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	if !request.IsValid() {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := validation.Decode(r, &request); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	statusCode, err := ctrl.service.MoveUserAmount(request.UserId, request.ReceivingUserId, request.Amount)
+	err := ctrl.service.MoveUserAmount(r.Context(), request.UserId, request.ReceivingUserId, request.Amount)
 	if err != nil {
-		http.Error(w, err.Error(), statusCode.ToHTTPStatusCode())
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -419,11 +386,12 @@ func (ctrl *DefaultController) MoveUserAmount(w http.ResponseWriter, r *http.Req
 
 func (ctrl *DefaultController) AuthenticationMiddleWare(w http.ResponseWriter, r *http.Request, next router.Next) {
 	if !ctrl.authIsActive {
-		user, err := ctrl.userRepository.FindById(1)
+		user, err := ctrl.userRepository.FindById(r.Context(), 1)
 		if err != nil {
-			http.Error(w, "The user doesn't exist anymore", http.StatusUnauthorized)
+			problem.Write(w, r, shared_types.NewError(shared_types.Unauthenticated, "USER_NOT_FOUND", "the user doesn't exist anymore"))
 			return
 		}
+		logger.SetUserID(r.Context(), user.ID)
 		ctx := context.WithValue(r.Context(), authenticatedUserKey, user)
 		next(r.WithContext(ctx))
 		return
@@ -433,15 +401,16 @@ func (ctrl *DefaultController) AuthenticationMiddleWare(w http.ResponseWriter, r
 
 	after, found := strings.CutPrefix(token, "Bearer ")
 	if !found {
-		http.Error(w, "There was no Token provided", http.StatusUnauthorized)
+		problem.Write(w, r, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_MISSING", "there was no token provided"))
 		return
 	}
-	user, statusCode, err := ctrl.service.ValidateAccessToken(after)
+	user, err := ctrl.service.ValidateAccessToken(r.Context(), after)
 	if user == nil {
-		http.Error(w, err.Error(), statusCode.ToHTTPStatusCode())
+		problem.Write(w, r, err)
 		return
 	}
 
+	logger.SetUserID(r.Context(), user.ID)
 	ctx := context.WithValue(r.Context(), authenticatedUserKey, user)
 	next(r.WithContext(ctx))
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	crypto_mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/crypto/_mocks"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/router"
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	mocks "github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/_mocks"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/model"
@@ -41,7 +43,7 @@ func TestDefaultController(t *testing.T) {
 
 				userRepository.
 					EXPECT().
-					FindById(uint64(1)).
+					FindById(gomock.Any(), uint64(1)).
 					Return(nil, errors.New("user not found"))
 
 				// when
@@ -63,7 +65,7 @@ func TestDefaultController(t *testing.T) {
 
 				userRepository.
 					EXPECT().
-					FindById(uint64(1)).
+					FindById(gomock.Any(), uint64(1)).
 					Return(user, nil)
 
 				// when
@@ -87,8 +89,8 @@ func TestDefaultController(t *testing.T) {
 
 				service.
 					EXPECT().
-					ValidateRefreshToken("").
-					Return(nil, shared_types.Unauthenticated, errors.New("token is not valid"))
+					ValidateRefreshToken(gomock.Any(), "").
+					Return(nil, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_INVALID", "token is not valid"))
 
 				// when
 				controller.RefreshToken(w, r)
@@ -141,8 +143,8 @@ func TestDefaultController(t *testing.T) {
 				// when
 				service.
 					EXPECT().
-					ValidateAccessToken("tester").
-					Return(nil, shared_types.Unauthenticated, errors.New("token is not valid"))
+					ValidateAccessToken(gomock.Any(), "tester").
+					Return(nil, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_INVALID", "token is not valid"))
 
 				called := false
 				controller.AuthenticationMiddleWare(w, r, func(r *http.Request) {
@@ -162,8 +164,8 @@ func TestDefaultController(t *testing.T) {
 
 				service.
 					EXPECT().
-					ValidateAccessToken("tester").
-					Return(user, shared_types.OK, nil)
+					ValidateAccessToken(gomock.Any(), "tester").
+					Return(user, nil)
 
 				// when
 				called := false
@@ -237,7 +239,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return(nil, errors.New("could not query database"))
 
 			// when
@@ -254,7 +256,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{}, nil)
 
 			// when
@@ -272,7 +274,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{{
 					Email:    "test@test.com",
 					Password: []byte("hashed password"),
@@ -298,7 +300,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{{
 					Email:    "test@test.com",
 					Password: []byte("hashed password"),
@@ -324,7 +326,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{{
 					Email:    "test@test.com",
 					Password: []byte("hashed password"),
@@ -360,7 +362,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{{
 					Email:    "test@test.com",
 					Password: []byte("hashed password"),
@@ -408,7 +410,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{{
 					Email:    "test@test.com",
 					Password: []byte("hashed password"),
@@ -450,7 +452,7 @@ func TestDefaultController(t *testing.T) {
 
 			// then
 			res := w.Result()
-			var response loginResponse
+			var response LoginResponse
 			err := json.NewDecoder(res.Body).Decode(&response)
 
 			assert.NoError(t, err)
@@ -519,6 +521,27 @@ func TestDefaultController(t *testing.T) {
 			}
 		})
 
+		t.Run("should return 400 BAD REQUEST if payload is invalid", func(t *testing.T) {
+			tests := []io.Reader{
+				strings.NewReader(`{"email":"test","password":"test","profileName":"Toni Tester"}`),
+				strings.NewReader(`{"email":"test@test.com","password":"` + strings.Repeat("a", 73) + `","profileName":"Toni Tester"}`),
+				strings.NewReader(`{"email":"test@test.com","password":"test","profileName":"Toni Tester","balance":100}`),
+			}
+
+			for _, test := range tests {
+				// given
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest("POST", "/api/v1/auth/register", test)
+
+				// when
+				controller.Register(w, r)
+
+				// then
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			}
+		})
+
 		t.Run("should return 500 INTERNAL SERVER ERROR if search for existing user failed", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
@@ -526,7 +549,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return(nil, errors.New("could not query database"))
 
 			// when
@@ -543,7 +566,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{{}}, nil)
 
 			// when
@@ -560,7 +583,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{}, nil)
 
 			hasher.
@@ -582,7 +605,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{}, nil)
 
 			hasher.
@@ -592,7 +615,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Create([]*model.DbUser{{
+				Create(gomock.Any(), []*model.DbUser{{
 					Email:       "test@test.com",
 					Password:    []byte("hashed password"),
 					ProfileName: "Toni Tester",
@@ -613,7 +636,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindByEmail("test@test.com").
+				FindByEmail(gomock.Any(), "test@test.com").
 				Return([]*model.DbUser{}, nil)
 
 			hasher.
@@ -623,7 +646,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Create([]*model.DbUser{{
+				Create(gomock.Any(), []*model.DbUser{{
 					Email:       "test@test.com",
 					Password:    []byte("hashed password"),
 					ProfileName: "Toni Tester",
@@ -662,8 +685,8 @@ func TestDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				ValidateRefreshToken("invalid_token").
-				Return(nil, shared_types.Unauthenticated, errors.New("token is not valid"))
+				ValidateRefreshToken(gomock.Any(), "invalid_token").
+				Return(nil, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_INVALID", "token is not valid"))
 
 			// when
 			controller.RefreshToken(w, r)
@@ -688,8 +711,8 @@ func TestDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				ValidateRefreshToken("valid").
-				Return(user, shared_types.OK, nil)
+				ValidateRefreshToken(gomock.Any(), "valid").
+				Return(user, nil)
 
 			accessTokenGenerator.
 				EXPECT().
@@ -726,8 +749,8 @@ func TestDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				ValidateRefreshToken("valid").
-				Return(user, shared_types.OK, nil)
+				ValidateRefreshToken(gomock.Any(), "valid").
+				Return(user, nil)
 
 			accessTokenGenerator.
 				EXPECT().
@@ -775,8 +798,8 @@ func TestDefaultController(t *testing.T) {
 
 			service.
 				EXPECT().
-				ValidateRefreshToken("valid").
-				Return(user, shared_types.OK, nil)
+				ValidateRefreshToken(gomock.Any(), "valid").
+				Return(user, nil)
 
 			accessTokenGenerator.
 				EXPECT().
@@ -809,7 +832,7 @@ func TestDefaultController(t *testing.T) {
 
 			// then
 			res := w.Result()
-			var response loginResponse
+			var response LoginResponse
 			err := json.NewDecoder(res.Body).Decode(&response)
 
 			assert.NoError(t, err)
@@ -868,7 +891,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Update(uint64(1), patchUser).
+				Update(gomock.Any(), uint64(1), patchUser).
 				Return(errors.New("database error"))
 
 			// when
@@ -899,7 +922,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Update(uint64(1), patchUser).
+				Update(gomock.Any(), uint64(1), patchUser).
 				Return(nil)
 
 			// when
@@ -925,7 +948,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindAll().
+				FindAll(gomock.Any()).
 				Return(nil, errors.New("query failed")).
 				Times(1)
 
@@ -943,7 +966,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				FindAll().
+				FindAll(gomock.Any()).
 				Return([]*model.DbUser{{ID: 999}}, nil).
 				Times(1)
 
@@ -964,11 +987,10 @@ func TestDefaultController(t *testing.T) {
 	})
 
 	t.Run("GetUser", func(t *testing.T) {
-		t.Run("should return 400 BAD REQUEST if query param is not a number", func(t *testing.T) {
+		t.Run("should return 400 BAD REQUEST if the userid is missing", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/users/1", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "userid", "test"))
 
 			// when
 			controller.GetUser(w, r)
@@ -977,16 +999,16 @@ func TestDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, w.Code)
 		})
 
-		t.Run("should return 404 NOT FOUND query failed", func(t *testing.T) {
+		t.Run("should return 404 NOT FOUND if the user doesn't exist", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/users/1", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "userid", "1"))
+			r = router.WithParam(r, "userid", uint64(1))
 
 			userRepository.
 				EXPECT().
-				FindById(uint64(1)).
-				Return(nil, errors.New("database error"))
+				FindById(gomock.Any(), uint64(1)).
+				Return(nil, sql.ErrNoRows)
 
 			// when
 			controller.GetUser(w, r)
@@ -995,16 +1017,34 @@ func TestDefaultController(t *testing.T) {
 			assert.Equal(t, http.StatusNotFound, w.Code)
 		})
 
+		t.Run("should return 500 INTERNAL SERVER ERROR if the query failed", func(t *testing.T) {
+			// given
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/api/v1/users/1", nil)
+			r = router.WithParam(r, "userid", uint64(1))
+
+			userRepository.
+				EXPECT().
+				FindById(gomock.Any(), uint64(1)).
+				Return(nil, errors.New("database error"))
+
+			// when
+			controller.GetUser(w, r)
+
+			// then
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+		})
+
 		t.Run("should return 200 OK and user", func(t *testing.T) {
 			// given
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/api/v1/users/1", nil)
-			r = r.WithContext(context.WithValue(r.Context(), "userid", "1"))
+			r = router.WithParam(r, "userid", uint64(1))
 			id := uint64(1)
 
 			userRepository.
 				EXPECT().
-				FindById(id).
+				FindById(gomock.Any(), id).
 				Return(&model.DbUser{
 					ID:          1,
 					Email:       "test@test.com",
@@ -1106,8 +1146,8 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Update(uint64(1), gomock.Any()).
-				Do(func(_ uint64, user *model.DbUserPatch) {
+				Update(gomock.Any(), uint64(1), gomock.Any()).
+				Do(func(_ context.Context, _ uint64, user *model.DbUserPatch) {
 					assert.Equal(t, "Tino Taster", *user.ProfileName)
 				}).
 				Return(errors.New("database error"))
@@ -1135,8 +1175,8 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Update(uint64(1), gomock.Any()).
-				Do(func(_ uint64, user *model.DbUserPatch) {
+				Update(gomock.Any(), uint64(1), gomock.Any()).
+				Do(func(_ context.Context, _ uint64, user *model.DbUserPatch) {
 					assert.Equal(t, "Tino Taster", *user.ProfileName)
 				}).
 				Return(nil)
@@ -1164,8 +1204,8 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Update(uint64(1), gomock.Any()).
-				Do(func(_ uint64, user *model.DbUserPatch) {
+				Update(gomock.Any(), uint64(1), gomock.Any()).
+				Do(func(_ context.Context, _ uint64, user *model.DbUserPatch) {
 					assert.Equal(t, int64(100), *user.Balance)
 				}).
 				Return(nil)
@@ -1224,8 +1264,8 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Update(uint64(1), gomock.Any()).
-				Do(func(_ uint64, user *model.DbUserPatch) {
+				Update(gomock.Any(), uint64(1), gomock.Any()).
+				Do(func(_ context.Context, _ uint64, user *model.DbUserPatch) {
 					assert.Equal(t, "Tino Taster", *user.ProfileName)
 					assert.Equal(t, []byte("hashed password"), *user.Password)
 				}).
@@ -1255,7 +1295,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Delete([]*model.DbUser{dbUser}).
+				Delete(gomock.Any(), []*model.DbUser{dbUser}).
 				Return(errors.New("database error"))
 
 			// when
@@ -1280,7 +1320,7 @@ func TestDefaultController(t *testing.T) {
 
 			userRepository.
 				EXPECT().
-				Delete([]*model.DbUser{dbUser}).
+				Delete(gomock.Any(), []*model.DbUser{dbUser}).
 				Return(nil)
 
 			// when
@@ -5,7 +5,6 @@ import (
 
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/user-service/proto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/service"
-	"google.golang.org/grpc/status"
 )
 
 type server struct {
@@ -22,9 +21,9 @@ func NewServer(
 }
 
 func (s *server) ValidateToken(ctx context.Context, req *proto.ValidateTokenRequest) (*proto.ValidateTokenResponse, error) {
-	user, statusCode, err := s.service.ValidateAccessToken(req.Token)
-	if user == nil {
-		return nil, status.Error(statusCode.ToGRPCStatusCode(), err.Error())
+	user, err := s.service.ValidateAccessToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
 	}
 
 	response := &proto.ValidateTokenResponse{
@@ -35,9 +34,9 @@ func (s *server) ValidateToken(ctx context.Context, req *proto.ValidateTokenRequ
 }
 
 func (s *server) MoveUserAmount(ctx context.Context, req *proto.MoveUserAmountRequest) (*proto.MoveUserAmountResponse, error) {
-	statusCode, err := s.service.MoveUserAmount(req.UserId, req.ReceivingUserId, req.Amount)
+	err := s.service.MoveUserAmount(ctx, req.UserId, req.ReceivingUserId, req.Amount)
 	if err != nil {
-		return nil, status.Error(statusCode.ToGRPCStatusCode(), err.Error())
+		return nil, err
 	}
 
 	response := &proto.MoveUserAmountResponse{
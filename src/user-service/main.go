@@ -1,22 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net"
-	"net/http"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/crypto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/database"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/grpc/user-service/proto"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/lifecycle"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/tracing"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/api/router"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/auth"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/controller"
 	grpc_server "github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/grpc"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/repository"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/service"
-	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -27,19 +31,38 @@ type ApplicationConfig struct {
 	AccessJwt         auth.JwtConfig      `envPrefix:"JWT_ACCESS_"`
 	RefreshJwt        auth.JwtConfig      `envPrefix:"JWT_REFRESH_"`
 	AuthIsActive      bool                `env:"AUTH_IS_ACTIVE" envDefault:"false"`
-	Port              uint16              `env:"PORT" envDefault:"8080"`
-	GrpcPort          uint16              `env:"GRPC_PORT" envDefault:"8081"`
+	Port              uint16              `env:"PORT" envDefault:"8080" validate:"min=1"`
+	MetricsPort       uint16              `env:"METRICS_PORT" envDefault:"9090" validate:"min=1"`
+	GrpcPort          uint16              `env:"GRPC_PORT" envDefault:"8081" validate:"min=1"`
 	GrpcCommunication bool                `env:"GRPC_COMMUNICATION" envDefault:"true"`
+	HTTP              middleware.Config   `envPrefix:"HTTP_"`
+	Health            health.Config       `envPrefix:"HEALTH_"`
+	Tracing           tracing.Config      `envPrefix:"TRACING_"`
+	Log               logger.Config       `envPrefix:"LOG_"`
+	Lifecycle         lifecycle.Config
 }
 
 func main() {
 	godotenv.Load()
 
 	config := ApplicationConfig{}
-	if err := env.Parse(&config); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	if err := appconfig.Load("user-service", &config); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
 
+	logger.SetDefault(config.Log)
+
+	runner := lifecycle.NewRunner(config.Lifecycle)
+
+	tracerProvider, err := tracing.NewProvider(context.Background(), "user-service", config.Tracing)
+	if err != nil {
+		log.Fatalf("could not create the tracer provider: %s", err.Error())
+	}
+	runner.AddCloser("tracer provider", tracerProvider)
+
+	checker := health.NewChecker(config.Health)
+	runner.OnShutdown(checker.Shutdown)
+
 	accessTokenGenerator, err := auth.NewJwtTokenGenerator(config.AccessJwt)
 	if err != nil {
 		log.Fatalf("could not create JWT access token generator: %s", err.Error())
@@ -54,43 +77,41 @@ func main() {
 	if err != nil {
 		log.Fatalf("could not create user repository: %s", err.Error())
 	}
+	runner.AddCloser("repository", userRepository)
+	if err := metrics.RegisterDBStats("users", userRepository); err != nil {
+		log.Fatalf("could not register the database metrics: %s", err.Error())
+	}
 
-	if err := userRepository.Migrate(); err != nil {
+	if err := userRepository.Migrate(context.Background()); err != nil {
 		log.Fatalf("could not migrate: %s", err.Error())
 	}
 
 	hasher := crypto.NewBcryptHasher()
 
 	service := service.NewDefaultService(userRepository, accessTokenGenerator, refreshTokenGenerator, config.AuthIsActive)
-	healthController := health.NewDefaultController()
 
 	controller := controller.NewDefaultController(userRepository, service, hasher, accessTokenGenerator, refreshTokenGenerator, config.AuthIsActive)
 
-	handler := router.New(controller, healthController)
+	checker.Register("database", userRepository.Ping)
+	healthController := health.NewDefaultController(checker)
 
-	if config.GrpcCommunication {
-		grpcAddr := fmt.Sprintf("0.0.0.0:%d", config.GrpcPort)
-		listener, err := net.Listen("tcp", grpcAddr)
-		if err != nil {
-			log.Fatalf("could not listen: %v", err)
-		}
+	handler := middleware.Default(router.New(controller, healthController), config.HTTP)
 
-		srv := grpc.NewServer()
+	if config.GrpcCommunication {
+		srv := grpc.NewServer(append(metrics.ServerOptions(), tracing.ServerOption(), logger.ServerOption())...)
 		reflection.Register(srv)
 		gprcServer := grpc_server.NewServer(service)
 		proto.RegisterUserServiceServer(srv, gprcServer)
 
-		go func() {
-			log.Printf("GRPC-Server started on Port: %d\n", config.GrpcPort)
-			if err := srv.Serve(listener); err != nil {
-				log.Fatalf("could not serve: %v", err)
-			}
-		}()
+		health.RegisterGRPC(srv, checker)
+
+		runner.AddGRPCServer("GRPC-Server", fmt.Sprintf("0.0.0.0:%d", config.GrpcPort), srv)
 	}
 
-	log.Printf("REST-Server started on Port: %d\n", config.Port)
-	addr := fmt.Sprintf("0.0.0.0:%d", config.Port)
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		log.Fatalf("error while listen and serve: %s", err.Error())
+	runner.AddHTTPServer("REST-Server", fmt.Sprintf("0.0.0.0:%d", config.Port), handler)
+	runner.AddHTTPServer("Metrics-Server", fmt.Sprintf("0.0.0.0:%d", config.MetricsPort), metrics.Handler())
+
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatalf("error while running the servers: %s", err.Error())
 	}
 }
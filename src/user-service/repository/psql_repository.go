@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -16,8 +17,7 @@ type PsqlRepository struct {
 }
 
 func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
-	dsn := config.Dsn()
-	db, err := sql.Open("postgres", dsn)
+	db, err := database.Open("postgres", config)
 	db.SetMaxOpenConns(10)
 	if err != nil {
 		return nil, err
@@ -26,6 +26,21 @@ func NewPsqlRepository(config database.Config) (*PsqlRepository, error) {
 	return &PsqlRepository{db}, nil
 }
 
+// Close closes the connection pool of the repository.
+func (repo *PsqlRepository) Close() error {
+	return repo.db.Close()
+}
+
+// Ping checks that the database can be reached.
+func (repo *PsqlRepository) Ping(ctx context.Context) error {
+	return repo.db.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool for the metrics.
+func (repo *PsqlRepository) Stats() sql.DBStats {
+	return repo.db.Stats()
+}
+
 const createUsersTable = `
 create table if not exists users (
     id				serial primary key, 
@@ -37,8 +52,8 @@ create table if not exists users (
 )
 `
 
-func (repo *PsqlRepository) Migrate() error {
-	_, err := repo.db.Exec(createUsersTable)
+func (repo *PsqlRepository) Migrate(ctx context.Context) error {
+	_, err := repo.db.ExecContext(ctx, createUsersTable)
 	return err
 }
 
@@ -46,7 +61,7 @@ const createUsersBatchQuery = `
 insert into users (email, password, profile_name) values %s
 `
 
-func (repo *PsqlRepository) Create(users []*model.DbUser) error {
+func (repo *PsqlRepository) Create(ctx context.Context, users []*model.DbUser) error {
 	placeholders := make([]string, len(users))
 	values := make([]interface{}, len(users)*3)
 
@@ -58,7 +73,7 @@ func (repo *PsqlRepository) Create(users []*model.DbUser) error {
 	}
 
 	query := fmt.Sprintf(createUsersBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, values...)
+	_, err := repo.db.ExecContext(ctx, query, values...)
 	return err
 }
 
@@ -66,8 +81,8 @@ const updateUserQuery = `
 update users set profile_name = $1, password = $2, balance = $3, token_version = $4 where id = $5 returning id
 `
 
-func (repo *PsqlRepository) Update(id uint64, user *model.DbUserPatch) error {
-	dbUser, err := repo.FindById(id)
+func (repo *PsqlRepository) Update(ctx context.Context, id uint64, user *model.DbUserPatch) error {
+	dbUser, err := repo.FindById(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -84,7 +99,7 @@ func (repo *PsqlRepository) Update(id uint64, user *model.DbUserPatch) error {
 		dbUser.TokenVersion = *user.TokenVersion
 	}
 
-	_, err = repo.db.Exec(updateUserQuery, dbUser.ProfileName, dbUser.Password, dbUser.Balance, dbUser.TokenVersion, dbUser.ID)
+	_, err = repo.db.ExecContext(ctx, updateUserQuery, dbUser.ProfileName, dbUser.Password, dbUser.Balance, dbUser.TokenVersion, dbUser.ID)
 	return err
 }
 
@@ -92,8 +107,8 @@ const findAllUsersQuery = `
 select id, email, password, profile_name, balance, token_version from users
 `
 
-func (repo *PsqlRepository) FindAll() ([]*model.DbUser, error) {
-	rows, err := repo.db.Query(findAllUsersQuery)
+func (repo *PsqlRepository) FindAll(ctx context.Context) ([]*model.DbUser, error) {
+	rows, err := repo.db.QueryContext(ctx, findAllUsersQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -114,8 +129,8 @@ const findUsersByEmailQuery = `
 select id, email, password, profile_name, balance, token_version from users where email = $1
 `
 
-func (repo *PsqlRepository) FindByEmail(email string) ([]*model.DbUser, error) {
-	rows, err := repo.db.Query(findUsersByEmailQuery, email)
+func (repo *PsqlRepository) FindByEmail(ctx context.Context, email string) ([]*model.DbUser, error) {
+	rows, err := repo.db.QueryContext(ctx, findUsersByEmailQuery, email)
 	if err != nil {
 		return nil, err
 	}
@@ -137,8 +152,8 @@ const findUsersByIdQuery = `
 select id, email, password, profile_name, balance, token_version from users where id = $1 LIMIT 1
 `
 
-func (repo *PsqlRepository) FindById(id uint64) (*model.DbUser, error) {
-	row := repo.db.QueryRow(findUsersByIdQuery, id)
+func (repo *PsqlRepository) FindById(ctx context.Context, id uint64) (*model.DbUser, error) {
+	row := repo.db.QueryRowContext(ctx, findUsersByIdQuery, id)
 	user := model.DbUser{}
 	if err := row.Scan(&user.ID, &user.Email, &user.Password, &user.ProfileName, &user.Balance, &user.TokenVersion); err != nil {
 		return nil, err
@@ -150,7 +165,7 @@ const deleteUsersBatchQuery = `
 delete from users where id in (%s)
 `
 
-func (repo *PsqlRepository) Delete(users []*model.DbUser) error {
+func (repo *PsqlRepository) Delete(ctx context.Context, users []*model.DbUser) error {
 	placeholders := make([]string, len(users))
 	ids := make([]interface{}, len(users))
 
@@ -160,6 +175,6 @@ func (repo *PsqlRepository) Delete(users []*model.DbUser) error {
 	}
 
 	query := fmt.Sprintf(deleteUsersBatchQuery, strings.Join(placeholders, ","))
-	_, err := repo.db.Exec(query, ids...)
+	_, err := repo.db.ExecContext(ctx, query, ids...)
 	return err
 }
@@ -44,7 +44,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 
 			// given
 			// when
-			err := repository.Migrate()
+			err := repository.Migrate(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -75,7 +75,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Create(users)
+			err := repository.Create(context.Background(), users)
 
 			// then
 			assert.NoError(t, err)
@@ -104,7 +104,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Update(3, user)
+			err := repository.Update(context.Background(), 3, user)
 
 			// then
 			assert.NoError(t, err)
@@ -143,7 +143,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			users, err := repository.FindAll()
+			users, err := repository.FindAll(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -164,7 +164,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			})
 
 			// when
-			user, err := repository.FindByEmail("test@test.com")
+			user, err := repository.FindByEmail(context.Background(), "test@test.com")
 
 			// then
 			assert.NoError(t, err)
@@ -186,7 +186,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			})
 
 			// when
-			user, err := repository.FindById(7)
+			user, err := repository.FindById(context.Background(), 7)
 
 			// then
 			assert.NoError(t, err)
@@ -221,7 +221,7 @@ func TestIntegrationPsqlRepository(t *testing.T) {
 			}
 
 			// when
-			err := repository.Delete([]*model.DbUser{users[1]})
+			err := repository.Delete(context.Background(), []*model.DbUser{users[1]})
 
 			// then
 			assert.NoError(t, err)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Create(users)
+			err := repository.Create(context.Background(), users)
 
 			// then
 			assert.Error(t, err)
@@ -64,7 +65,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Create(users)
+			err := repository.Create(context.Background(), users)
 
 			// then
 			assert.NoError(t, err)
@@ -88,7 +89,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Update(1, user)
+			err := repository.Update(context.Background(), 1, user)
 
 			// then
 			assert.Error(t, err)
@@ -119,7 +120,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Update(1, user)
+			err := repository.Update(context.Background(), 1, user)
 
 			// then
 			assert.Error(t, err)
@@ -151,7 +152,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(1, 1))
 
 			// when
-			err := repository.Update(1, user)
+			err := repository.Update(context.Background(), 1, user)
 
 			// then
 			assert.NoError(t, err)
@@ -166,7 +167,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			users, err := repository.FindAll()
+			users, err := repository.FindAll(context.Background())
 
 			// then
 			assert.Error(t, err)
@@ -180,7 +181,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(2, "abc@abc.com", []byte("hash"), "ABC ABC", 0, 0))
 
 			// when
-			users, err := repository.FindAll()
+			users, err := repository.FindAll(context.Background())
 
 			// then
 			assert.NoError(t, err)
@@ -201,7 +202,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			users, err := repository.FindByEmail(email)
+			users, err := repository.FindByEmail(context.Background(), email)
 
 			// then
 			assert.Error(t, err)
@@ -218,7 +219,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(1, "test@test.com", []byte("hash"), "Toni Tester", 0, 0))
 
 			// when
-			users, err := repository.FindByEmail(email)
+			users, err := repository.FindByEmail(context.Background(), email)
 
 			// then
 			assert.NoError(t, err)
@@ -236,7 +237,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			users, err := repository.FindById(id)
+			users, err := repository.FindById(context.Background(), id)
 
 			// then
 			assert.Error(t, err)
@@ -253,7 +254,7 @@ func TestPsqlRepository(t *testing.T) {
 					AddRow(1, "test@test.com", []byte("hash"), "Toni Tester", 0, 0))
 
 			// when
-			user, err := repository.FindById(id)
+			user, err := repository.FindById(context.Background(), id)
 
 			// then
 			assert.NoError(t, err)
@@ -286,7 +287,7 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnError(errors.New("database error"))
 
 			// when
-			err := repository.Delete(users)
+			err := repository.Delete(context.Background(), users)
 
 			// then
 			assert.Error(t, err)
@@ -317,10 +318,22 @@ func TestPsqlRepository(t *testing.T) {
 				WillReturnResult(sqlmock.NewResult(0, 2))
 
 			// when
-			err := repository.Delete(users)
+			err := repository.Delete(context.Background(), users)
 
 			// then
 			assert.NoError(t, err)
 		})
 	})
+
+	t.Run("Close", func(t *testing.T) {
+		// given
+		dbmock.ExpectClose()
+
+		// when
+		err := repository.Close()
+
+		// then
+		assert.NoError(t, err)
+		assert.NoError(t, dbmock.ExpectationsWereMet())
+	})
 }
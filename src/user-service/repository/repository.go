@@ -1,13 +1,17 @@
 package repository
 
-import "github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/model"
+import (
+	"context"
+
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/model"
+)
 
 type Repository interface {
-	Migrate() error
-	Create([]*model.DbUser) error
-	FindAll() ([]*model.DbUser, error)
-	FindByEmail(email string) ([]*model.DbUser, error)
-	FindById(id uint64) (*model.DbUser, error)
-	Update(id uint64, user *model.DbUserPatch) error
-	Delete([]*model.DbUser) error
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, users []*model.DbUser) error
+	FindAll(ctx context.Context) ([]*model.DbUser, error)
+	FindByEmail(ctx context.Context, email string) ([]*model.DbUser, error)
+	FindById(ctx context.Context, id uint64) (*model.DbUser, error)
+	Update(ctx context.Context, id uint64, user *model.DbUserPatch) error
+	Delete(ctx context.Context, users []*model.DbUser) error
 }
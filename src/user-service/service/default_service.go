@@ -1,8 +1,8 @@
 package service
 
 import (
-	"errors"
-	"log"
+	"context"
+	"log/slog"
 
 	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/auth"
@@ -31,91 +31,91 @@ func NewDefaultService(
 	}
 }
 
-func (s *DefaultService) validateToken(token string, tokenGenerator auth.TokenGenerator) (*model.DbUser, shared_types.Code, error) {
+func (s *DefaultService) validateToken(ctx context.Context, token string, tokenGenerator auth.TokenGenerator) (*model.DbUser, error) {
 	if !s.authIsActive {
-		user, err := s.repository.FindById(1)
+		user, err := s.repository.FindById(ctx, 1)
 		if err != nil {
-			log.Println("ERROR [tokenVerification - FindById]: ", err.Error())
-			return nil, shared_types.NotFound, errors.New("user not found")
+			slog.ErrorContext(ctx, "could not find the default user", "error", err)
+			return nil, shared_types.WrapNotFound(err, "USER_NOT_FOUND", "user not found")
 		}
-		return user, shared_types.OK, nil
+		return user, nil
 	}
 
 	claims, err := tokenGenerator.VerifyToken(token)
 	if err != nil {
-		log.Println("ERROR [tokenVerification - VerifyToken]: ", err.Error())
-		return nil, shared_types.Unauthenticated, errors.New("token couldn't be verified")
+		slog.InfoContext(ctx, "could not verify the token", "error", err)
+		return nil, shared_types.WrapError(err, shared_types.Unauthenticated, "TOKEN_INVALID", "token couldn't be verified")
 	}
 
 	email, ok := claims["email"].(string)
 	if !ok {
-		log.Println("ERROR [tokenVerification - get email claim]: ", "There is no email claim in your token")
-		return nil, shared_types.Unauthenticated, errors.New("there is no email claim in your token")
+		slog.InfoContext(ctx, "there is no email claim in the token")
+		return nil, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_CLAIM_MISSING", "there is no email claim in your token")
 	}
 
 	tokenV, ok := claims["token_version"].(float64)
 	if !ok {
-		log.Println("ERROR [tokenVerification - get token_version claim]: ", "There is no token_version claim in your token")
-		return nil, shared_types.Unauthenticated, errors.New("there is no token_version claim in your token")
+		slog.InfoContext(ctx, "there is no token_version claim in the token")
+		return nil, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_CLAIM_MISSING", "there is no token_version claim in your token")
 	}
 	tokenVersion := uint64(tokenV)
 
-	users, err := s.repository.FindByEmail(email)
+	users, err := s.repository.FindByEmail(ctx, email)
 	if err != nil {
-		log.Println("ERROR [tokenVerification - FindByEmail]: ", err.Error())
-		return nil, shared_types.Internal, errors.New("internal server error")
+		slog.ErrorContext(ctx, "could not find the user by email", "error", err)
+		return nil, shared_types.WrapError(err, shared_types.Internal, "INTERNAL", "internal server error")
 	}
 
 	if len(users) < 1 {
-		log.Println("ERROR [tokenVerification - len(users) < 1]: ", "Couldn't find user by email")
-		return nil, shared_types.Unauthenticated, errors.New("couldn't find user by email")
+		slog.InfoContext(ctx, "could not find the user of the token")
+		return nil, shared_types.NewError(shared_types.Unauthenticated, "USER_NOT_FOUND", "couldn't find user by email")
 	}
 
 	if users[0].TokenVersion != tokenVersion {
-		log.Println("ERROR [tokenVerification - token version]: ", "The token version is not valid")
-		return nil, shared_types.Unauthenticated, errors.New("the token version is not valid")
+		slog.InfoContext(ctx, "the token version is not valid")
+		return nil, shared_types.NewError(shared_types.Unauthenticated, "TOKEN_VERSION_INVALID", "the token version is not valid")
 	}
 
-	return users[0], shared_types.OK, nil
+	return users[0], nil
 }
 
-func (s *DefaultService) ValidateAccessToken(token string) (*model.DbUser, shared_types.Code, error) {
-	return s.validateToken(token, s.accessTokenGenerator)
+func (s *DefaultService) ValidateAccessToken(ctx context.Context, token string) (*model.DbUser, error) {
+	return s.validateToken(ctx, token, s.accessTokenGenerator)
 }
 
-func (s *DefaultService) ValidateRefreshToken(token string) (*model.DbUser, shared_types.Code, error) {
-	return s.validateToken(token, s.refreshTokenGenerator)
+func (s *DefaultService) ValidateRefreshToken(ctx context.Context, token string) (*model.DbUser, error) {
+	return s.validateToken(ctx, token, s.refreshTokenGenerator)
 }
 
-func (s *DefaultService) MoveUserAmount(payingUserId uint64, receivingUserId uint64, amount int64) (shared_types.Code, error) {
-	payingUser, err := s.repository.FindById(payingUserId)
+func (s *DefaultService) MoveUserAmount(ctx context.Context, payingUserId uint64, receivingUserId uint64, amount int64) error {
+	payingUser, err := s.repository.FindById(ctx, payingUserId)
 	if err != nil {
-		log.Println("ERROR [MoveUserAmount - FindById - Paying]: ", err.Error())
-		return shared_types.NotFound, errors.New("payingUser not found")
+		slog.WarnContext(ctx, "could not find the paying user", "error", err)
+		return shared_types.WrapNotFound(err, "PAYING_USER_NOT_FOUND", "payingUser not found")
 	}
 
-	receivingUser, err := s.repository.FindById(receivingUserId)
+	receivingUser, err := s.repository.FindById(ctx, receivingUserId)
 	if err != nil {
-		log.Println("ERROR [MoveUserAmount - FindById - Receiving]: ", err.Error())
-		return shared_types.NotFound, errors.New("receivingUser not found")
+		slog.WarnContext(ctx, "could not find the receiving user", "error", err)
+		return shared_types.WrapNotFound(err, "RECEIVING_USER_NOT_FOUND", "receivingUser not found")
 	}
 
 	payingUserBalance := payingUser.Balance - amount
 	receivingUserBalance := receivingUser.Balance + amount
 
 	userPatch := &model.DbUserPatch{Balance: &payingUserBalance}
-	err = s.repository.Update(payingUser.ID, userPatch)
+	err = s.repository.Update(ctx, payingUser.ID, userPatch)
 	if err != nil {
-		log.Println("ERROR [MoveUserAmount - Update - Paying]: ", err.Error())
-		return shared_types.Internal, errors.New("internal server error")
+		slog.ErrorContext(ctx, "could not update the balance of the paying user", "error", err)
+		return shared_types.WrapError(err, shared_types.Internal, "INTERNAL", "internal server error")
 	}
 
 	userPatch = &model.DbUserPatch{Balance: &receivingUserBalance}
-	err = s.repository.Update(receivingUser.ID, userPatch)
+	err = s.repository.Update(ctx, receivingUser.ID, userPatch)
 	if err != nil {
-		log.Println("ERROR [MoveUserAmount - Update - Receiving]: ", err.Error())
-		return shared_types.Internal, errors.New("internal server error")
+		slog.ErrorContext(ctx, "could not update the balance of the receiving user", "error", err)
+		return shared_types.WrapError(err, shared_types.Internal, "INTERNAL", "internal server error")
 	}
 
-	return shared_types.OK, nil
+	return nil
 }
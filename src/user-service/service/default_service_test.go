@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -25,16 +27,32 @@ func TestDefaultService(t *testing.T) {
 				// given
 				repository.
 					EXPECT().
-					FindById(uint64(1)).
-					Return(nil, errors.New("Not found"))
+					FindById(gomock.Any(), uint64(1)).
+					Return(nil, sql.ErrNoRows)
 
 				// when
-				user, statusCode, err := service.validateToken("", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.NotFound, statusCode)
+				assert.Equal(t, shared_types.NotFound, shared_types.CodeOf(err))
+			})
+
+			t.Run("return Internal if the repository fails", func(t *testing.T) {
+				// given
+				repository.
+					EXPECT().
+					FindById(gomock.Any(), uint64(1)).
+					Return(nil, errors.New("connection refused"))
+
+				// when
+				user, err := service.validateToken(context.Background(), "", tokenGenerator)
+
+				// then
+				assert.Error(t, err)
+				assert.Nil(t, user)
+				assert.Equal(t, shared_types.Internal, shared_types.CodeOf(err))
 			})
 
 			t.Run("return Ok if user was found", func(t *testing.T) {
@@ -44,16 +62,16 @@ func TestDefaultService(t *testing.T) {
 				}
 				repository.
 					EXPECT().
-					FindById(uint64(1)).
+					FindById(gomock.Any(), uint64(1)).
 					Return(shouldUser, nil)
 
 				// when
-				user, statusCode, err := service.validateToken("", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "", tokenGenerator)
 
 				// then
 				assert.NoError(t, err)
 				assert.Equal(t, shouldUser, user)
-				assert.Equal(t, shared_types.OK, statusCode)
+				assert.Equal(t, shared_types.OK, shared_types.CodeOf(err))
 			})
 		})
 	})
@@ -69,12 +87,12 @@ func TestDefaultService(t *testing.T) {
 					Return(nil, errors.New("Unauthenticated"))
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.Unauthenticated, statusCode)
+				assert.Equal(t, shared_types.Unauthenticated, shared_types.CodeOf(err))
 			})
 
 			t.Run("return Unauthenticated if email claim is missing", func(t *testing.T) {
@@ -87,12 +105,12 @@ func TestDefaultService(t *testing.T) {
 					Return(claims, nil)
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.Unauthenticated, statusCode)
+				assert.Equal(t, shared_types.Unauthenticated, shared_types.CodeOf(err))
 			})
 
 			t.Run("return Unauthenticated if tokenVersion is missing", func(t *testing.T) {
@@ -107,12 +125,12 @@ func TestDefaultService(t *testing.T) {
 					Return(claims, nil)
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.Unauthenticated, statusCode)
+				assert.Equal(t, shared_types.Unauthenticated, shared_types.CodeOf(err))
 			})
 
 			t.Run("return Internal if db-Error accured", func(t *testing.T) {
@@ -129,16 +147,16 @@ func TestDefaultService(t *testing.T) {
 
 				repository.
 					EXPECT().
-					FindByEmail("test@test.com").
+					FindByEmail(gomock.Any(), "test@test.com").
 					Return(nil, errors.New("internal error"))
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.Internal, statusCode)
+				assert.Equal(t, shared_types.Internal, shared_types.CodeOf(err))
 			})
 
 			t.Run("return Unauthenticated if no users were found", func(t *testing.T) {
@@ -157,16 +175,16 @@ func TestDefaultService(t *testing.T) {
 
 				repository.
 					EXPECT().
-					FindByEmail("test@test.com").
+					FindByEmail(gomock.Any(), "test@test.com").
 					Return(users, nil)
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.Unauthenticated, statusCode)
+				assert.Equal(t, shared_types.Unauthenticated, shared_types.CodeOf(err))
 			})
 
 			t.Run("return Unauthenticated if token Version of the user and token are different", func(t *testing.T) {
@@ -190,16 +208,16 @@ func TestDefaultService(t *testing.T) {
 
 				repository.
 					EXPECT().
-					FindByEmail("test@test.com").
+					FindByEmail(gomock.Any(), "test@test.com").
 					Return(users, nil)
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.Error(t, err)
 				assert.Nil(t, user)
-				assert.Equal(t, shared_types.Unauthenticated, statusCode)
+				assert.Equal(t, shared_types.Unauthenticated, shared_types.CodeOf(err))
 			})
 
 			t.Run("return OK if no error accured", func(t *testing.T) {
@@ -223,16 +241,16 @@ func TestDefaultService(t *testing.T) {
 
 				repository.
 					EXPECT().
-					FindByEmail("test@test.com").
+					FindByEmail(gomock.Any(), "test@test.com").
 					Return(users, nil)
 
 				// when
-				user, statusCode, err := service.validateToken("token", tokenGenerator)
+				user, err := service.validateToken(context.Background(), "token", tokenGenerator)
 
 				// then
 				assert.NoError(t, err)
 				assert.Equal(t, users[0], user)
-				assert.Equal(t, shared_types.OK, statusCode)
+				assert.Equal(t, shared_types.OK, shared_types.CodeOf(err))
 			})
 		})
 	})
@@ -245,15 +263,33 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				FindById(payingUserId).
-				Return(nil, errors.New("Not found"))
+				FindById(gomock.Any(), payingUserId).
+				Return(nil, sql.ErrNoRows)
+
+			// when
+			err := service.MoveUserAmount(context.Background(), payingUserId, receivingUserId, 100)
+
+			// then
+			assert.Error(t, err)
+			assert.Equal(t, shared_types.NotFound, shared_types.CodeOf(err))
+		})
+
+		t.Run("return DeadlineExceeded if the repository times out", func(t *testing.T) {
+			// given
+			payingUserId := uint64(1)
+			receivingUserId := uint64(2)
+
+			repository.
+				EXPECT().
+				FindById(gomock.Any(), payingUserId).
+				Return(nil, context.DeadlineExceeded)
 
 			// when
-			statusCode, err := service.MoveUserAmount(payingUserId, receivingUserId, 100)
+			err := service.MoveUserAmount(context.Background(), payingUserId, receivingUserId, 100)
 
 			// then
 			assert.Error(t, err)
-			assert.Equal(t, shared_types.NotFound, statusCode)
+			assert.Equal(t, shared_types.DeadlineExceeded, shared_types.CodeOf(err))
 		})
 
 		t.Run("return NotFound if receivingUser was not Found", func(t *testing.T) {
@@ -268,20 +304,20 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				FindById(payingUserId).
+				FindById(gomock.Any(), payingUserId).
 				Return(payingUser, nil)
 
 			repository.
 				EXPECT().
-				FindById(receivingUserId).
-				Return(nil, errors.New("Not found"))
+				FindById(gomock.Any(), receivingUserId).
+				Return(nil, sql.ErrNoRows)
 
 			// when
-			statusCode, err := service.MoveUserAmount(payingUserId, receivingUserId, 100)
+			err := service.MoveUserAmount(context.Background(), payingUserId, receivingUserId, 100)
 
 			// then
 			assert.Error(t, err)
-			assert.Equal(t, shared_types.NotFound, statusCode)
+			assert.Equal(t, shared_types.NotFound, shared_types.CodeOf(err))
 		})
 
 		t.Run("return Internal if payingUser update error accured", func(t *testing.T) {
@@ -301,25 +337,25 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				FindById(payingUserId).
+				FindById(gomock.Any(), payingUserId).
 				Return(payingUser, nil)
 
 			repository.
 				EXPECT().
-				FindById(receivingUserId).
+				FindById(gomock.Any(), receivingUserId).
 				Return(receivingUser, nil)
 
 			repository.
 				EXPECT().
-				Update(payingUserId, &model.DbUserPatch{Balance: &payingUserNewBalance}).
+				Update(gomock.Any(), payingUserId, &model.DbUserPatch{Balance: &payingUserNewBalance}).
 				Return(errors.New("Internal error"))
 
 			// when
-			statusCode, err := service.MoveUserAmount(payingUserId, receivingUserId, 100)
+			err := service.MoveUserAmount(context.Background(), payingUserId, receivingUserId, 100)
 
 			// then
 			assert.Error(t, err)
-			assert.Equal(t, shared_types.Internal, statusCode)
+			assert.Equal(t, shared_types.Internal, shared_types.CodeOf(err))
 		})
 
 		t.Run("return Internal if receivingUser update error accured", func(t *testing.T) {
@@ -340,30 +376,30 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				FindById(payingUserId).
+				FindById(gomock.Any(), payingUserId).
 				Return(payingUser, nil)
 
 			repository.
 				EXPECT().
-				FindById(receivingUserId).
+				FindById(gomock.Any(), receivingUserId).
 				Return(receivingUser, nil)
 
 			repository.
 				EXPECT().
-				Update(payingUserId, &model.DbUserPatch{Balance: &payingUserNewBalance}).
+				Update(gomock.Any(), payingUserId, &model.DbUserPatch{Balance: &payingUserNewBalance}).
 				Return(nil)
 
 			repository.
 				EXPECT().
-				Update(receivingUserId, &model.DbUserPatch{Balance: &receivingUserNewBalance}).
+				Update(gomock.Any(), receivingUserId, &model.DbUserPatch{Balance: &receivingUserNewBalance}).
 				Return(errors.New("Internal error"))
 
 			// when
-			statusCode, err := service.MoveUserAmount(payingUserId, receivingUserId, 100)
+			err := service.MoveUserAmount(context.Background(), payingUserId, receivingUserId, 100)
 
 			// then
 			assert.Error(t, err)
-			assert.Equal(t, shared_types.Internal, statusCode)
+			assert.Equal(t, shared_types.Internal, shared_types.CodeOf(err))
 		})
 
 		t.Run("return Ok if no error accured", func(t *testing.T) {
@@ -384,30 +420,30 @@ func TestDefaultService(t *testing.T) {
 
 			repository.
 				EXPECT().
-				FindById(payingUserId).
+				FindById(gomock.Any(), payingUserId).
 				Return(payingUser, nil)
 
 			repository.
 				EXPECT().
-				FindById(receivingUserId).
+				FindById(gomock.Any(), receivingUserId).
 				Return(receivingUser, nil)
 
 			repository.
 				EXPECT().
-				Update(payingUserId, &model.DbUserPatch{Balance: &payingUserNewBalance}).
+				Update(gomock.Any(), payingUserId, &model.DbUserPatch{Balance: &payingUserNewBalance}).
 				Return(nil)
 
 			repository.
 				EXPECT().
-				Update(receivingUserId, &model.DbUserPatch{Balance: &receivingUserNewBalance}).
+				Update(gomock.Any(), receivingUserId, &model.DbUserPatch{Balance: &receivingUserNewBalance}).
 				Return(nil)
 
 			// when
-			statusCode, err := service.MoveUserAmount(payingUserId, receivingUserId, 100)
+			err := service.MoveUserAmount(context.Background(), payingUserId, receivingUserId, 100)
 
 			// then
 			assert.NoError(t, err)
-			assert.Equal(t, shared_types.OK, statusCode)
+			assert.Equal(t, shared_types.OK, shared_types.CodeOf(err))
 		})
 	})
 }
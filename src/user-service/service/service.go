@@ -1,12 +1,13 @@
 package service
 
 import (
-	shared_types "github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/shared-types"
+	"context"
+
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/user-service/model"
 )
 
 type Service interface {
-	ValidateAccessToken(token string) (*model.DbUser, shared_types.Code, error)
-	ValidateRefreshToken(token string) (*model.DbUser, shared_types.Code, error)
-	MoveUserAmount(payingUserId uint64, receivingUserId uint64, amount int64) (shared_types.Code, error)
+	ValidateAccessToken(ctx context.Context, token string) (*model.DbUser, error)
+	ValidateRefreshToken(ctx context.Context, token string) (*model.DbUser, error)
+	MoveUserAmount(ctx context.Context, payingUserId uint64, receivingUserId uint64, amount int64) error
 }
@@ -1,27 +1,52 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/appconfig"
 	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/health"
-	"github.com/caarlos0/env/v10"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/lifecycle"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/logger"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/metrics"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/middleware"
+	"github.com/akatranlp/hsfl-master-ai-cloud-engineering/lib/tracing"
 	"github.com/joho/godotenv"
 )
 
 type ApplicationConfig struct {
-	Port uint16 `env:"PORT" envDefault:"8080"`
+	Port        uint16            `env:"PORT" envDefault:"8080" validate:"min=1"`
+	MetricsPort uint16            `env:"METRICS_PORT" envDefault:"9090" validate:"min=1"`
+	HTTP        middleware.Config `envPrefix:"HTTP_"`
+	Health      health.Config     `envPrefix:"HEALTH_"`
+	Tracing     tracing.Config    `envPrefix:"TRACING_"`
+	Log         logger.Config     `envPrefix:"LOG_"`
+	Lifecycle   lifecycle.Config
 }
 
 func main() {
 	godotenv.Load()
 	config := ApplicationConfig{}
-	if err := env.Parse(&config); err != nil {
-		log.Fatalf("Couldn't parse environment %s", err.Error())
+	if err := appconfig.Load("web-service", &config); err != nil {
+		log.Fatalf("Couldn't load the configuration: %s", err.Error())
 	}
 
-	healthController := health.NewDefaultController()
+	logger.SetDefault(config.Log)
+
+	runner := lifecycle.NewRunner(config.Lifecycle)
+
+	tracerProvider, err := tracing.NewProvider(context.Background(), "web-service", config.Tracing)
+	if err != nil {
+		log.Fatalf("could not create the tracer provider: %s", err.Error())
+	}
+	runner.AddCloser("tracer provider", tracerProvider)
+
+	checker := health.NewChecker(config.Health)
+	runner.OnShutdown(checker.Shutdown)
+
+	healthController := health.NewDefaultController(checker)
 
 	router := http.NewServeMux()
 
@@ -30,13 +55,16 @@ func main() {
 		http.ServeFile(w, r, "./dist/vite.svg")
 	})
 	router.HandleFunc("/health", healthController.ProvideHealth)
+	router.HandleFunc("/health/live", healthController.ProvideLiveness)
+	router.HandleFunc("/health/ready", healthController.ProvideReadiness)
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "./dist/index.html")
 	})
 
-	log.Println("Server Started!")
-	addr := fmt.Sprintf("0.0.0.0:%d", config.Port)
-	if err := http.ListenAndServe(addr, router); err != nil {
-		log.Fatalf("error while listen and serve: %s", err.Error())
+	runner.AddHTTPServer("REST-Server", fmt.Sprintf("0.0.0.0:%d", config.Port), middleware.Default(router, config.HTTP))
+	runner.AddHTTPServer("Metrics-Server", fmt.Sprintf("0.0.0.0:%d", config.MetricsPort), metrics.Handler())
+
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatalf("error while running the servers: %s", err.Error())
 	}
 }
@@ -32,8 +32,8 @@ export class BookRepository {
     return response.data;
   }
 
-  async createChapter(chapter: CreateChapter) {
-    const response = await this.apiClient.post<void>(`/books/${chapter.bookid}/chapters`, chapter);
+  async createChapter({ bookid, ...chapter }: CreateChapter) {
+    const response = await this.apiClient.post<void>(`/books/${bookid}/chapters`, chapter);
     return response.data;
   }
   async editChapter(chapter: UpdateChapter, bookId: number, chapterId: number) {